@@ -4,12 +4,60 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github-stars-notify/internal/schedule"
+
 	"gopkg.in/yaml.v3"
 )
 
+// GitHub response cache constants
+const (
+	GitHubCacheMemory  = "memory"
+	GitHubCacheStorage = "storage"
+	GitHubCacheNone    = "none"
+)
+
+// Storage type constants
+const (
+	StorageTypeFile     = "file"
+	StorageTypeSQLite   = "sqlite"
+	StorageTypePostgres = "postgres"
+)
+
+// GitHub.com URLs, used unless an Enterprise Server instance is configured
+const (
+	DefaultGitHubAPIURL = "https://api.github.com"
+	DefaultGitHubWebURL = "https://github.com"
+)
+
+// GitHub backend constants
+const (
+	GitHubBackendREST    = "rest"
+	GitHubBackendGraphQL = "graphql"
+)
+
+// Tracked activity constants
+const (
+	TrackStars    = "stars"
+	TrackForks    = "forks"
+	TrackReleases = "releases"
+)
+
+// Baseline mode constants
+const (
+	BaselineSilent  = "silent"  // Record existing stargazers without notifying
+	BaselineSummary = "summary" // Only announce stars newer than the baseline window
+	BaselineAll     = "all"     // Announce every existing stargazer
+)
+
+// DefaultMilestones are the star counts celebrated when no thresholds are configured
+var DefaultMilestones = []int{100, 500, 1000, 5000, 10000}
+
 // Log level constants
 const (
 	LogLevelDebug = "debug"
@@ -21,6 +69,7 @@ const (
 // Config represents the application configuration
 type Config struct {
 	Repositories  []Repository  `yaml:"repositories"`
+	Followers     []Follower    `yaml:"followers"`
 	Settings      Settings      `yaml:"settings"`
 	GitHub        GitHubConfig  `yaml:"github"`
 	Notifications Notifications `yaml:"notifications"`
@@ -31,25 +80,225 @@ type Config struct {
 
 // Repository represents a GitHub repository to monitor
 type Repository struct {
-	Owner string `yaml:"owner"`
-	Repo  string `yaml:"repo"`
+	Owner    string   `yaml:"owner"`
+	Repo     string   `yaml:"repo"`               // Repository name, or "*" for every repository of the owner
+	Baseline string   `yaml:"baseline,omitempty"` // Overrides settings.baseline for this repository
+	Instance string   `yaml:"instance,omitempty"` // Name of the github.instances entry hosting the repository
+	Track    []string `yaml:"track,omitempty"`    // Activity to watch: "stars", "forks" and/or "releases", stars only by default
+
+	Milestones []int          `yaml:"milestones,omitempty"` // Overrides settings.milestones for this repository
+	Releases   ReleaseOptions `yaml:"releases,omitempty"`   // Which releases to announce when tracking releases
+
+	// When to check the repository, settings.check_interval_minutes by default
+	IntervalMinutes int    `yaml:"interval_minutes,omitempty"` // Check every n minutes
+	Schedule        string `yaml:"schedule,omitempty"`         // Cron expression, such as "0 3 * * *"
+
+	// Filters applied when expanding a wildcard entry
+	Include      []string `yaml:"include,omitempty"`       // Glob patterns the repository name must match
+	Exclude      []string `yaml:"exclude,omitempty"`       // Glob patterns the repository name must not match
+	Topics       []string `yaml:"topics,omitempty"`        // Repository must have at least one of these topics
+	SkipArchived bool     `yaml:"skip_archived,omitempty"` // Ignore archived repositories
+	SkipForks    bool     `yaml:"skip_forks,omitempty"`    // Ignore forked repositories
+}
+
+// WildcardRepo is the repository name that matches every repository of an owner
+const WildcardRepo = "*"
+
+// IsWildcard reports whether the entry expands to several repositories
+func (r Repository) IsWildcard() bool {
+	return r.Repo == WildcardRepo
+}
+
+// Follower represents a user or organization whose new followers are announced
+type Follower struct {
+	Login    string `yaml:"login"`
+	Instance string `yaml:"instance,omitempty"` // Name of the github.instances entry hosting the account
+
+	// When to check the followers, settings.check_interval_minutes by default
+	IntervalMinutes int    `yaml:"interval_minutes,omitempty"` // Check every n minutes
+	Schedule        string `yaml:"schedule,omitempty"`         // Cron expression, such as "0 3 * * *"
+}
+
+// ReleaseOptions selects the releases announced for a repository
+type ReleaseOptions struct {
+	Prereleases bool `yaml:"prereleases"` // Also announce prereleases
+	Drafts      bool `yaml:"drafts"`      // Also announce drafts, only visible to tokens with push access
+}
+
+// Tracks reports whether the given activity (TrackStars, TrackForks, TrackReleases) is watched
+func (r Repository) Tracks(kind string) bool {
+	if len(r.Track) == 0 {
+		return kind == TrackStars
+	}
+	for _, tracked := range r.Track {
+		if tracked == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether a discovered repository passes the entry's filters
+func (r Repository) Matches(name string, topics []string, archived, fork bool) bool {
+	if r.SkipArchived && archived {
+		return false
+	}
+	if r.SkipForks && fork {
+		return false
+	}
+
+	if len(r.Include) > 0 && !matchesAny(r.Include, name) {
+		return false
+	}
+	if matchesAny(r.Exclude, name) {
+		return false
+	}
+
+	if len(r.Topics) > 0 {
+		for _, wanted := range r.Topics {
+			for _, topic := range topics {
+				if topic == wanted {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// matchesAny reports whether name matches any of the glob patterns
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
 }
 
 // Settings contains application settings
 type Settings struct {
-	CheckIntervalMinutes int `yaml:"check_interval_minutes"`
+	CheckIntervalMinutes    int  `yaml:"check_interval_minutes"`
+	IncrementalFetch        bool `yaml:"incremental_fetch"`          // Only fetch the newest stargazer pages between full syncs
+	FullSyncIntervalMinutes int  `yaml:"full_sync_interval_minutes"` // How often stargazers are fully reconciled in incremental mode or when the star count is unchanged
+
+	// Baseline controls notifications for repositories seen for the first time
+	Baseline            string `yaml:"baseline"`              // "silent", "summary" or "all"
+	BaselineWindowHours int    `yaml:"baseline_window_hours"` // In summary mode, only stars newer than this are announced
+
+	DiscoveryRefreshMinutes int `yaml:"discovery_refresh_minutes"` // How often wildcard repository entries are expanded again
+
+	Milestones []int `yaml:"milestones"` // Star counts to celebrate, an empty list disables milestones
+
+	// Repositories answering 404 (deleted or made private) are suspended after
+	// this many consecutive checks, then probed again every SuspendedRecheckHours
+	SuspendAfterNotFound  int `yaml:"suspend_after_not_found"`
+	SuspendedRecheckHours int `yaml:"suspended_recheck_hours"`
+
+	// Repositories are checked concurrently by up to Concurrency workers, fewer
+	// when the GitHub rate limit runs low
+	Concurrency         int `yaml:"concurrency"`
+	CheckTimeoutSeconds int `yaml:"check_timeout_seconds"` // Deadline of a single repository check
+	CycleTimeoutMinutes int `yaml:"cycle_timeout_minutes"` // Deadline of a whole cycle, the check interval by default
 }
 
 // GitHubConfig contains GitHub API configuration
 type GitHubConfig struct {
-	Token   string `yaml:"token"`
-	Timeout int    `yaml:"timeout_seconds"` // HTTP timeout in seconds
+	Token   string    `yaml:"token"`
+	Tokens  []string  `yaml:"tokens"`          // Additional tokens, requests are rotated across all of them
+	Timeout int       `yaml:"timeout_seconds"` // HTTP timeout in seconds
+	Cache   string    `yaml:"cache"`           // "memory", "storage" or "none" for conditional request caching
+	Backend string    `yaml:"backend"`         // "rest" or "graphql" for fetching stargazers
+	App     GitHubApp `yaml:"app"`             // GitHub App authentication, used instead of the token when configured
+	APIURL  string    `yaml:"api_url"`         // API base URL, e.g. https://ghe.example.com/api/v3
+	WebURL  string    `yaml:"web_url"`         // Web base URL for links, derived from api_url when unset
+
+	// Additional GitHub Enterprise Server instances, referenced by repository.instance
+	Instances []GitHubInstance `yaml:"instances"`
+}
+
+// GitHubInstance contains the connection settings of a GitHub instance. Timeout,
+// cache and backend are shared by all instances.
+type GitHubInstance struct {
+	Name   string    `yaml:"name"`
+	APIURL string    `yaml:"api_url"`
+	WebURL string    `yaml:"web_url"`
+	Token  string    `yaml:"token"`
+	Tokens []string  `yaml:"tokens"`
+	App    GitHubApp `yaml:"app"`
+}
+
+// GetTokens returns the personal access tokens of the instance without duplicates
+func (i GitHubInstance) GetTokens() []string {
+	var tokens []string
+	seen := make(map[string]bool)
+	for _, token := range append([]string{i.Token}, i.Tokens...) {
+		token = strings.TrimSpace(token)
+		if token == "" || seen[token] {
+			continue
+		}
+		seen[token] = true
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// deriveWebURL derives the web URL of a GitHub instance from its API URL
+func deriveWebURL(apiURL string) string {
+	apiURL = strings.TrimSuffix(apiURL, "/")
+	if apiURL == DefaultGitHubAPIURL {
+		return DefaultGitHubWebURL
+	}
+
+	// GitHub Enterprise Server serves the API below /api/v3
+	if strings.HasSuffix(apiURL, "/api/v3") {
+		return strings.TrimSuffix(apiURL, "/api/v3")
+	}
+	return apiURL
+}
+
+// GitHubApp contains GitHub App authentication configuration
+type GitHubApp struct {
+	AppID          int64  `yaml:"app_id"`
+	PrivateKey     string `yaml:"private_key"`      // PEM encoded private key
+	PrivateKeyPath string `yaml:"private_key_path"` // Path to the PEM encoded private key
+	InstallationID int64  `yaml:"installation_id"`  // Optional, looked up per owner when unset
+}
+
+// Enabled reports whether GitHub App authentication is configured
+func (a GitHubApp) Enabled() bool {
+	return a.AppID != 0
+}
+
+// LoadPrivateKey returns the PEM encoded private key, reading it from disk if needed
+func (a GitHubApp) LoadPrivateKey() ([]byte, error) {
+	if a.PrivateKey != "" {
+		return []byte(a.PrivateKey), nil
+	}
+
+	data, err := os.ReadFile(a.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read github app private key: %w", err)
+	}
+	return data, nil
 }
 
 // Notifications contains notification configuration
 type Notifications struct {
-	Discord DiscordConfig `yaml:"discord"`
-	Slack   SlackConfig   `yaml:"slack"`
+	Discord         DiscordConfig `yaml:"discord"`
+	Slack           SlackConfig   `yaml:"slack"`
+	NotifyLostStars bool          `yaml:"notify_lost_stars"` // Also report stars that were removed
+	Enrichment      Enrichment    `yaml:"enrichment"`        // Look up stargazer profiles for richer messages
+}
+
+// Enrichment contains stargazer profile enrichment configuration
+type Enrichment struct {
+	Enabled               bool `yaml:"enabled"`
+	CacheTTLHours         int  `yaml:"cache_ttl_hours"`          // How long fetched profiles are reused
+	MaxProfiles           int  `yaml:"max_profiles"`             // Profiles looked up per notification at most
+	MinRateLimitRemaining int  `yaml:"min_rate_limit_remaining"` // Skip lookups below this remaining API quota
 }
 
 // DiscordConfig contains Discord webhook configuration
@@ -67,16 +316,31 @@ type SlackConfig struct {
 
 // ServerConfig contains HTTP server configuration
 type ServerConfig struct {
-	Port         int    `yaml:"port"`
-	ReadTimeout  int    `yaml:"read_timeout_seconds"`
-	WriteTimeout int    `yaml:"write_timeout_seconds"`
-	Host         string `yaml:"host"`
+	Port         int           `yaml:"port"`
+	ReadTimeout  int           `yaml:"read_timeout_seconds"`
+	WriteTimeout int           `yaml:"write_timeout_seconds"`
+	Host         string        `yaml:"host"`
+	Webhook      WebhookConfig `yaml:"webhook"`
+}
+
+// WebhookConfig configures the endpoint receiving GitHub webhook deliveries for
+// star events. Enabled and Secret are reloaded without restart, Path is not.
+type WebhookConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"`   // Path on the metrics server, "/webhook" by default
+	Secret  string `yaml:"secret"` // Secret used to verify X-Hub-Signature-256
 }
 
 // StorageConfig contains storage configuration
 type StorageConfig struct {
-	Type string `yaml:"type"` // "file" for now, extensible for future storage types
-	Path string `yaml:"path"` // Directory path for file storage
+	Type string `yaml:"type"` // "file" (JSON files), "sqlite" or "postgres"
+	Path string `yaml:"path"` // Data directory, also holding the database of the sqlite storage
+	DSN  string `yaml:"dsn"`  // Connection string of the postgres storage
+
+	// Connection pool of the postgres storage
+	MaxOpenConns           int `yaml:"max_open_conns"`            // Maximum open connections
+	MaxIdleConns           int `yaml:"max_idle_conns"`            // Maximum idle connections kept open
+	ConnMaxLifetimeMinutes int `yaml:"conn_max_lifetime_minutes"` // Connections are replaced after this age
 }
 
 // LoggingConfig contains logging configuration
@@ -116,6 +380,37 @@ func (c *Config) applyEnvOverrides() {
 	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
 		c.GitHub.Token = token
 	}
+	if tokens := os.Getenv("GITHUB_TOKENS"); tokens != "" {
+		c.GitHub.Tokens = strings.Split(tokens, ",")
+	}
+	if cache := os.Getenv("GITHUB_CACHE"); cache != "" {
+		c.GitHub.Cache = cache
+	}
+	if backend := os.Getenv("GITHUB_BACKEND"); backend != "" {
+		c.GitHub.Backend = backend
+	}
+	if apiURL := os.Getenv("GITHUB_API_URL"); apiURL != "" {
+		c.GitHub.APIURL = apiURL
+	}
+	if webURL := os.Getenv("GITHUB_WEB_URL"); webURL != "" {
+		c.GitHub.WebURL = webURL
+	}
+	if appID := os.Getenv("GITHUB_APP_ID"); appID != "" {
+		if id, err := strconv.ParseInt(appID, 10, 64); err == nil {
+			c.GitHub.App.AppID = id
+		}
+	}
+	if privateKey := os.Getenv("GITHUB_APP_PRIVATE_KEY"); privateKey != "" {
+		c.GitHub.App.PrivateKey = privateKey
+	}
+	if privateKeyPath := os.Getenv("GITHUB_APP_PRIVATE_KEY_PATH"); privateKeyPath != "" {
+		c.GitHub.App.PrivateKeyPath = privateKeyPath
+	}
+	if installationID := os.Getenv("GITHUB_APP_INSTALLATION_ID"); installationID != "" {
+		if id, err := strconv.ParseInt(installationID, 10, 64); err == nil {
+			c.GitHub.App.InstallationID = id
+		}
+	}
 
 	// Discord configuration
 	if webhookURL := os.Getenv("DISCORD_WEBHOOK_URL"); webhookURL != "" {
@@ -135,6 +430,12 @@ func (c *Config) applyEnvOverrides() {
 	if enabled := os.Getenv("SLACK_ENABLED"); enabled != "" {
 		c.Notifications.Slack.Enabled = enabled == "true"
 	}
+	if lostStars := os.Getenv("NOTIFY_LOST_STARS"); lostStars != "" {
+		c.Notifications.NotifyLostStars = lostStars == "true"
+	}
+	if enrichment := os.Getenv("ENRICHMENT_ENABLED"); enrichment != "" {
+		c.Notifications.Enrichment.Enabled = enrichment == "true"
+	}
 
 	// Server configuration
 	if port := os.Getenv("SERVER_PORT"); port != "" {
@@ -145,11 +446,23 @@ func (c *Config) applyEnvOverrides() {
 	if host := os.Getenv("SERVER_HOST"); host != "" {
 		c.Server.Host = host
 	}
+	if enabled := os.Getenv("WEBHOOK_ENABLED"); enabled != "" {
+		c.Server.Webhook.Enabled = enabled == "true"
+	}
+	if secret := os.Getenv("WEBHOOK_SECRET"); secret != "" {
+		c.Server.Webhook.Secret = secret
+	}
 
 	// Storage configuration
+	if storageType := os.Getenv("STORAGE_TYPE"); storageType != "" {
+		c.Storage.Type = storageType
+	}
 	if path := os.Getenv("STORAGE_PATH"); path != "" {
 		c.Storage.Path = path
 	}
+	if dsn := os.Getenv("STORAGE_DSN"); dsn != "" {
+		c.Storage.DSN = dsn
+	}
 
 	// Logging configuration
 	if level := os.Getenv("LOG_LEVEL"); level != "" {
@@ -165,12 +478,32 @@ func (c *Config) applyEnvOverrides() {
 			c.Settings.CheckIntervalMinutes = i
 		}
 	}
+	if incremental := os.Getenv("INCREMENTAL_FETCH"); incremental != "" {
+		c.Settings.IncrementalFetch = incremental == "true"
+	}
+	if interval := os.Getenv("FULL_SYNC_INTERVAL_MINUTES"); interval != "" {
+		if i, err := strconv.Atoi(interval); err == nil {
+			c.Settings.FullSyncIntervalMinutes = i
+		}
+	}
+	if baseline := os.Getenv("BASELINE"); baseline != "" {
+		c.Settings.Baseline = baseline
+	}
+	if concurrency := os.Getenv("CHECK_CONCURRENCY"); concurrency != "" {
+		if i, err := strconv.Atoi(concurrency); err == nil {
+			c.Settings.Concurrency = i
+		}
+	}
 }
 
 // validate validates the configuration
 func (c *Config) validate() error {
-	if len(c.Repositories) == 0 {
-		return fmt.Errorf("at least one repository must be configured")
+	if len(c.Repositories) == 0 && len(c.Followers) == 0 {
+		return fmt.Errorf("at least one repository or follower account must be configured")
+	}
+
+	if c.Settings.FullSyncIntervalMinutes < 0 {
+		return fmt.Errorf("full sync interval must not be negative")
 	}
 
 	for i, repo := range c.Repositories {
@@ -180,6 +513,119 @@ func (c *Config) validate() error {
 		if repo.Repo == "" {
 			return fmt.Errorf("repository[%d]: repo is required", i)
 		}
+		if repo.Baseline != "" && !isValidBaseline(repo.Baseline) {
+			return fmt.Errorf("repository[%d]: invalid baseline: %s", i, repo.Baseline)
+		}
+		for _, pattern := range append(append([]string{}, repo.Include...), repo.Exclude...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("repository[%d]: invalid pattern %q: %w", i, pattern, err)
+			}
+		}
+		for _, kind := range repo.Track {
+			if kind != TrackStars && kind != TrackForks && kind != TrackReleases {
+				return fmt.Errorf("repository[%d]: invalid track: %s", i, kind)
+			}
+		}
+		if err := validateMilestones(repo.Milestones); err != nil {
+			return fmt.Errorf("repository[%d]: %w", i, err)
+		}
+		if _, ok := c.GetGitHubInstance(repo.Instance); !ok {
+			return fmt.Errorf("repository[%d]: unknown github instance: %s", i, repo.Instance)
+		}
+		if err := validateSchedule(repo.IntervalMinutes, repo.Schedule); err != nil {
+			return fmt.Errorf("repository[%d]: %w", i, err)
+		}
+	}
+
+	for i, follower := range c.Followers {
+		if follower.Login == "" {
+			return fmt.Errorf("followers[%d]: login is required", i)
+		}
+		if _, ok := c.GetGitHubInstance(follower.Instance); !ok {
+			return fmt.Errorf("followers[%d]: unknown github instance: %s", i, follower.Instance)
+		}
+		if err := validateSchedule(follower.IntervalMinutes, follower.Schedule); err != nil {
+			return fmt.Errorf("followers[%d]: %w", i, err)
+		}
+	}
+
+	if c.Settings.Baseline != "" && !isValidBaseline(c.Settings.Baseline) {
+		return fmt.Errorf("invalid baseline: %s", c.Settings.Baseline)
+	}
+
+	if err := validateMilestones(c.Settings.Milestones); err != nil {
+		return err
+	}
+
+	if c.Settings.BaselineWindowHours < 0 {
+		return fmt.Errorf("baseline window must not be negative")
+	}
+
+	if c.Settings.SuspendAfterNotFound < 0 || c.Settings.SuspendedRecheckHours < 0 {
+		return fmt.Errorf("suspension settings must not be negative")
+	}
+
+	if c.Settings.Concurrency < 0 || c.Settings.CheckTimeoutSeconds < 0 || c.Settings.CycleTimeoutMinutes < 0 {
+		return fmt.Errorf("concurrency and timeout settings must not be negative")
+	}
+
+	if c.Notifications.Enrichment.CacheTTLHours < 0 ||
+		c.Notifications.Enrichment.MaxProfiles < 0 ||
+		c.Notifications.Enrichment.MinRateLimitRemaining < 0 {
+		return fmt.Errorf("enrichment settings must not be negative")
+	}
+
+	switch c.Storage.Type {
+	case "", StorageTypeFile, StorageTypeSQLite:
+		// Valid storage types
+	case StorageTypePostgres:
+		if c.Storage.DSN == "" {
+			return fmt.Errorf("storage DSN is required for the postgres storage")
+		}
+	default:
+		return fmt.Errorf("invalid storage type: %s", c.Storage.Type)
+	}
+	if c.Storage.MaxOpenConns < 0 || c.Storage.MaxIdleConns < 0 || c.Storage.ConnMaxLifetimeMinutes < 0 {
+		return fmt.Errorf("storage connection pool settings must not be negative")
+	}
+
+	// Validate GitHub response cache
+	if c.GitHub.Cache != "" {
+		switch c.GitHub.Cache {
+		case GitHubCacheMemory, GitHubCacheStorage, GitHubCacheNone:
+			// Valid caches
+		default:
+			return fmt.Errorf("invalid github cache: %s", c.GitHub.Cache)
+		}
+	}
+
+	// Validate GitHub instances
+	names := make(map[string]bool)
+	for i, instance := range c.GitHub.Instances {
+		if instance.Name == "" {
+			return fmt.Errorf("github instance[%d]: name is required", i)
+		}
+		if names[instance.Name] {
+			return fmt.Errorf("github instance[%d]: duplicate name: %s", i, instance.Name)
+		}
+		names[instance.Name] = true
+		if instance.APIURL == "" {
+			return fmt.Errorf("github instance %s: api_url is required", instance.Name)
+		}
+	}
+
+	for _, instance := range c.GetGitHubInstances() {
+		if err := instance.validate(c.GitHub.Backend); err != nil {
+			return err
+		}
+	}
+
+	// Validate GitHub backend
+	switch c.GitHub.Backend {
+	case "", GitHubBackendREST, GitHubBackendGraphQL:
+		// Valid backends
+	default:
+		return fmt.Errorf("invalid github backend: %s", c.GitHub.Backend)
 	}
 
 	if c.Notifications.Discord.Enabled && c.Notifications.Discord.WebhookURL == "" {
@@ -190,6 +636,18 @@ func (c *Config) validate() error {
 		return fmt.Errorf("slack webhook URL is required when slack notifications are enabled")
 	}
 
+	if c.Server.Webhook.Enabled && c.Server.Webhook.Secret == "" {
+		return fmt.Errorf("webhook secret is required when the webhook endpoint is enabled")
+	}
+	if c.Server.Webhook.Path != "" && !strings.HasPrefix(c.Server.Webhook.Path, "/") {
+		return fmt.Errorf("webhook path must start with /: %s", c.Server.Webhook.Path)
+	}
+	switch c.Server.Webhook.Path {
+	case "/metrics", "/health":
+		// The metrics server already serves these paths
+		return fmt.Errorf("webhook path is reserved: %s", c.Server.Webhook.Path)
+	}
+
 	// Validate logging level
 	if c.Logging.Level != "" {
 		switch c.Logging.Level {
@@ -218,15 +676,60 @@ func (c *Config) setDefaults() {
 	if c.Settings.CheckIntervalMinutes == 0 {
 		c.Settings.CheckIntervalMinutes = 60
 	}
+	if c.Settings.FullSyncIntervalMinutes == 0 {
+		c.Settings.FullSyncIntervalMinutes = 1440
+	}
+	if c.Settings.Baseline == "" {
+		c.Settings.Baseline = BaselineSummary
+	}
+	if c.Settings.DiscoveryRefreshMinutes == 0 {
+		c.Settings.DiscoveryRefreshMinutes = 60
+	}
+	if c.Settings.BaselineWindowHours == 0 {
+		c.Settings.BaselineWindowHours = 24
+	}
+	if c.Settings.Milestones == nil {
+		c.Settings.Milestones = DefaultMilestones
+	}
+	if c.Settings.SuspendAfterNotFound == 0 {
+		c.Settings.SuspendAfterNotFound = 3
+	}
+	if c.Settings.SuspendedRecheckHours == 0 {
+		c.Settings.SuspendedRecheckHours = 24
+	}
+	if c.Settings.Concurrency == 0 {
+		c.Settings.Concurrency = 4
+	}
+	if c.Settings.CheckTimeoutSeconds == 0 {
+		c.Settings.CheckTimeoutSeconds = 300
+	}
 	if c.GitHub.Timeout == 0 {
 		c.GitHub.Timeout = 30
 	}
+	if c.Notifications.Enrichment.CacheTTLHours == 0 {
+		c.Notifications.Enrichment.CacheTTLHours = 168
+	}
+	if c.Notifications.Enrichment.MaxProfiles == 0 {
+		c.Notifications.Enrichment.MaxProfiles = 10
+	}
+	if c.Notifications.Enrichment.MinRateLimitRemaining == 0 {
+		c.Notifications.Enrichment.MinRateLimitRemaining = 500
+	}
+	if c.GitHub.Cache == "" {
+		c.GitHub.Cache = GitHubCacheMemory
+	}
+	if c.GitHub.Backend == "" {
+		c.GitHub.Backend = GitHubBackendREST
+	}
 	if c.Server.Port == 0 {
 		c.Server.Port = 9090
 	}
 	if c.Server.Host == "" {
 		c.Server.Host = "localhost"
 	}
+	if c.Server.Webhook.Path == "" {
+		c.Server.Webhook.Path = "/webhook"
+	}
 	if c.Server.ReadTimeout == 0 {
 		c.Server.ReadTimeout = 30
 	}
@@ -234,11 +737,20 @@ func (c *Config) setDefaults() {
 		c.Server.WriteTimeout = 30
 	}
 	if c.Storage.Type == "" {
-		c.Storage.Type = "file"
+		c.Storage.Type = StorageTypeFile
 	}
 	if c.Storage.Path == "" {
 		c.Storage.Path = "./data"
 	}
+	if c.Storage.MaxOpenConns == 0 {
+		c.Storage.MaxOpenConns = 10
+	}
+	if c.Storage.MaxIdleConns == 0 {
+		c.Storage.MaxIdleConns = 2
+	}
+	if c.Storage.ConnMaxLifetimeMinutes == 0 {
+		c.Storage.ConnMaxLifetimeMinutes = 30
+	}
 	if c.Logging.Level == "" {
 		c.Logging.Level = LogLevelInfo
 	}
@@ -252,11 +764,176 @@ func (c *Config) GetCheckInterval() time.Duration {
 	return time.Duration(c.Settings.CheckIntervalMinutes) * time.Minute
 }
 
+// GetSchedule returns the schedule spec of a repository, as accepted by
+// schedule.Parse: its cron expression, its own interval or the check interval
+func (c *Config) GetSchedule(repo Repository) string {
+	return c.scheduleSpec(repo.IntervalMinutes, repo.Schedule)
+}
+
+// GetFollowerSchedule returns the schedule spec of a follower account, as
+// accepted by schedule.Parse
+func (c *Config) GetFollowerSchedule(follower Follower) string {
+	return c.scheduleSpec(follower.IntervalMinutes, follower.Schedule)
+}
+
+// scheduleSpec returns the cron spec if set, else the interval, else the check interval
+func (c *Config) scheduleSpec(intervalMinutes int, spec string) string {
+	if spec != "" {
+		return spec
+	}
+	if intervalMinutes > 0 {
+		return schedule.EverySpec(time.Duration(intervalMinutes) * time.Minute)
+	}
+	return schedule.EverySpec(c.GetCheckInterval())
+}
+
+// GetFullSyncInterval returns the full reconciliation interval as a time.Duration
+func (c *Config) GetFullSyncInterval() time.Duration {
+	return time.Duration(c.Settings.FullSyncIntervalMinutes) * time.Minute
+}
+
+// GetDiscoveryRefreshInterval returns the wildcard expansion refresh interval as a time.Duration
+func (c *Config) GetDiscoveryRefreshInterval() time.Duration {
+	return time.Duration(c.Settings.DiscoveryRefreshMinutes) * time.Minute
+}
+
+// GetStorageConnMaxLifetime returns the maximum age of storage database connections as a time.Duration
+func (c *Config) GetStorageConnMaxLifetime() time.Duration {
+	return time.Duration(c.Storage.ConnMaxLifetimeMinutes) * time.Minute
+}
+
+// GetBaseline returns the baseline mode for a repository
+func (c *Config) GetBaseline(repo Repository) string {
+	if repo.Baseline != "" {
+		return repo.Baseline
+	}
+	return c.Settings.Baseline
+}
+
+// GetBaselineWindow returns the baseline window as a time.Duration
+func (c *Config) GetBaselineWindow() time.Duration {
+	return time.Duration(c.Settings.BaselineWindowHours) * time.Hour
+}
+
+// GetCheckTimeout returns the deadline of a single repository check as a time.Duration
+func (c *Config) GetCheckTimeout() time.Duration {
+	return time.Duration(c.Settings.CheckTimeoutSeconds) * time.Second
+}
+
+// GetCycleTimeout returns the deadline of a check cycle, which defaults to the
+// check interval so a cycle never overlaps the next one
+func (c *Config) GetCycleTimeout() time.Duration {
+	if c.Settings.CycleTimeoutMinutes == 0 {
+		return c.GetCheckInterval()
+	}
+	return time.Duration(c.Settings.CycleTimeoutMinutes) * time.Minute
+}
+
+// GetSuspendedRecheckInterval returns how often suspended repositories are probed again
+func (c *Config) GetSuspendedRecheckInterval() time.Duration {
+	return time.Duration(c.Settings.SuspendedRecheckHours) * time.Hour
+}
+
+// GetMilestones returns the star counts to celebrate for a repository in ascending order
+func (c *Config) GetMilestones(repo Repository) []int {
+	milestones := c.Settings.Milestones
+	if repo.Milestones != nil {
+		milestones = repo.Milestones
+	}
+
+	sorted := append([]int(nil), milestones...)
+	sort.Ints(sorted)
+	return sorted
+}
+
+// validateSchedule checks the interval and cron schedule of a repository or
+// follower entry, which are mutually exclusive
+func validateSchedule(intervalMinutes int, spec string) error {
+	if intervalMinutes < 0 {
+		return fmt.Errorf("interval must not be negative")
+	}
+	if intervalMinutes > 0 && spec != "" {
+		return fmt.Errorf("interval_minutes and schedule are mutually exclusive")
+	}
+	if spec != "" {
+		if _, err := schedule.Parse(spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateMilestones checks that every milestone is a positive star count
+func validateMilestones(milestones []int) error {
+	for _, milestone := range milestones {
+		if milestone <= 0 {
+			return fmt.Errorf("invalid milestone: %d", milestone)
+		}
+	}
+	return nil
+}
+
+// isValidBaseline checks whether a baseline mode is supported
+func isValidBaseline(baseline string) bool {
+	switch baseline {
+	case BaselineSilent, BaselineSummary, BaselineAll:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetProfileCacheTTL returns how long enriched profiles are cached as a time.Duration
+func (c *Config) GetProfileCacheTTL() time.Duration {
+	return time.Duration(c.Notifications.Enrichment.CacheTTLHours) * time.Hour
+}
+
 // GetGitHubTimeout returns the GitHub API timeout as a time.Duration
 func (c *Config) GetGitHubTimeout() time.Duration {
 	return time.Duration(c.GitHub.Timeout) * time.Second
 }
 
+// GetGitHubTokens returns the personal access tokens of the default instance without duplicates
+func (c *Config) GetGitHubTokens() []string {
+	return c.GetGitHubInstances()[0].GetTokens()
+}
+
+// GetGitHubInstances returns every GitHub instance with its URLs resolved. The
+// first one is the default instance configured at the top level of github.
+func (c *Config) GetGitHubInstances() []GitHubInstance {
+	instances := []GitHubInstance{{
+		APIURL: c.GitHub.APIURL,
+		WebURL: c.GitHub.WebURL,
+		Token:  c.GitHub.Token,
+		Tokens: c.GitHub.Tokens,
+		App:    c.GitHub.App,
+	}}
+	instances = append(instances, c.GitHub.Instances...)
+
+	for i := range instances {
+		if instances[i].APIURL == "" {
+			instances[i].APIURL = DefaultGitHubAPIURL
+		}
+		instances[i].APIURL = strings.TrimSuffix(instances[i].APIURL, "/")
+		if instances[i].WebURL == "" {
+			instances[i].WebURL = deriveWebURL(instances[i].APIURL)
+		}
+		instances[i].WebURL = strings.TrimSuffix(instances[i].WebURL, "/")
+	}
+
+	return instances
+}
+
+// GetGitHubInstance returns the GitHub instance with the given name, "" being the default one
+func (c *Config) GetGitHubInstance(name string) (GitHubInstance, bool) {
+	for _, instance := range c.GetGitHubInstances() {
+		if instance.Name == name {
+			return instance, true
+		}
+	}
+	return GitHubInstance{}, false
+}
+
 // GetServerAddress returns the server address
 func (c *Config) GetServerAddress() string {
 	return fmt.Sprintf("%s:%d", c.Server.Host, c.Server.Port)
@@ -277,3 +954,25 @@ func (c *Config) GetLogLevel() slog.Level {
 		return slog.LevelInfo
 	}
 }
+
+// validate checks the authentication settings of a GitHub instance
+func (i GitHubInstance) validate(backend string) error {
+	prefix := "github"
+	if i.Name != "" {
+		prefix = "github instance " + i.Name + ":"
+	}
+
+	if i.App.Enabled() {
+		if i.App.PrivateKey == "" && i.App.PrivateKeyPath == "" {
+			return fmt.Errorf("%s app private key is required when app_id is set", prefix)
+		}
+	} else if i.App.InstallationID != 0 {
+		return fmt.Errorf("%s app installation_id requires app_id", prefix)
+	}
+
+	if backend == GitHubBackendGraphQL && len(i.GetTokens()) == 0 && !i.App.Enabled() {
+		return fmt.Errorf("%s token or app is required for the graphql backend", prefix)
+	}
+
+	return nil
+}
@@ -80,3 +80,240 @@ notifications:
 		t.Errorf("Expected 60 minute duration, got %v", duration)
 	}
 }
+
+func TestRepositoryMatches(t *testing.T) {
+	repo := Repository{
+		Owner:        "my-org",
+		Repo:         WildcardRepo,
+		Include:      []string{"api-*", "web"},
+		Exclude:      []string{"*-legacy"},
+		Topics:       []string{"oss"},
+		SkipArchived: true,
+		SkipForks:    true,
+	}
+
+	if !repo.IsWildcard() {
+		t.Fatal("Expected wildcard repository")
+	}
+
+	tests := []struct {
+		name     string
+		repo     string
+		topics   []string
+		archived bool
+		fork     bool
+		want     bool
+	}{
+		{"included", "api-users", []string{"oss"}, false, false, true},
+		{"exact include", "web", []string{"go", "oss"}, false, false, true},
+		{"not included", "docs", []string{"oss"}, false, false, false},
+		{"excluded", "api-legacy", []string{"oss"}, false, false, false},
+		{"missing topic", "api-users", []string{"internal"}, false, false, false},
+		{"archived", "api-users", []string{"oss"}, true, false, false},
+		{"fork", "api-users", []string{"oss"}, false, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repo.Matches(tt.repo, tt.topics, tt.archived, tt.fork); got != tt.want {
+				t.Errorf("Matches(%q) = %v, want %v", tt.repo, got, tt.want)
+			}
+		})
+	}
+
+	// Without filters every repository matches
+	all := Repository{Owner: "my-org", Repo: WildcardRepo}
+	if !all.Matches("anything", nil, true, true) {
+		t.Error("Expected unfiltered wildcard to match every repository")
+	}
+}
+
+func TestRepositoryTracks(t *testing.T) {
+	defaults := Repository{Owner: "owner", Repo: "repo"}
+	if !defaults.Tracks(TrackStars) || defaults.Tracks(TrackForks) {
+		t.Error("Expected only stars to be tracked by default")
+	}
+
+	forksOnly := Repository{Owner: "owner", Repo: "repo", Track: []string{TrackForks}}
+	if forksOnly.Tracks(TrackStars) || !forksOnly.Tracks(TrackForks) {
+		t.Error("Expected only forks to be tracked")
+	}
+
+	cfg := &Config{
+		Repositories: []Repository{{Owner: "owner", Repo: "repo", Track: []string{"issues"}}},
+	}
+	if err := cfg.validate(); err == nil {
+		t.Error("Expected error for invalid track value")
+	}
+}
+
+func TestGetMilestones(t *testing.T) {
+	cfg := &Config{}
+	cfg.setDefaults()
+
+	if got := cfg.GetMilestones(Repository{}); len(got) != len(DefaultMilestones) {
+		t.Errorf("Expected default milestones, got %v", got)
+	}
+
+	custom := Repository{Milestones: []int{250, 50}}
+	if got := cfg.GetMilestones(custom); len(got) != 2 || got[0] != 50 || got[1] != 250 {
+		t.Errorf("Expected sorted custom milestones, got %v", got)
+	}
+
+	disabled := Repository{Milestones: []int{}}
+	if got := cfg.GetMilestones(disabled); len(got) != 0 {
+		t.Errorf("Expected milestones to be disabled, got %v", got)
+	}
+
+	if err := validateMilestones([]int{100, 0}); err == nil {
+		t.Error("Expected error for non-positive milestone")
+	}
+}
+
+func TestGetSchedule(t *testing.T) {
+	cfg := &Config{}
+	cfg.setDefaults()
+
+	tests := []struct {
+		repo     Repository
+		expected string
+	}{
+		{Repository{}, "@every 1h0m0s"},
+		{Repository{IntervalMinutes: 5}, "@every 5m0s"},
+		{Repository{Schedule: "0 3 * * *"}, "0 3 * * *"},
+	}
+
+	for _, tt := range tests {
+		if got := cfg.GetSchedule(tt.repo); got != tt.expected {
+			t.Errorf("GetSchedule(%+v) = %q, expected %q", tt.repo, got, tt.expected)
+		}
+	}
+
+	invalid := []Repository{
+		{Owner: "owner", Repo: "repo", IntervalMinutes: -1},
+		{Owner: "owner", Repo: "repo", IntervalMinutes: 5, Schedule: "@daily"},
+		{Owner: "owner", Repo: "repo", Schedule: "every day"},
+	}
+	for _, repo := range invalid {
+		cfg.Repositories = []Repository{repo}
+		if err := cfg.validate(); err == nil {
+			t.Errorf("Expected error for schedule of %+v", repo)
+		}
+	}
+
+	// Follower accounts take the same schedules
+	if got := cfg.GetFollowerSchedule(Follower{Login: "org", IntervalMinutes: 30}); got != "@every 30m0s" {
+		t.Errorf("GetFollowerSchedule = %q, expected the follower interval", got)
+	}
+	cfg.Repositories = nil
+	cfg.Followers = []Follower{{Login: "org", Schedule: "every day"}}
+	if err := cfg.validate(); err == nil {
+		t.Error("Expected error for the schedule of a follower")
+	}
+}
+
+func TestFollowersOnlyConfig(t *testing.T) {
+	cfg := &Config{
+		Followers: []Follower{{Login: "my-org"}},
+	}
+	if err := cfg.validate(); err != nil {
+		t.Errorf("Expected follower-only config to be valid, got %v", err)
+	}
+
+	cfg.Followers = append(cfg.Followers, Follower{})
+	if err := cfg.validate(); err == nil {
+		t.Error("Expected error for follower without login")
+	}
+
+	cfg.Followers = nil
+	if err := cfg.validate(); err == nil {
+		t.Error("Expected error without repositories or followers")
+	}
+}
+
+func TestGitHubInstances(t *testing.T) {
+	cfg := &Config{
+		Repositories: []Repository{
+			{Owner: "facebook", Repo: "react"},
+			{Owner: "platform", Repo: "api", Instance: "corp"},
+		},
+		GitHub: GitHubConfig{
+			Token: "public-token",
+			Instances: []GitHubInstance{
+				{Name: "corp", APIURL: "https://ghe.example.com/api/v3/", Token: "corp-token"},
+			},
+		},
+	}
+
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("Expected valid config, got %v", err)
+	}
+
+	instances := cfg.GetGitHubInstances()
+	if len(instances) != 2 {
+		t.Fatalf("Expected 2 instances, got %d", len(instances))
+	}
+	if instances[0].APIURL != DefaultGitHubAPIURL || instances[0].WebURL != DefaultGitHubWebURL {
+		t.Errorf("Unexpected default instance URLs: %s, %s", instances[0].APIURL, instances[0].WebURL)
+	}
+
+	corp, ok := cfg.GetGitHubInstance("corp")
+	if !ok {
+		t.Fatal("Expected corp instance")
+	}
+	if corp.APIURL != "https://ghe.example.com/api/v3" || corp.WebURL != "https://ghe.example.com" {
+		t.Errorf("Unexpected corp instance URLs: %s, %s", corp.APIURL, corp.WebURL)
+	}
+
+	cfg.Repositories[1].Instance = "unknown"
+	if err := cfg.validate(); err == nil {
+		t.Error("Expected error for unknown instance")
+	}
+}
+
+func TestPostgresStorageConfig(t *testing.T) {
+	cfg := &Config{
+		Repositories: []Repository{{Owner: "owner", Repo: "repo"}},
+		Storage:      StorageConfig{Type: StorageTypePostgres},
+	}
+	if err := cfg.validate(); err == nil {
+		t.Error("Expected error for postgres storage without DSN")
+	}
+
+	cfg.Storage.DSN = "postgres://stars@localhost/stars"
+	if err := cfg.validate(); err != nil {
+		t.Errorf("Expected valid postgres storage config, got %v", err)
+	}
+
+	cfg.Storage.MaxOpenConns = -1
+	if err := cfg.validate(); err == nil {
+		t.Error("Expected error for negative pool size")
+	}
+
+	cfg.Storage.MaxOpenConns = 0
+	cfg.setDefaults()
+	if cfg.Storage.MaxOpenConns != 10 || cfg.GetStorageConnMaxLifetime() != 30*time.Minute {
+		t.Errorf("Unexpected pool defaults: %+v", cfg.Storage)
+	}
+}
+
+func TestWebhookPathConfig(t *testing.T) {
+	cfg := &Config{
+		Repositories: []Repository{{Owner: "owner", Repo: "repo"}},
+	}
+
+	for _, path := range []string{"", "/webhook", "/hooks/github"} {
+		cfg.Server.Webhook.Path = path
+		if err := cfg.validate(); err != nil {
+			t.Errorf("Expected webhook path %q to be valid, got %v", path, err)
+		}
+	}
+
+	// Paths served by the metrics server itself would make the routes collide
+	for _, path := range []string{"webhook", "/metrics", "/health"} {
+		cfg.Server.Webhook.Path = path
+		if err := cfg.validate(); err == nil {
+			t.Errorf("Expected error for webhook path %q", path)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"reflect"
 	"sync"
 	"time"
 
@@ -245,26 +246,93 @@ func (r *Reloader) detectChanges(oldConfig, newConfig *Config) []string {
 		changes = append(changes, "repositories")
 	}
 
+	// Follower account changes
+	if !reflect.DeepEqual(oldConfig.Followers, newConfig.Followers) {
+		changes = append(changes, "followers")
+	}
+
 	// Check interval changes
 	if oldConfig.GetCheckInterval() != newConfig.GetCheckInterval() {
 		changes = append(changes, "check_interval")
 	}
 
+	// Fetch mode changes
+	if oldConfig.Settings.IncrementalFetch != newConfig.Settings.IncrementalFetch ||
+		oldConfig.GetFullSyncInterval() != newConfig.GetFullSyncInterval() {
+		changes = append(changes, "fetch_mode")
+	}
+
+	// Discovery refresh changes
+	if oldConfig.GetDiscoveryRefreshInterval() != newConfig.GetDiscoveryRefreshInterval() {
+		changes = append(changes, "discovery_refresh")
+	}
+
+	// Baseline changes
+	if oldConfig.Settings.Baseline != newConfig.Settings.Baseline ||
+		oldConfig.GetBaselineWindow() != newConfig.GetBaselineWindow() {
+		changes = append(changes, "baseline")
+	}
+
+	// Milestone changes
+	if !reflect.DeepEqual(oldConfig.Settings.Milestones, newConfig.Settings.Milestones) {
+		changes = append(changes, "milestones")
+	}
+
+	// Suspension changes
+	if oldConfig.Settings.SuspendAfterNotFound != newConfig.Settings.SuspendAfterNotFound ||
+		oldConfig.GetSuspendedRecheckInterval() != newConfig.GetSuspendedRecheckInterval() {
+		changes = append(changes, "suspension")
+	}
+
 	// GitHub token changes
-	if oldConfig.GitHub.Token != newConfig.GitHub.Token {
+	if !reflect.DeepEqual(oldConfig.GetGitHubTokens(), newConfig.GetGitHubTokens()) {
 		changes = append(changes, "github_token")
 	}
 
+	// GitHub App changes
+	if oldConfig.GitHub.App != newConfig.GitHub.App {
+		changes = append(changes, "github_app")
+	}
+
+	// GitHub instance changes
+	if oldConfig.GitHub.APIURL != newConfig.GitHub.APIURL ||
+		oldConfig.GitHub.WebURL != newConfig.GitHub.WebURL ||
+		!reflect.DeepEqual(oldConfig.GitHub.Instances, newConfig.GitHub.Instances) {
+		changes = append(changes, "github_instances")
+	}
+
 	// GitHub timeout changes
 	if oldConfig.GetGitHubTimeout() != newConfig.GetGitHubTimeout() {
 		changes = append(changes, "github_timeout")
 	}
 
+	// GitHub cache changes
+	if oldConfig.GitHub.Cache != newConfig.GitHub.Cache {
+		changes = append(changes, "github_cache")
+	}
+
+	// GitHub backend changes
+	if oldConfig.GitHub.Backend != newConfig.GitHub.Backend {
+		changes = append(changes, "github_backend")
+	}
+
 	// Notification changes
 	if !equalNotifications(oldConfig.Notifications, newConfig.Notifications) {
 		changes = append(changes, "notifications")
 	}
 
+	// Concurrency changes
+	if oldConfig.Settings.Concurrency != newConfig.Settings.Concurrency ||
+		oldConfig.GetCheckTimeout() != newConfig.GetCheckTimeout() ||
+		oldConfig.Settings.CycleTimeoutMinutes != newConfig.Settings.CycleTimeoutMinutes {
+		changes = append(changes, "concurrency")
+	}
+
+	// Webhook changes, the path only applies after a restart
+	if oldConfig.Server.Webhook != newConfig.Server.Webhook {
+		changes = append(changes, "webhook")
+	}
+
 	// Log level changes
 	if oldConfig.GetLogLevel() != newConfig.GetLogLevel() {
 		changes = append(changes, "log_level")
@@ -280,7 +348,7 @@ func equalRepositories(a, b []Repository) bool {
 	}
 
 	for i := range a {
-		if a[i].Owner != b[i].Owner || a[i].Repo != b[i].Repo {
+		if !reflect.DeepEqual(a[i], b[i]) {
 			return false
 		}
 	}
@@ -294,5 +362,7 @@ func equalNotifications(a, b Notifications) bool {
 		a.Discord.WebhookURL == b.Discord.WebhookURL &&
 		a.Slack.Enabled == b.Slack.Enabled &&
 		a.Slack.WebhookURL == b.Slack.WebhookURL &&
-		a.Slack.Channel == b.Slack.Channel
+		a.Slack.Channel == b.Slack.Channel &&
+		a.NotifyLostStars == b.NotifyLostStars &&
+		a.Enrichment == b.Enrichment
 }
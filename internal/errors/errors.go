@@ -3,6 +3,7 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 // Error types for different components
@@ -43,6 +44,11 @@ type GitHubAPIError struct {
 	StatusCode int
 	Message    string
 	Err        error
+
+	// Rate limit details parsed from the response, zero when unknown
+	ResetAt    time.Time     // When the exhausted quota resets (X-RateLimit-Reset)
+	RetryAfter time.Duration // How long to wait before retrying (Retry-After)
+	Secondary  bool          // Whether a secondary (abuse) rate limit was hit
 }
 
 func (e *GitHubAPIError) Error() string {
@@ -60,9 +66,34 @@ func (e *GitHubAPIError) Is(target error) bool {
 	return target == ErrGitHubAPI
 }
 
-// IsRateLimited checks if the error is due to rate limiting
+// IsRateLimited checks if the error is due to rate limiting. A 403 only counts
+// when the response carried rate limit information, otherwise it is a permission error.
 func (e *GitHubAPIError) IsRateLimited() bool {
-	return e.StatusCode == 403 || e.StatusCode == 429
+	if e.StatusCode == 429 {
+		return true
+	}
+	return e.StatusCode == 403 && (e.Secondary || !e.ResetAt.IsZero() || e.RetryAfter > 0)
+}
+
+// IsNotFound checks if the resource does not exist or is not visible to the token
+func (e *GitHubAPIError) IsNotFound() bool {
+	return e.StatusCode == 404
+}
+
+// IsRetryable checks if the error is transient: a network failure or a server error
+func (e *GitHubAPIError) IsRetryable() bool {
+	return e.StatusCode == 0 || e.StatusCode >= 500
+}
+
+// WaitDuration returns how long to wait before the request may succeed, or zero when unknown
+func (e *GitHubAPIError) WaitDuration(now time.Time) time.Duration {
+	if e.RetryAfter > 0 {
+		return e.RetryAfter
+	}
+	if !e.ResetAt.IsZero() && e.ResetAt.After(now) {
+		return e.ResetAt.Sub(now)
+	}
+	return 0
 }
 
 // StorageError represents storage-related errors
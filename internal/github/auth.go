@@ -0,0 +1,309 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github-stars-notify/internal/errors"
+)
+
+// TokenSource provides the access token used to authenticate requests for an owner.
+// An empty owner asks for a token suitable for owner-independent endpoints.
+type TokenSource interface {
+	Token(ctx context.Context, owner string) (string, error)
+}
+
+// StaticTokenSource returns the same personal access token for every owner
+type StaticTokenSource string
+
+// Token returns the static token
+func (s StaticTokenSource) Token(ctx context.Context, owner string) (string, error) {
+	return string(s), nil
+}
+
+// installationTokenRefreshMargin is how long before expiry an installation token is renewed
+const installationTokenRefreshMargin = 5 * time.Minute
+
+// AppConfig holds GitHub App authentication configuration
+type AppConfig struct {
+	AppID          int64
+	PrivateKey     []byte // PEM encoded RSA private key
+	InstallationID int64  // Optional fixed installation, looked up per owner otherwise
+	BaseURL        string
+	Timeout        time.Duration
+	UserAgent      string
+}
+
+// AppTokenSource authenticates as a GitHub App installation. It signs a JWT with
+// the app's private key and exchanges it for installation access tokens, which
+// are cached per installation and renewed shortly before they expire.
+type AppTokenSource struct {
+	appID          int64
+	privateKey     *rsa.PrivateKey
+	installationID int64
+	baseURL        string
+	userAgent      string
+	httpClient     *http.Client
+
+	installations map[string]int64 // owner -> installation ID
+	tokens        map[int64]*installationToken
+	mutex         sync.Mutex
+}
+
+// installationToken represents an installation access token
+type installationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewAppTokenSource creates a token source for a GitHub App
+func NewAppTokenSource(cfg AppConfig) (*AppTokenSource, error) {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://api.github.com"
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = "github-stars-notify/1.0"
+	}
+
+	key, err := parsePrivateKey(cfg.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AppTokenSource{
+		appID:          cfg.AppID,
+		privateKey:     key,
+		installationID: cfg.InstallationID,
+		baseURL:        strings.TrimSuffix(cfg.BaseURL, "/"),
+		userAgent:      cfg.UserAgent,
+		httpClient: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		installations: make(map[string]int64),
+		tokens:        make(map[int64]*installationToken),
+	}, nil
+}
+
+// parsePrivateKey parses a PKCS#1 or PKCS#8 PEM encoded RSA private key
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.NewConfigurationError("github.app.private_key",
+			"private key is not PEM encoded", nil)
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.NewConfigurationError("github.app.private_key",
+			"failed to parse private key", err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.NewConfigurationError("github.app.private_key",
+			"private key is not an RSA key", nil)
+	}
+	return key, nil
+}
+
+// Token returns a valid installation access token for owner
+func (a *AppTokenSource) Token(ctx context.Context, owner string) (string, error) {
+	installationID, err := a.installationFor(ctx, owner)
+	if err != nil {
+		return "", err
+	}
+
+	a.mutex.Lock()
+	cached := a.tokens[installationID]
+	a.mutex.Unlock()
+
+	if cached != nil && time.Until(cached.ExpiresAt) > installationTokenRefreshMargin {
+		return cached.Token, nil
+	}
+
+	token, err := a.createInstallationToken(ctx, installationID)
+	if err != nil {
+		return "", err
+	}
+
+	a.mutex.Lock()
+	a.tokens[installationID] = token
+	a.mutex.Unlock()
+
+	return token.Token, nil
+}
+
+// installationFor resolves the installation ID used for owner
+func (a *AppTokenSource) installationFor(ctx context.Context, owner string) (int64, error) {
+	if a.installationID != 0 {
+		return a.installationID, nil
+	}
+
+	a.mutex.Lock()
+	if id, ok := a.installations[owner]; ok {
+		a.mutex.Unlock()
+		return id, nil
+	}
+
+	// Owner-independent endpoints can use any installation we already know
+	if owner == "" {
+		for _, id := range a.installations {
+			a.mutex.Unlock()
+			return id, nil
+		}
+	}
+	a.mutex.Unlock()
+
+	lookup := a.lookupInstallation
+	if owner == "" {
+		lookup = a.firstInstallation
+	}
+	id, err := lookup(ctx, owner)
+	if err != nil {
+		return 0, err
+	}
+
+	a.mutex.Lock()
+	a.installations[owner] = id
+	a.mutex.Unlock()
+
+	return id, nil
+}
+
+// lookupInstallation finds the app installation for an organization, falling
+// back to the user endpoint when owner is not an organization
+func (a *AppTokenSource) lookupInstallation(ctx context.Context, owner string) (int64, error) {
+	var installation struct {
+		ID int64 `json:"id"`
+	}
+
+	err := a.do(ctx, "GET", fmt.Sprintf("/orgs/%s/installation", owner), http.StatusOK, &installation)
+	if gitHubErr, ok := err.(*errors.GitHubAPIError); ok && gitHubErr.StatusCode == http.StatusNotFound {
+		err = a.do(ctx, "GET", fmt.Sprintf("/users/%s/installation", owner), http.StatusOK, &installation)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return installation.ID, nil
+}
+
+// firstInstallation finds an installation of the app for owner-independent
+// endpoints, used before any owner was resolved. owner is ignored.
+func (a *AppTokenSource) firstInstallation(ctx context.Context, owner string) (int64, error) {
+	var installations []struct {
+		ID int64 `json:"id"`
+	}
+
+	endpoint := "/app/installations?per_page=1"
+	if err := a.do(ctx, "GET", endpoint, http.StatusOK, &installations); err != nil {
+		return 0, err
+	}
+	if len(installations) == 0 {
+		return 0, errors.NewGitHubAPIError(endpoint, http.StatusNotFound,
+			"the app is not installed on any account", nil)
+	}
+
+	return installations[0].ID, nil
+}
+
+// createInstallationToken exchanges the app JWT for an installation access token
+func (a *AppTokenSource) createInstallationToken(ctx context.Context, installationID int64) (*installationToken, error) {
+	var token installationToken
+	endpoint := fmt.Sprintf("/app/installations/%d/access_tokens", installationID)
+
+	if err := a.do(ctx, "POST", endpoint, http.StatusCreated, &token); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// do performs an app-authenticated request and decodes the response into result
+func (a *AppTokenSource) do(ctx context.Context, method, endpoint string, expectedStatus int, result interface{}) error {
+	jwt, err := a.signJWT(time.Now())
+	if err != nil {
+		return errors.NewGitHubAPIError(endpoint, 0, "failed to sign app JWT", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+endpoint, nil)
+	if err != nil {
+		return errors.NewGitHubAPIError(endpoint, 0, "failed to create request", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", a.userAgent)
+	req.Header.Set("Authorization", "Bearer "+jwt)
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return errors.NewGitHubAPIError(endpoint, 0, "failed to make request", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatus {
+		return errors.NewGitHubAPIError(endpoint, resp.StatusCode,
+			fmt.Sprintf("API request failed with status %d", resp.StatusCode), nil)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return errors.NewGitHubAPIError(endpoint, resp.StatusCode,
+			"failed to decode response", err)
+	}
+
+	return nil
+}
+
+// signJWT creates the RS256 signed JWT identifying the app. The issue time is
+// backdated to allow for clock drift and the token is valid for 9 minutes.
+func (a *AppTokenSource) signJWT(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-60 * time.Second).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": fmt.Sprintf("%d", a.appID),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(base64.RawURLEncoding.EncodeToString(header))
+	buf.WriteByte('.')
+	buf.WriteString(base64.RawURLEncoding.EncodeToString(claims))
+
+	digest := sha256.Sum256(buf.Bytes())
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	buf.WriteByte('.')
+	buf.WriteString(base64.RawURLEncoding.EncodeToString(signature))
+
+	return buf.String(), nil
+}
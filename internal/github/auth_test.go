@@ -0,0 +1,171 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newAppServer serves the installation endpoints of a GitHub App for the user
+// "octocat" and counts issued installation tokens
+func newAppServer(t *testing.T, key *rsa.PrivateKey, validFor time.Duration, issued *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/app") || strings.HasSuffix(r.URL.Path, "/installation"):
+			if err := verifyJWT(&key.PublicKey, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")); err != nil {
+				t.Errorf("Invalid app JWT on %s: %v", r.URL.Path, err)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		default:
+			if r.Header.Get("Authorization") != fmt.Sprintf("token ghs_%d", *issued) {
+				t.Errorf("Expected installation token, got %q", r.Header.Get("Authorization"))
+			}
+			fmt.Fprint(w, `[]`)
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/orgs/octocat/installation":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/users/octocat/installation":
+			fmt.Fprint(w, `{"id":42}`)
+		case r.URL.Path == "/app/installations" && r.Method == "GET":
+			fmt.Fprint(w, `[{"id":42}]`)
+		case r.URL.Path == "/app/installations/42/access_tokens" && r.Method == "POST":
+			*issued++
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token":"ghs_%d","expires_at":%q}`, *issued,
+				time.Now().Add(validFor).UTC().Format(time.RFC3339))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+// verifyJWT checks the RS256 signature of a JWT
+func verifyJWT(key *rsa.PublicKey, jwt string) error {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("expected 3 parts, got %d", len(parts))
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature)
+}
+
+func newTestPrivateKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	pemKey := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+	return key, pemKey
+}
+
+func TestAppTokenSource(t *testing.T) {
+	key, pemKey := newTestPrivateKey(t)
+
+	issued := 0
+	server := newAppServer(t, key, time.Hour, &issued)
+	defer server.Close()
+
+	tokens, err := NewAppTokenSource(AppConfig{AppID: 1234, PrivateKey: pemKey, BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewAppTokenSource failed: %v", err)
+	}
+
+	client := NewClientWithConfig(Config{BaseURL: server.URL, TokenSource: tokens})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.ListRepositories(context.Background(), "octocat"); err != nil {
+			t.Fatalf("ListRepositories failed: %v", err)
+		}
+	}
+
+	if issued != 1 {
+		t.Errorf("Expected the installation token to be reused, got %d tokens", issued)
+	}
+
+	// Owner-independent requests reuse a known installation
+	token, err := tokens.Token(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if token != "ghs_1" {
+		t.Errorf("Expected ghs_1, got %s", token)
+	}
+}
+
+func TestAppTokenSourceWithoutOwner(t *testing.T) {
+	key, pemKey := newTestPrivateKey(t)
+
+	issued := 0
+	server := newAppServer(t, key, time.Hour, &issued)
+	defer server.Close()
+
+	tokens, err := NewAppTokenSource(AppConfig{AppID: 1234, PrivateKey: pemKey, BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewAppTokenSource failed: %v", err)
+	}
+
+	// Owner-independent requests before any repository use the first installation
+	token, err := tokens.Token(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if token != "ghs_1" {
+		t.Errorf("Expected ghs_1, got %s", token)
+	}
+}
+
+func TestAppTokenSourceRefresh(t *testing.T) {
+	key, pemKey := newTestPrivateKey(t)
+
+	// Tokens expiring within the refresh margin are renewed on every use
+	issued := 0
+	server := newAppServer(t, key, time.Minute, &issued)
+	defer server.Close()
+
+	tokens, err := NewAppTokenSource(AppConfig{AppID: 1234, PrivateKey: pemKey, BaseURL: server.URL, InstallationID: 42})
+	if err != nil {
+		t.Fatalf("NewAppTokenSource failed: %v", err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		token, err := tokens.Token(context.Background(), "octocat")
+		if err != nil {
+			t.Fatalf("Token failed: %v", err)
+		}
+		if token != fmt.Sprintf("ghs_%d", i) {
+			t.Errorf("Expected ghs_%d, got %s", i, token)
+		}
+	}
+}
+
+func TestAppTokenSourceInvalidKey(t *testing.T) {
+	if _, err := NewAppTokenSource(AppConfig{AppID: 1234, PrivateKey: []byte("not a key")}); err == nil {
+		t.Error("Expected error for invalid private key")
+	}
+}
@@ -0,0 +1,112 @@
+package github
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultMemoryCacheEntries is the number of responses kept by NewMemoryCache
+const DefaultMemoryCacheEntries = 1000
+
+// CacheEntry represents a cached GitHub API response used for conditional requests
+type CacheEntry struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Link         string `json:"link,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+// ResponseCache stores responses per URL so unchanged pages can be revalidated
+// with If-None-Match / If-Modified-Since instead of being downloaded again
+type ResponseCache interface {
+	// Get returns the cached entry for a URL
+	Get(key string) (*CacheEntry, bool)
+
+	// Set stores the entry for a URL
+	Set(key string, entry *CacheEntry) error
+}
+
+// CacheObserver receives cache hit and miss events, typically for metrics
+type CacheObserver interface {
+	// RecordGitHubCacheHit records a response served from the cache after a 304
+	RecordGitHubCacheHit(endpoint string)
+
+	// RecordGitHubCacheMiss records a response that had to be downloaded
+	RecordGitHubCacheMiss(endpoint string)
+}
+
+// MemoryCache implements ResponseCache in memory, evicting the least recently
+// used entry once it holds its maximum number of entries
+type MemoryCache struct {
+	maxEntries int
+	entries    map[string]*list.Element
+	recent     *list.List // Of *memoryCacheItem, most recently used first
+	mutex      sync.Mutex
+}
+
+// memoryCacheItem is an entry of a MemoryCache with its key
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewMemoryCache creates a new in-memory response cache holding up to
+// DefaultMemoryCacheEntries entries
+func NewMemoryCache() *MemoryCache {
+	return NewMemoryCacheWithSize(DefaultMemoryCacheEntries)
+}
+
+// NewMemoryCacheWithSize creates a new in-memory response cache holding up to
+// maxEntries entries
+func NewMemoryCacheWithSize(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMemoryCacheEntries
+	}
+
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		recent:     list.New(),
+	}
+}
+
+// Get returns the cached entry for a URL
+func (mc *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	element, ok := mc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	mc.recent.MoveToFront(element)
+	return element.Value.(*memoryCacheItem).entry, true
+}
+
+// Set stores the entry for a URL
+func (mc *MemoryCache) Set(key string, entry *CacheEntry) error {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	if element, ok := mc.entries[key]; ok {
+		element.Value.(*memoryCacheItem).entry = entry
+		mc.recent.MoveToFront(element)
+		return nil
+	}
+
+	mc.entries[key] = mc.recent.PushFront(&memoryCacheItem{key: key, entry: entry})
+	if mc.recent.Len() > mc.maxEntries {
+		oldest := mc.recent.Back()
+		mc.recent.Remove(oldest)
+		delete(mc.entries, oldest.Value.(*memoryCacheItem).key)
+	}
+	return nil
+}
+
+// Len returns the number of cached entries
+func (mc *MemoryCache) Len() int {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	return mc.recent.Len()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"regexp"
 	"strconv"
@@ -13,20 +14,68 @@ import (
 	"github-stars-notify/internal/errors"
 )
 
+// API defines the GitHub operations used by the service, implemented by the REST
+// Client and the GraphQLClient
+type API interface {
+	// GetStargazers fetches all stargazers for a repository, oldest first
+	GetStargazers(ctx context.Context, owner, repo string) ([]Stargazer, error)
+
+	// GetStargazersIncremental fetches only the stargazers missing from knownIDs, oldest first
+	GetStargazersIncremental(ctx context.Context, owner, repo string, knownIDs map[int64]bool) ([]Stargazer, error)
+
+	// GetRateLimit fetches the current rate limit status of the API fetching stargazers
+	GetRateLimit(ctx context.Context) (*RateLimit, error)
+
+	// GetCoreRateLimit fetches the current rate limit status of the REST API,
+	// which serves the operations without a GraphQL implementation
+	GetCoreRateLimit(ctx context.Context) (*RateLimit, error)
+
+	// ListRepositories lists the repositories of an organization or user
+	ListRepositories(ctx context.Context, owner string) ([]Repository, error)
+
+	// GetRepository fetches a repository by name, following renames and transfers
+	GetRepository(ctx context.Context, owner, repo string) (*Repository, error)
+
+	// GetRepositoryByID fetches a repository by its numeric ID. owner selects the
+	// token used for the request.
+	GetRepositoryByID(ctx context.Context, owner string, id int64) (*Repository, error)
+
+	// GetForks fetches all forks of a repository, oldest first
+	GetForks(ctx context.Context, owner, repo string) ([]Repository, error)
+
+	// GetReleases fetches the most recent releases of a repository, newest first
+	GetReleases(ctx context.Context, owner, repo string) ([]Release, error)
+
+	// GetFollowers fetches all followers of a user or organization
+	GetFollowers(ctx context.Context, login string) ([]Stargazer, error)
+
+	// GetUser fetches the public profile of a user
+	GetUser(ctx context.Context, login string) (*UserProfile, error)
+
+	// ResponseCache returns the response cache used for conditional requests, if any
+	ResponseCache() ResponseCache
+}
+
 // Client represents a GitHub API client
 type Client struct {
-	httpClient *http.Client
-	baseURL    string
-	token      string
-	userAgent  string
+	httpClient    *http.Client
+	baseURL       string
+	token         string
+	tokenSource   TokenSource
+	userAgent     string
+	cache         ResponseCache
+	cacheObserver CacheObserver
 }
 
 // Config holds GitHub client configuration
 type Config struct {
-	Token     string
-	BaseURL   string
-	Timeout   time.Duration
-	UserAgent string
+	Token         string
+	TokenSource   TokenSource // Optional, takes precedence over Token (e.g. GitHub App installations)
+	BaseURL       string
+	Timeout       time.Duration
+	UserAgent     string
+	Cache         ResponseCache // Optional cache enabling conditional requests
+	CacheObserver CacheObserver // Optional observer for cache hits and misses
 }
 
 // Stargazer represents a GitHub user who starred a repository
@@ -35,7 +84,11 @@ type Stargazer struct {
 	ID        int64     `json:"id"`
 	NodeID    string    `json:"node_id"`
 	AvatarURL string    `json:"avatar_url"`
+	HTMLURL   string    `json:"html_url,omitempty"`
 	StarredAt time.Time `json:"starred_at"`
+
+	// Profile is set by profile enrichment before notifying, it is not part of API responses
+	Profile *UserProfile `json:"profile,omitempty"`
 }
 
 // APIResponse represents the API response structure
@@ -48,6 +101,7 @@ type APIResponse struct {
 
 // RateLimit represents GitHub API rate limit information
 type RateLimit struct {
+	Resource  string
 	Limit     int
 	Remaining int
 	Reset     time.Time
@@ -81,9 +135,50 @@ func NewClientWithConfig(cfg Config) *Client {
 		httpClient: &http.Client{
 			Timeout: cfg.Timeout,
 		},
-		baseURL:   cfg.BaseURL,
-		token:     cfg.Token,
-		userAgent: cfg.UserAgent,
+		baseURL:       cfg.BaseURL,
+		token:         cfg.Token,
+		tokenSource:   cfg.TokenSource,
+		userAgent:     cfg.UserAgent,
+		cache:         cfg.Cache,
+		cacheObserver: cfg.CacheObserver,
+	}
+}
+
+// ResponseCache returns the response cache used for conditional requests, if any
+func (c *Client) ResponseCache() ResponseCache {
+	return c.cache
+}
+
+// authorize adds the authorization header for requests concerning owner and
+// returns the token that was used
+func (c *Client) authorize(ctx context.Context, req *http.Request, owner, scheme string) (string, error) {
+	token := c.token
+	if c.tokenSource != nil {
+		var err error
+		if token, err = c.tokenSource.Token(ctx, owner); err != nil {
+			// Keep API errors such as an exhausted token pool recognizable
+			if _, ok := err.(*errors.GitHubAPIError); ok {
+				return "", err
+			}
+			return "", errors.NewGitHubAPIError(req.URL.Path, 0, "failed to obtain access token", err)
+		}
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", scheme+" "+token)
+	}
+	return token, nil
+}
+
+// trackRateLimit reports the quota left on token to the token source, if it tracks quotas
+func (c *Client) trackRateLimit(token string, header http.Header) {
+	tracker, ok := c.tokenSource.(RateLimitTracker)
+	if !ok || token == "" {
+		return
+	}
+
+	if rateLimit, ok := parseRateLimitHeaders(header); ok {
+		tracker.TrackRateLimit(token, rateLimit)
 	}
 }
 
@@ -93,17 +188,17 @@ func (c *Client) GetStargazers(ctx context.Context, owner, repo string) ([]Starg
 	page := 1
 
 	for {
-		stargazers, nextPage, err := c.getStargazersPage(ctx, owner, repo, page)
+		stargazers, links, err := c.getStargazersPage(ctx, owner, repo, page)
 		if err != nil {
 			return nil, err
 		}
 
 		allStargazers = append(allStargazers, stargazers...)
 
-		if nextPage == 0 {
+		if links.Next == 0 {
 			break
 		}
-		page = nextPage
+		page = links.Next
 
 		// Check if context is cancelled
 		if ctx.Err() != nil {
@@ -114,34 +209,96 @@ func (c *Client) GetStargazers(ctx context.Context, owner, repo string) ([]Starg
 	return allStargazers, nil
 }
 
-// getStargazersPage fetches a single page of stargazers
-func (c *Client) getStargazersPage(ctx context.Context, owner, repo string, page int) ([]Stargazer, int, error) {
-	endpoint := fmt.Sprintf("/repos/%s/%s/stargazers", owner, repo)
-	url := fmt.Sprintf("%s%s?page=%d&per_page=100", c.baseURL, endpoint, page)
+// GetStargazersIncremental fetches only the stargazers that are not present in knownIDs.
+// GitHub returns stargazers oldest-first, so the newest ones live on the last pages.
+// Starting from the page referenced by rel="last", pages are walked backwards until
+// one of them contains an already known stargazer. The result is ordered oldest-first.
+func (c *Client) GetStargazersIncremental(ctx context.Context, owner, repo string, knownIDs map[int64]bool) ([]Stargazer, error) {
+	// Without known stargazers there is nothing to stop at
+	if len(knownIDs) == 0 {
+		return c.GetStargazers(ctx, owner, repo)
+	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	// The first page is needed anyway to discover the last page number
+	firstPage, links, err := c.getStargazersPage(ctx, owner, repo, 1)
 	if err != nil {
-		return nil, 0, errors.NewGitHubAPIError(endpoint, 0, "failed to create request", err)
+		return nil, err
 	}
 
-	// Request stargazer data with timestamps
-	req.Header.Set("Accept", "application/vnd.github.v3.star+json")
-	req.Header.Set("User-Agent", c.userAgent)
+	if links.Last == 0 {
+		return filterUnknownStargazers(firstPage, knownIDs), nil
+	}
+
+	// Walk backwards from the last page, collecting pages newest-first
+	var pages [][]Stargazer
+	reachedKnown := false
+	for page := links.Last; page > 1; page-- {
+		// Check if context is cancelled
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		stargazers, _, err := c.getStargazersPage(ctx, owner, repo, page)
+		if err != nil {
+			return nil, err
+		}
 
-	// Add authorization header if token is provided
-	if c.token != "" {
-		req.Header.Set("Authorization", "token "+c.token)
+		pages = append(pages, stargazers)
+		if containsKnownStargazer(stargazers, knownIDs) {
+			reachedKnown = true
+			break
+		}
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, 0, errors.NewGitHubAPIError(endpoint, 0, "failed to make request", err)
+	// Every page after the first one was new, so the first page may hold new stargazers too
+	if !reachedKnown {
+		pages = append(pages, firstPage)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, 0, errors.NewGitHubAPIError(endpoint, resp.StatusCode,
-			fmt.Sprintf("API request failed with status %d", resp.StatusCode), nil)
+	var newStargazers []Stargazer
+	for i := len(pages) - 1; i >= 0; i-- {
+		newStargazers = append(newStargazers, filterUnknownStargazers(pages[i], knownIDs)...)
+	}
+
+	return newStargazers, nil
+}
+
+// containsKnownStargazer reports whether any of the stargazers is in knownIDs
+func containsKnownStargazer(stargazers []Stargazer, knownIDs map[int64]bool) bool {
+	for _, sg := range stargazers {
+		if knownIDs[sg.ID] {
+			return true
+		}
+	}
+	return false
+}
+
+// filterUnknownStargazers returns the stargazers that are not in knownIDs
+func filterUnknownStargazers(stargazers []Stargazer, knownIDs map[int64]bool) []Stargazer {
+	var result []Stargazer
+	for _, sg := range stargazers {
+		if !knownIDs[sg.ID] {
+			result = append(result, sg)
+		}
+	}
+	return result
+}
+
+// pageLinks holds the page numbers extracted from a Link header
+type pageLinks struct {
+	Next int
+	Last int
+}
+
+// getStargazersPage fetches a single page of stargazers
+func (c *Client) getStargazersPage(ctx context.Context, owner, repo string, page int) ([]Stargazer, pageLinks, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/stargazers", owner, repo)
+	url := fmt.Sprintf("%s%s?page=%d&per_page=100", c.baseURL, endpoint, page)
+
+	// Request stargazer data with timestamps
+	resp, err := c.get(ctx, owner, "stargazers", endpoint, url, "application/vnd.github.v3.star+json")
+	if err != nil {
+		return nil, pageLinks{}, err
 	}
 
 	var stargazers []struct {
@@ -149,8 +306,8 @@ func (c *Client) getStargazersPage(ctx context.Context, owner, repo string, page
 		User      Stargazer `json:"user"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&stargazers); err != nil {
-		return nil, 0, errors.NewGitHubAPIError(endpoint, resp.StatusCode,
+	if err := json.Unmarshal(resp.Body, &stargazers); err != nil {
+		return nil, pageLinks{}, errors.NewGitHubAPIError(endpoint, resp.StatusCode,
 			"failed to decode response", err)
 	}
 
@@ -162,9 +319,13 @@ func (c *Client) getStargazersPage(ctx context.Context, owner, repo string, page
 	}
 
 	// Parse Link header for pagination
-	nextPage := c.parseNextPage(resp.Header.Get("Link"))
+	linkHeader := resp.Link
+	links := pageLinks{
+		Next: c.parseNextPage(linkHeader),
+		Last: c.parseLastPage(linkHeader),
+	}
 
-	return result, nextPage, nil
+	return result, links, nil
 }
 
 // GetRateLimit fetches the current rate limit status with context support
@@ -172,27 +333,9 @@ func (c *Client) GetRateLimit(ctx context.Context) (*RateLimit, error) {
 	endpoint := "/rate_limit"
 	url := fmt.Sprintf("%s%s", c.baseURL, endpoint)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	resp, err := c.get(ctx, "", "rate_limit", endpoint, url, "")
 	if err != nil {
-		return nil, errors.NewGitHubAPIError(endpoint, 0, "failed to create request", err)
-	}
-
-	req.Header.Set("User-Agent", c.userAgent)
-
-	// Add authorization header if token is provided
-	if c.token != "" {
-		req.Header.Set("Authorization", "token "+c.token)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, errors.NewGitHubAPIError(endpoint, 0, "failed to make request", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.NewGitHubAPIError(endpoint, resp.StatusCode,
-			fmt.Sprintf("API request failed with status %d", resp.StatusCode), nil)
+		return nil, err
 	}
 
 	var rateLimitResp struct {
@@ -203,131 +346,169 @@ func (c *Client) GetRateLimit(ctx context.Context) (*RateLimit, error) {
 		} `json:"rate"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&rateLimitResp); err != nil {
+	if err := json.Unmarshal(resp.Body, &rateLimitResp); err != nil {
 		return nil, errors.NewGitHubAPIError(endpoint, resp.StatusCode,
 			"failed to decode response", err)
 	}
 
 	return &RateLimit{
+		Resource:  "core",
 		Limit:     rateLimitResp.Rate.Limit,
 		Remaining: rateLimitResp.Rate.Remaining,
 		Reset:     time.Unix(int64(rateLimitResp.Rate.Reset), 0),
 	}, nil
 }
 
-// parseNextPage parses the Link header to extract the next page number
-func (c *Client) parseNextPage(linkHeader string) int {
-	if linkHeader == "" {
-		return 0
+// GetCoreRateLimit fetches the current rate limit status of the REST API. It
+// is the same as GetRateLimit for the REST client.
+func (c *Client) GetCoreRateLimit(ctx context.Context) (*RateLimit, error) {
+	return c.GetRateLimit(ctx)
+}
+
+// response holds the parts of a GitHub API response used by the client
+type response struct {
+	StatusCode int
+	Link       string
+	Body       []byte
+}
+
+// get performs a GET request against the GitHub API. When a response cache is
+// configured the request is made conditional, and a 304 Not Modified answer is
+// served from the cached body. Rate limit responses change with every request
+// and are never cached.
+func (c *Client) get(ctx context.Context, owner, name, endpoint, url, accept string) (*response, error) {
+	cache := c.cache
+	if name == "rate_limit" {
+		cache = nil
 	}
 
-	// Parse Link header format: <url>; rel="next", <url>; rel="last"
-	links := strings.Split(linkHeader, ",")
-	for _, link := range links {
-		parts := strings.Split(strings.TrimSpace(link), ";")
-		if len(parts) != 2 {
-			continue
-		}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, errors.NewGitHubAPIError(endpoint, 0, "failed to create request", err)
+	}
 
-		// Check if this is the "next" link
-		if strings.Contains(parts[1], `rel="next"`) {
-			// Extract URL from <url>
-			url := strings.Trim(parts[0], "<>")
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
 
-			// Extract page number from URL
-			re := regexp.MustCompile(`page=(\d+)`)
-			matches := re.FindStringSubmatch(url)
-			if len(matches) > 1 {
-				if page, err := strconv.Atoi(matches[1]); err == nil {
-					return page
-				}
+	token, err := c.authorize(ctx, req, owner, "token")
+	if err != nil {
+		return nil, err
+	}
+
+	// Add validators from the cached response
+	var cached *CacheEntry
+	if cache != nil {
+		if entry, ok := cache.Get(url); ok {
+			cached = entry
+			if entry.ETag != "" {
+				req.Header.Set("If-None-Match", entry.ETag)
+			}
+			if entry.LastModified != "" {
+				req.Header.Set("If-Modified-Since", entry.LastModified)
 			}
 		}
 	}
 
-	return 0
-}
-
-// RetryableClient wraps the GitHub client with retry logic
-type RetryableClient struct {
-	*Client
-	maxRetries int
-	backoff    time.Duration
-}
-
-// NewRetryableClient creates a new retryable GitHub client
-func NewRetryableClient(client *Client, maxRetries int, backoff time.Duration) *RetryableClient {
-	return &RetryableClient{
-		Client:     client,
-		maxRetries: maxRetries,
-		backoff:    backoff,
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.NewGitHubAPIError(endpoint, 0, "failed to make request", err)
 	}
-}
+	defer resp.Body.Close()
 
-// GetStargazersWithRetry fetches stargazers with retry logic
-func (rc *RetryableClient) GetStargazersWithRetry(ctx context.Context, owner, repo string) ([]Stargazer, error) {
-	var lastErr error
+	c.trackRateLimit(token, resp.Header)
 
-	for i := 0; i <= rc.maxRetries; i++ {
-		stargazers, err := rc.Client.GetStargazers(ctx, owner, repo)
-		if err == nil {
-			return stargazers, nil
+	// Unchanged since the cached response, reuse its body
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		if c.cacheObserver != nil {
+			c.cacheObserver.RecordGitHubCacheHit(name)
 		}
+		return &response{
+			StatusCode: http.StatusOK,
+			Link:       cached.Link,
+			Body:       cached.Body,
+		}, nil
+	}
 
-		lastErr = err
+	if resp.StatusCode != http.StatusOK {
+		return nil, newResponseError(endpoint, resp)
+	}
 
-		// Check if it's a rate limit error
-		if gitHubErr, ok := err.(*errors.GitHubAPIError); ok && gitHubErr.IsRateLimited() {
-			// For rate limit errors, don't retry immediately
-			return nil, err
-		}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.NewGitHubAPIError(endpoint, resp.StatusCode,
+			"failed to read response", err)
+	}
 
-		// Don't retry on context cancellation
-		if ctx.Err() != nil {
-			return nil, ctx.Err()
+	result := &response{
+		StatusCode: resp.StatusCode,
+		Link:       resp.Header.Get("Link"),
+		Body:       body,
+	}
+
+	if cache != nil {
+		if c.cacheObserver != nil {
+			c.cacheObserver.RecordGitHubCacheMiss(name)
 		}
 
-		// Wait before retrying (except on last attempt)
-		if i < rc.maxRetries {
-			select {
-			case <-time.After(rc.backoff * time.Duration(i+1)):
-				// Continue to next retry
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			}
+		etag := resp.Header.Get("ETag")
+		lastModified := resp.Header.Get("Last-Modified")
+		if etag != "" || lastModified != "" {
+			// A failed cache write only costs a full download next time
+			_ = cache.Set(url, &CacheEntry{
+				ETag:         etag,
+				LastModified: lastModified,
+				Link:         result.Link,
+				Body:         body,
+			})
 		}
 	}
 
-	return nil, lastErr
+	return result, nil
 }
 
-// GetRateLimitWithRetry fetches rate limit with retry logic
-func (rc *RetryableClient) GetRateLimitWithRetry(ctx context.Context) (*RateLimit, error) {
-	var lastErr error
+// parseNextPage parses the Link header to extract the next page number
+func (c *Client) parseNextPage(linkHeader string) int {
+	return parseLinkPage(linkHeader, "next")
+}
 
-	for i := 0; i <= rc.maxRetries; i++ {
-		rateLimit, err := rc.Client.GetRateLimit(ctx)
-		if err == nil {
-			return rateLimit, nil
-		}
+// parseLastPage parses the Link header to extract the last page number
+func (c *Client) parseLastPage(linkHeader string) int {
+	return parseLinkPage(linkHeader, "last")
+}
 
-		lastErr = err
+// linkPageRegexp extracts the page query parameter from a Link header URL
+var linkPageRegexp = regexp.MustCompile(`[?&]page=(\d+)`)
 
-		// Don't retry on context cancellation
-		if ctx.Err() != nil {
-			return nil, ctx.Err()
+// parseLinkPage parses the Link header to extract the page number for the given relation
+func parseLinkPage(linkHeader, rel string) int {
+	if linkHeader == "" {
+		return 0
+	}
+
+	// Parse Link header format: <url>; rel="next", <url>; rel="last"
+	links := strings.Split(linkHeader, ",")
+	for _, link := range links {
+		parts := strings.Split(strings.TrimSpace(link), ";")
+		if len(parts) != 2 {
+			continue
 		}
 
-		// Wait before retrying (except on last attempt)
-		if i < rc.maxRetries {
-			select {
-			case <-time.After(rc.backoff * time.Duration(i+1)):
-				// Continue to next retry
-			case <-ctx.Done():
-				return nil, ctx.Err()
+		// Check if this is the requested relation
+		if strings.TrimSpace(parts[1]) == fmt.Sprintf(`rel="%s"`, rel) {
+			// Extract URL from <url>
+			url := strings.Trim(parts[0], "<>")
+
+			// Extract page number from URL
+			matches := linkPageRegexp.FindStringSubmatch(url)
+			if len(matches) > 1 {
+				if page, err := strconv.Atoi(matches[1]); err == nil {
+					return page
+				}
 			}
 		}
 	}
 
-	return nil, lastErr
+	return 0
 }
@@ -2,8 +2,15 @@ package github
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
+
+	"github-stars-notify/internal/errors"
 )
 
 func TestGitHubClientBasic(t *testing.T) {
@@ -112,3 +119,359 @@ func TestParseNextPage(t *testing.T) {
 		}
 	}
 }
+
+// newStargazersServer serves a paginated stargazers list and records requested pages
+func newStargazersServer(t *testing.T, total int, requested *[]int) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		*requested = append(*requested, page)
+
+		lastPage := (total + 99) / 100
+		url := server.URL + r.URL.Path
+		links := fmt.Sprintf(`<%s?per_page=100&page=%d>; rel="last"`, url, lastPage)
+		if page < lastPage {
+			links = fmt.Sprintf(`<%s?per_page=100&page=%d>; rel="next", `, url, page+1) + links
+		}
+		w.Header().Set("Link", links)
+
+		var items []map[string]interface{}
+		for id := (page-1)*100 + 1; id <= page*100 && id <= total; id++ {
+			items = append(items, map[string]interface{}{
+				"starred_at": time.Now().Format(time.RFC3339),
+				"user":       map[string]interface{}{"login": fmt.Sprintf("user%d", id), "id": id},
+			})
+		}
+		if err := json.NewEncoder(w).Encode(items); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+	return server
+}
+
+func TestGetStargazersIncremental(t *testing.T) {
+	var requested []int
+	server := newStargazersServer(t, 450, &requested)
+	defer server.Close()
+
+	client := NewClientWithConfig(Config{BaseURL: server.URL})
+
+	// Stargazers 1..320 are already known, so only pages 1, 5 and 4 should be fetched
+	knownIDs := make(map[int64]bool)
+	for id := int64(1); id <= 320; id++ {
+		knownIDs[id] = true
+	}
+
+	newStargazers, err := client.GetStargazersIncremental(context.Background(), "owner", "repo", knownIDs)
+	if err != nil {
+		t.Fatalf("GetStargazersIncremental failed: %v", err)
+	}
+
+	if len(newStargazers) != 130 {
+		t.Fatalf("Expected 130 new stargazers, got %d", len(newStargazers))
+	}
+	if newStargazers[0].ID != 321 || newStargazers[len(newStargazers)-1].ID != 450 {
+		t.Errorf("Expected stargazers 321..450 in order, got %d..%d",
+			newStargazers[0].ID, newStargazers[len(newStargazers)-1].ID)
+	}
+
+	expectedPages := []int{1, 5, 4}
+	if fmt.Sprint(requested) != fmt.Sprint(expectedPages) {
+		t.Errorf("Expected pages %v to be requested, got %v", expectedPages, requested)
+	}
+}
+
+func TestGetStargazersIncrementalWithoutKnown(t *testing.T) {
+	var requested []int
+	server := newStargazersServer(t, 250, &requested)
+	defer server.Close()
+
+	client := NewClientWithConfig(Config{BaseURL: server.URL})
+
+	stargazers, err := client.GetStargazersIncremental(context.Background(), "owner", "repo", nil)
+	if err != nil {
+		t.Fatalf("GetStargazersIncremental failed: %v", err)
+	}
+	if len(stargazers) != 250 {
+		t.Errorf("Expected all 250 stargazers, got %d", len(stargazers))
+	}
+}
+
+func TestParseLastPage(t *testing.T) {
+	client := NewClient()
+
+	linkHeader := `<https://api.github.com/repositories/1/stargazers?per_page=100&page=2>; rel="next", ` +
+		`<https://api.github.com/repositories/1/stargazers?per_page=100&page=7>; rel="last"`
+
+	if page := client.parseLastPage(linkHeader); page != 7 {
+		t.Errorf("parseLastPage = %d, expected 7", page)
+	}
+	if page := client.parseNextPage(linkHeader); page != 2 {
+		t.Errorf("parseNextPage = %d, expected 2", page)
+	}
+}
+
+// countingObserver counts cache hits and misses
+type countingObserver struct {
+	hits   int
+	misses int
+}
+
+func (o *countingObserver) RecordGitHubCacheHit(endpoint string)  { o.hits++ }
+func (o *countingObserver) RecordGitHubCacheMiss(endpoint string) { o.misses++ }
+
+func TestConditionalRequests(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/rate_limit" {
+			if r.Header.Get("If-None-Match") != "" {
+				t.Error("Expected the rate limit to be requested unconditionally")
+			}
+			w.Header().Set("ETag", `"rate"`)
+			fmt.Fprint(w, `{"rate":{"limit":5000,"remaining":4999,"reset":0}}`)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `[{"starred_at":"2024-01-01T00:00:00Z","user":{"login":"alice","id":1}}]`)
+	}))
+	defer server.Close()
+
+	observer := &countingObserver{}
+	cache := NewMemoryCache()
+	client := NewClientWithConfig(Config{
+		BaseURL:       server.URL,
+		Cache:         cache,
+		CacheObserver: observer,
+	})
+
+	for i := 0; i < 2; i++ {
+		stargazers, err := client.GetStargazers(context.Background(), "owner", "repo")
+		if err != nil {
+			t.Fatalf("GetStargazers failed on attempt %d: %v", i+1, err)
+		}
+		if len(stargazers) != 1 || stargazers[0].Login != "alice" {
+			t.Errorf("Unexpected stargazers on attempt %d: %+v", i+1, stargazers)
+		}
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	if observer.misses != 1 || observer.hits != 1 {
+		t.Errorf("Expected 1 miss and 1 hit, got %d misses and %d hits", observer.misses, observer.hits)
+	}
+
+	// Rate limits bypass the cache
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetRateLimit(context.Background()); err != nil {
+			t.Fatalf("GetRateLimit failed: %v", err)
+		}
+	}
+	if cache.Len() != 1 || observer.misses != 1 {
+		t.Errorf("Expected the rate limit not to be cached, got %d entries and %d misses", cache.Len(), observer.misses)
+	}
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	cache := NewMemoryCacheWithSize(2)
+
+	cache.Set("a", &CacheEntry{ETag: "a"})
+	cache.Set("b", &CacheEntry{ETag: "b"})
+	cache.Get("a") // b is now the least recently used
+	cache.Set("c", &CacheEntry{ETag: "c"})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if entry, ok := cache.Get(key); !ok || entry.ETag != key {
+			t.Errorf("Expected %s to be cached, got %+v", key, entry)
+		}
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", cache.Len())
+	}
+
+	// Replacing an entry does not grow the cache
+	cache.Set("a", &CacheEntry{ETag: "a2"})
+	if entry, _ := cache.Get("a"); cache.Len() != 2 || entry.ETag != "a2" {
+		t.Errorf("Expected a to be replaced, got %+v with %d entries", entry, cache.Len())
+	}
+}
+
+func TestListRepositoriesUserFallback(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+
+		switch r.URL.Path {
+		case "/orgs/octocat/repos":
+			w.WriteHeader(http.StatusNotFound)
+		case "/users/octocat/repos":
+			fmt.Fprint(w, `[{"id":1,"name":"hello","full_name":"octocat/hello","topics":["oss"]},{"id":2,"name":"old","archived":true}]`)
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithConfig(Config{BaseURL: server.URL})
+
+	repos, err := client.ListRepositories(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("ListRepositories failed: %v", err)
+	}
+
+	if len(repos) != 2 {
+		t.Fatalf("Expected 2 repositories, got %d", len(repos))
+	}
+	if repos[0].Name != "hello" || len(repos[0].Topics) != 1 || !repos[1].Archived {
+		t.Errorf("Unexpected repositories: %+v", repos)
+	}
+	if len(paths) != 2 || paths[0] != "/orgs/octocat/repos" {
+		t.Errorf("Expected organization lookup before user fallback, got %v", paths)
+	}
+}
+
+func TestGetForks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octocat/hello/forks" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("sort") != "oldest" {
+			t.Errorf("Expected forks sorted oldest first, got %q", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `[{"id":7,"name":"hello","full_name":"alice/hello","html_url":"https://github.com/alice/hello","created_at":"2024-01-02T03:04:05Z"}]`)
+	}))
+	defer server.Close()
+
+	client := NewClientWithConfig(Config{BaseURL: server.URL})
+
+	forks, err := client.GetForks(context.Background(), "octocat", "hello")
+	if err != nil {
+		t.Fatalf("GetForks failed: %v", err)
+	}
+	if len(forks) != 1 || forks[0].FullName != "alice/hello" || forks[0].CreatedAt.IsZero() {
+		t.Errorf("Unexpected forks: %+v", forks)
+	}
+}
+
+func TestGetRepositoryFollowsRenames(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/octocat/old":
+			if r.Header.Get("Authorization") != "token test-token" {
+				t.Errorf("Expected the token to be sent, got %q", r.Header.Get("Authorization"))
+			}
+			http.Redirect(w, r, "/repositories/42", http.StatusMovedPermanently)
+		case "/repositories/42":
+			if r.Header.Get("Authorization") != "token test-token" {
+				t.Errorf("Expected the token to survive the redirect, got %q", r.Header.Get("Authorization"))
+			}
+			fmt.Fprint(w, `{"id":42,"name":"new","full_name":"octocat/new","owner":{"login":"octocat"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithConfig(Config{BaseURL: server.URL, Token: "test-token"})
+
+	repository, err := client.GetRepository(context.Background(), "octocat", "old")
+	if err != nil {
+		t.Fatalf("GetRepository failed: %v", err)
+	}
+	if repository.ID != 42 || repository.FullName != "octocat/new" {
+		t.Errorf("Expected the renamed repository, got %+v", repository)
+	}
+
+	repository, err = client.GetRepositoryByID(context.Background(), "octocat", 42)
+	if err != nil {
+		t.Fatalf("GetRepositoryByID failed: %v", err)
+	}
+	if repository.FullName != "octocat/new" {
+		t.Errorf("Expected octocat/new, got %s", repository.FullName)
+	}
+
+	_, err = client.GetRepository(context.Background(), "octocat", "deleted")
+	gitHubErr, ok := err.(*errors.GitHubAPIError)
+	if !ok || !gitHubErr.IsNotFound() {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestGetReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/octocat/hello/releases" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `[{"id":2,"tag_name":"v1.1.0","name":"Second","prerelease":true,"assets":[]},
+			{"id":1,"tag_name":"v1.0.0","assets":[{"name":"hello.tar.gz","download_count":40},{"name":"hello.zip","download_count":2}]}]`)
+	}))
+	defer server.Close()
+
+	client := NewClientWithConfig(Config{BaseURL: server.URL})
+
+	releases, err := client.GetReleases(context.Background(), "octocat", "hello")
+	if err != nil {
+		t.Fatalf("GetReleases failed: %v", err)
+	}
+	if len(releases) != 2 || releases[0].TagName != "v1.1.0" || !releases[0].Prerelease {
+		t.Fatalf("Unexpected releases: %+v", releases)
+	}
+	if releases[1].DownloadCount() != 42 {
+		t.Errorf("Expected 42 downloads, got %d", releases[1].DownloadCount())
+	}
+}
+
+func TestGetFollowers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/my-org/followers" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/users/my-org/followers?page=2&per_page=100>; rel="next"`, "http://"+r.Host))
+			fmt.Fprint(w, `[{"login":"alice","id":1}]`)
+		default:
+			fmt.Fprint(w, `[{"login":"bob","id":2}]`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithConfig(Config{BaseURL: server.URL})
+
+	followers, err := client.GetFollowers(context.Background(), "my-org")
+	if err != nil {
+		t.Fatalf("GetFollowers failed: %v", err)
+	}
+	if len(followers) != 2 || followers[0].Login != "alice" || followers[1].Login != "bob" {
+		t.Errorf("Expected followers from both pages, got %+v", followers)
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/alice" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"login":"alice","id":1,"name":"Alice","company":"Acme Corp","followers":2300,"public_repos":42,"created_at":"2015-03-01T00:00:00Z"}`)
+	}))
+	defer server.Close()
+
+	client := NewClientWithConfig(Config{BaseURL: server.URL})
+
+	profile, err := client.GetUser(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("GetUser failed: %v", err)
+	}
+	if profile.Company != "Acme Corp" || profile.Followers != 2300 || profile.PublicRepos != 42 || profile.CreatedAt.Year() != 2015 {
+		t.Errorf("Unexpected profile: %+v", profile)
+	}
+}
@@ -0,0 +1,47 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github-stars-notify/internal/errors"
+)
+
+// GetFollowers fetches all followers of a user or organization. Followers are
+// returned as Stargazer values so they share the stargazer diffing and
+// notification formatting, StarredAt is always zero.
+func (c *Client) GetFollowers(ctx context.Context, login string) ([]Stargazer, error) {
+	endpoint := fmt.Sprintf("/users/%s/followers", login)
+	var allFollowers []Stargazer
+	page := 1
+
+	for {
+		url := fmt.Sprintf("%s%s?page=%d&per_page=100", c.baseURL, endpoint, page)
+
+		resp, err := c.get(ctx, login, "followers", endpoint, url, "application/vnd.github+json")
+		if err != nil {
+			return nil, err
+		}
+
+		var followers []Stargazer
+		if err := json.Unmarshal(resp.Body, &followers); err != nil {
+			return nil, errors.NewGitHubAPIError(endpoint, resp.StatusCode,
+				"failed to decode response", err)
+		}
+		allFollowers = append(allFollowers, followers...)
+
+		nextPage := c.parseNextPage(resp.Link)
+		if nextPage == 0 {
+			break
+		}
+		page = nextPage
+
+		// Check if context is cancelled
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	return allFollowers, nil
+}
@@ -0,0 +1,260 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github-stars-notify/internal/errors"
+)
+
+// stargazersQuery fetches stargazers newest-first using cursor pagination
+const stargazersQuery = `query($owner: String!, $name: String!, $cursor: String) {
+  repository(owner: $owner, name: $name) {
+    stargazers(first: 100, after: $cursor, orderBy: {field: STARRED_AT, direction: DESC}) {
+      totalCount
+      pageInfo { hasNextPage endCursor }
+      edges { starredAt node { login databaseId id avatarUrl url } }
+    }
+  }
+}`
+
+// rateLimitQuery fetches the GraphQL rate limit status
+const rateLimitQuery = `query { rateLimit { limit remaining resetAt } }`
+
+// GraphQLClient fetches stargazers through the GitHub GraphQL API. Unlike the REST
+// endpoint it is not capped at ~40k results and returns the newest stars first.
+// Operations without a GraphQL implementation fall back to the embedded REST client.
+type GraphQLClient struct {
+	*Client
+	graphqlURL string
+}
+
+// graphqlRequest represents a GraphQL request body
+type graphqlRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// graphqlError represents an error returned by the GraphQL API
+type graphqlError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// stargazerConnection represents a page of the stargazers connection
+type stargazerConnection struct {
+	TotalCount int `json:"totalCount"`
+	PageInfo   struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Edges []struct {
+		StarredAt time.Time `json:"starredAt"`
+		Node      struct {
+			Login      string `json:"login"`
+			DatabaseID int64  `json:"databaseId"`
+			ID         string `json:"id"`
+			AvatarURL  string `json:"avatarUrl"`
+			URL        string `json:"url"`
+		} `json:"node"`
+	} `json:"edges"`
+}
+
+// NewGraphQLClient creates a new GitHub GraphQL API client
+func NewGraphQLClient(cfg Config) *GraphQLClient {
+	client := NewClientWithConfig(cfg)
+
+	return &GraphQLClient{
+		Client:     client,
+		graphqlURL: graphqlURLFromBaseURL(client.baseURL),
+	}
+}
+
+// graphqlURLFromBaseURL derives the GraphQL endpoint from the REST base URL.
+// GitHub Enterprise Server serves REST below /api/v3 and GraphQL at /api/graphql.
+func graphqlURLFromBaseURL(baseURL string) string {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if strings.HasSuffix(baseURL, "/api/v3") {
+		return strings.TrimSuffix(baseURL, "/v3") + "/graphql"
+	}
+	return baseURL + "/graphql"
+}
+
+// GetStargazers fetches all stargazers for a repository, oldest first
+func (gc *GraphQLClient) GetStargazers(ctx context.Context, owner, repo string) ([]Stargazer, error) {
+	return gc.GetStargazersIncremental(ctx, owner, repo, nil)
+}
+
+// GetStargazersIncremental fetches stargazers newest-first until a stargazer from
+// knownIDs is reached. The result is ordered oldest-first like the REST client.
+func (gc *GraphQLClient) GetStargazersIncremental(ctx context.Context, owner, repo string, knownIDs map[int64]bool) ([]Stargazer, error) {
+	var newestFirst []Stargazer
+	var cursor string
+
+	for {
+		// Check if context is cancelled
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		connection, err := gc.getStargazersPage(ctx, owner, repo, cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, edge := range connection.Edges {
+			if knownIDs[edge.Node.DatabaseID] {
+				return reverseStargazers(newestFirst), nil
+			}
+
+			newestFirst = append(newestFirst, Stargazer{
+				Login:     edge.Node.Login,
+				ID:        edge.Node.DatabaseID,
+				NodeID:    edge.Node.ID,
+				AvatarURL: edge.Node.AvatarURL,
+				HTMLURL:   edge.Node.URL,
+				StarredAt: edge.StarredAt,
+			})
+		}
+
+		if !connection.PageInfo.HasNextPage {
+			break
+		}
+		cursor = connection.PageInfo.EndCursor
+	}
+
+	return reverseStargazers(newestFirst), nil
+}
+
+// getStargazersPage fetches a single page of the stargazers connection
+func (gc *GraphQLClient) getStargazersPage(ctx context.Context, owner, repo, cursor string) (*stargazerConnection, error) {
+	variables := map[string]interface{}{
+		"owner": owner,
+		"name":  repo,
+	}
+	if cursor != "" {
+		variables["cursor"] = cursor
+	}
+
+	var data struct {
+		Repository *struct {
+			Stargazers stargazerConnection `json:"stargazers"`
+		} `json:"repository"`
+	}
+
+	if err := gc.query(ctx, owner, stargazersQuery, variables, &data); err != nil {
+		return nil, err
+	}
+
+	if data.Repository == nil {
+		return nil, errors.NewGitHubAPIError("graphql", http.StatusNotFound,
+			fmt.Sprintf("repository %s/%s not found", owner, repo), nil)
+	}
+
+	return &data.Repository.Stargazers, nil
+}
+
+// GetRateLimit fetches the current GraphQL rate limit status
+func (gc *GraphQLClient) GetRateLimit(ctx context.Context) (*RateLimit, error) {
+	var data struct {
+		RateLimit struct {
+			Limit     int       `json:"limit"`
+			Remaining int       `json:"remaining"`
+			ResetAt   time.Time `json:"resetAt"`
+		} `json:"rateLimit"`
+	}
+
+	if err := gc.query(ctx, "", rateLimitQuery, nil, &data); err != nil {
+		return nil, err
+	}
+
+	return &RateLimit{
+		Resource:  "graphql",
+		Limit:     data.RateLimit.Limit,
+		Remaining: data.RateLimit.Remaining,
+		Reset:     data.RateLimit.ResetAt,
+	}, nil
+}
+
+// query executes a GraphQL query on behalf of owner and decodes its data into result
+func (gc *GraphQLClient) query(ctx context.Context, owner, query string, variables map[string]interface{}, result interface{}) error {
+	endpoint := "graphql"
+
+	body, err := json.Marshal(graphqlRequest{Query: query, Variables: variables})
+	if err != nil {
+		return errors.NewGitHubAPIError(endpoint, 0, "failed to marshal query", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", gc.graphqlURL, bytes.NewReader(body))
+	if err != nil {
+		return errors.NewGitHubAPIError(endpoint, 0, "failed to create request", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", gc.userAgent)
+
+	// The GraphQL API always requires authentication
+	token, err := gc.authorize(withRateLimitResource(ctx, "graphql"), req, owner, "bearer")
+	if err != nil {
+		return err
+	}
+
+	resp, err := gc.httpClient.Do(req)
+	if err != nil {
+		return errors.NewGitHubAPIError(endpoint, 0, "failed to make request", err)
+	}
+	defer resp.Body.Close()
+
+	gc.trackRateLimit(token, resp.Header)
+
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(endpoint, resp)
+	}
+
+	var graphqlResp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphqlError  `json:"errors"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&graphqlResp); err != nil {
+		return errors.NewGitHubAPIError(endpoint, resp.StatusCode,
+			"failed to decode response", err)
+	}
+
+	if len(graphqlResp.Errors) > 0 {
+		first := graphqlResp.Errors[0]
+		statusCode := resp.StatusCode
+		switch first.Type {
+		case "NOT_FOUND":
+			statusCode = http.StatusNotFound
+		case "RATE_LIMITED":
+			statusCode = http.StatusTooManyRequests
+		}
+		apiErr := errors.NewGitHubAPIError(endpoint, statusCode, first.Message, nil)
+		if statusCode == http.StatusTooManyRequests {
+			applyRateLimitHeaders(apiErr, resp.Header)
+		}
+		return apiErr
+	}
+
+	if err := json.Unmarshal(graphqlResp.Data, result); err != nil {
+		return errors.NewGitHubAPIError(endpoint, resp.StatusCode,
+			"failed to decode response data", err)
+	}
+
+	return nil
+}
+
+// reverseStargazers returns the stargazers in reverse order
+func reverseStargazers(stargazers []Stargazer) []Stargazer {
+	reversed := make([]Stargazer, len(stargazers))
+	for i, sg := range stargazers {
+		reversed[len(stargazers)-1-i] = sg
+	}
+	return reversed
+}
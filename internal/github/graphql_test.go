@@ -0,0 +1,183 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newGraphQLServer serves a stargazers connection with IDs total..1 (newest first)
+func newGraphQLServer(t *testing.T, total int, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+
+		if r.URL.Path != "/graphql" || r.Method != "POST" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "bearer test-token" {
+			t.Errorf("Expected bearer authorization, got %q", r.Header.Get("Authorization"))
+		}
+
+		var req graphqlRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+			return
+		}
+
+		if strings.Contains(req.Query, "rateLimit") {
+			fmt.Fprint(w, `{"data":{"rateLimit":{"limit":5000,"remaining":4999,"resetAt":"2030-01-01T00:00:00Z"}}}`)
+			return
+		}
+
+		if req.Variables["name"] == "missing" {
+			fmt.Fprint(w, `{"data":{"repository":null},"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a Repository"}]}`)
+			return
+		}
+
+		// The cursor is the index of the next edge
+		start := 0
+		if cursor, ok := req.Variables["cursor"].(string); ok {
+			start, _ = strconv.Atoi(cursor)
+		}
+
+		var edges []map[string]interface{}
+		for i := start; i < start+100 && i < total; i++ {
+			id := total - i
+			edges = append(edges, map[string]interface{}{
+				"starredAt": time.Unix(int64(id), 0).UTC().Format(time.RFC3339),
+				"node":      map[string]interface{}{"login": fmt.Sprintf("user%d", id), "databaseId": id},
+			})
+		}
+
+		end := start + len(edges)
+		resp := map[string]interface{}{
+			"data": map[string]interface{}{
+				"repository": map[string]interface{}{
+					"stargazers": map[string]interface{}{
+						"totalCount": total,
+						"pageInfo":   map[string]interface{}{"hasNextPage": end < total, "endCursor": strconv.Itoa(end)},
+						"edges":      edges,
+					},
+				},
+			},
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+		}
+	}))
+}
+
+func TestGraphQLGetStargazers(t *testing.T) {
+	requests := 0
+	server := newGraphQLServer(t, 250, &requests)
+	defer server.Close()
+
+	client := NewGraphQLClient(Config{BaseURL: server.URL, Token: "test-token"})
+
+	stargazers, err := client.GetStargazers(context.Background(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("GetStargazers failed: %v", err)
+	}
+
+	if len(stargazers) != 250 {
+		t.Fatalf("Expected 250 stargazers, got %d", len(stargazers))
+	}
+	if stargazers[0].ID != 1 || stargazers[249].ID != 250 {
+		t.Errorf("Expected stargazers ordered oldest-first, got %d..%d", stargazers[0].ID, stargazers[249].ID)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+}
+
+func TestGraphQLGetStargazersIncremental(t *testing.T) {
+	requests := 0
+	server := newGraphQLServer(t, 1000, &requests)
+	defer server.Close()
+
+	client := NewGraphQLClient(Config{BaseURL: server.URL, Token: "test-token"})
+
+	knownIDs := make(map[int64]bool)
+	for id := int64(1); id <= 985; id++ {
+		knownIDs[id] = true
+	}
+
+	stargazers, err := client.GetStargazersIncremental(context.Background(), "owner", "repo", knownIDs)
+	if err != nil {
+		t.Fatalf("GetStargazersIncremental failed: %v", err)
+	}
+
+	if len(stargazers) != 15 {
+		t.Fatalf("Expected 15 new stargazers, got %d", len(stargazers))
+	}
+	if stargazers[0].ID != 986 || stargazers[14].ID != 1000 {
+		t.Errorf("Expected stargazers 986..1000, got %d..%d", stargazers[0].ID, stargazers[14].ID)
+	}
+	if requests != 1 {
+		t.Errorf("Expected only the newest page to be fetched, got %d requests", requests)
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	requests := 0
+	server := newGraphQLServer(t, 10, &requests)
+	defer server.Close()
+
+	client := NewGraphQLClient(Config{BaseURL: server.URL, Token: "test-token"})
+
+	_, err := client.GetStargazers(context.Background(), "owner", "missing")
+	if err == nil {
+		t.Fatal("Expected error for missing repository")
+	}
+	if !strings.Contains(err.Error(), "[404]") {
+		t.Errorf("Expected NOT_FOUND to map to 404, got %v", err)
+	}
+
+	rateLimit, err := client.GetRateLimit(context.Background())
+	if err != nil {
+		t.Fatalf("GetRateLimit failed: %v", err)
+	}
+	if rateLimit.Resource != "graphql" || rateLimit.Remaining != 4999 {
+		t.Errorf("Unexpected rate limit: %+v", rateLimit)
+	}
+}
+
+func TestGraphQLCoreRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rate_limit" {
+			t.Errorf("Expected the REST rate limit to be requested, got %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"rate":{"limit":5000,"remaining":1234,"reset":1893456000}}`)
+	}))
+	defer server.Close()
+
+	client := NewGraphQLClient(Config{BaseURL: server.URL, Token: "test-token"})
+
+	rateLimit, err := client.GetCoreRateLimit(context.Background())
+	if err != nil {
+		t.Fatalf("GetCoreRateLimit failed: %v", err)
+	}
+	if rateLimit.Resource != "core" || rateLimit.Remaining != 1234 {
+		t.Errorf("Unexpected rate limit: %+v", rateLimit)
+	}
+}
+
+func TestGraphQLURLFromBaseURL(t *testing.T) {
+	tests := map[string]string{
+		"https://api.github.com":          "https://api.github.com/graphql",
+		"https://ghe.example.com/api/v3":  "https://ghe.example.com/api/graphql",
+		"https://ghe.example.com/api/v3/": "https://ghe.example.com/api/graphql",
+	}
+
+	for baseURL, expected := range tests {
+		if got := graphqlURLFromBaseURL(baseURL); got != expected {
+			t.Errorf("graphqlURLFromBaseURL(%q) = %q, want %q", baseURL, got, expected)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package github
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github-stars-notify/internal/errors"
+)
+
+// secondaryRateLimitWait is how long to back off after a secondary rate limit
+// without a Retry-After header, as recommended by GitHub
+const secondaryRateLimitWait = time.Minute
+
+// parseRateLimitHeaders extracts the rate limit reported in X-RateLimit-* headers
+func parseRateLimitHeaders(header http.Header) (RateLimit, bool) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+
+	limit, _ := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	reset, _ := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+
+	return RateLimit{
+		Resource:  header.Get("X-RateLimit-Resource"),
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     time.Unix(reset, 0),
+	}, true
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
+
+// newResponseError creates the error for an unsuccessful response, including
+// the rate limit details needed to decide when to retry
+func newResponseError(endpoint string, resp *http.Response) *errors.GitHubAPIError {
+	apiErr := errors.NewGitHubAPIError(endpoint, resp.StatusCode,
+		fmt.Sprintf("API request failed with status %d", resp.StatusCode), nil)
+
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return apiErr
+	}
+
+	applyRateLimitHeaders(apiErr, resp.Header)
+
+	// Secondary rate limits are only identifiable from the message
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if strings.Contains(strings.ToLower(string(body)), "secondary rate limit") {
+		apiErr.Secondary = true
+		apiErr.Message = "secondary rate limit exceeded"
+		if apiErr.RetryAfter == 0 {
+			apiErr.RetryAfter = secondaryRateLimitWait
+		}
+	}
+
+	return apiErr
+}
+
+// applyRateLimitHeaders sets the retry information of a rate limited response on apiErr
+func applyRateLimitHeaders(apiErr *errors.GitHubAPIError, header http.Header) {
+	apiErr.RetryAfter = parseRetryAfter(header.Get("Retry-After"), time.Now())
+
+	if rateLimit, ok := parseRateLimitHeaders(header); ok && rateLimit.Remaining == 0 {
+		apiErr.ResetAt = rateLimit.Reset
+		apiErr.Message = fmt.Sprintf("rate limit exceeded, resets at %s", rateLimit.Reset.Format(time.RFC3339))
+	}
+}
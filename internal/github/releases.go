@@ -0,0 +1,62 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github-stars-notify/internal/errors"
+)
+
+// Release represents a GitHub release
+type Release struct {
+	ID          int64          `json:"id"`
+	TagName     string         `json:"tag_name"`
+	Name        string         `json:"name"`
+	Body        string         `json:"body"`
+	HTMLURL     string         `json:"html_url"`
+	Draft       bool           `json:"draft"`
+	Prerelease  bool           `json:"prerelease"`
+	CreatedAt   time.Time      `json:"created_at"`
+	PublishedAt time.Time      `json:"published_at"`
+	Assets      []ReleaseAsset `json:"assets"`
+}
+
+// ReleaseAsset represents a file attached to a release
+type ReleaseAsset struct {
+	ID                 int64  `json:"id"`
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	DownloadCount      int    `json:"download_count"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// DownloadCount returns the total downloads of the release's assets
+func (r Release) DownloadCount() int {
+	total := 0
+	for _, asset := range r.Assets {
+		total += asset.DownloadCount
+	}
+	return total
+}
+
+// GetReleases fetches the 100 most recent releases of a repository, newest first.
+// Drafts are only included when the token has push access to the repository.
+func (c *Client) GetReleases(ctx context.Context, owner, repo string) ([]Release, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/releases", owner, repo)
+	url := fmt.Sprintf("%s%s?per_page=100", c.baseURL, endpoint)
+
+	resp, err := c.get(ctx, owner, "releases", endpoint, url, "application/vnd.github+json")
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []Release
+	if err := json.Unmarshal(resp.Body, &releases); err != nil {
+		return nil, errors.NewGitHubAPIError(endpoint, resp.StatusCode,
+			"failed to decode response", err)
+	}
+
+	return releases, nil
+}
@@ -0,0 +1,115 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github-stars-notify/internal/errors"
+)
+
+// Repository represents a GitHub repository returned by the repository listing endpoints
+type Repository struct {
+	ID              int64           `json:"id"`
+	Name            string          `json:"name"`
+	FullName        string          `json:"full_name"`
+	Owner           RepositoryOwner `json:"owner"`
+	Private         bool            `json:"private"`
+	Archived        bool            `json:"archived"`
+	Fork            bool            `json:"fork"`
+	Topics          []string        `json:"topics"`
+	StargazersCount int             `json:"stargazers_count"`
+	HTMLURL         string          `json:"html_url"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// RepositoryOwner represents the owner of a repository
+type RepositoryOwner struct {
+	Login string `json:"login"`
+	Type  string `json:"type"`
+}
+
+// ListRepositories lists all repositories of an organization, falling back to
+// the user endpoint when owner is not an organization
+func (c *Client) ListRepositories(ctx context.Context, owner string) ([]Repository, error) {
+	repos, err := c.listRepositories(ctx, owner, "repositories", fmt.Sprintf("/orgs/%s/repos", owner), "type=all")
+	if err == nil {
+		return repos, nil
+	}
+
+	// Not an organization, try as a user
+	if gitHubErr, ok := err.(*errors.GitHubAPIError); ok && gitHubErr.StatusCode == http.StatusNotFound {
+		return c.listRepositories(ctx, owner, "repositories", fmt.Sprintf("/users/%s/repos", owner), "type=owner")
+	}
+
+	return nil, err
+}
+
+// GetRepository fetches a repository by name. Renamed and transferred
+// repositories are answered with a redirect that is followed, so the returned
+// FullName is the current name.
+func (c *Client) GetRepository(ctx context.Context, owner, repo string) (*Repository, error) {
+	return c.getRepository(ctx, owner, fmt.Sprintf("/repos/%s/%s", owner, repo))
+}
+
+// GetRepositoryByID fetches a repository by its numeric ID, which survives renames and transfers
+func (c *Client) GetRepositoryByID(ctx context.Context, owner string, id int64) (*Repository, error) {
+	return c.getRepository(ctx, owner, fmt.Sprintf("/repositories/%d", id))
+}
+
+// getRepository fetches a single repository from endpoint
+func (c *Client) getRepository(ctx context.Context, owner, endpoint string) (*Repository, error) {
+	resp, err := c.get(ctx, owner, "repository", endpoint, c.baseURL+endpoint, "application/vnd.github+json")
+	if err != nil {
+		return nil, err
+	}
+
+	var repository Repository
+	if err := json.Unmarshal(resp.Body, &repository); err != nil {
+		return nil, errors.NewGitHubAPIError(endpoint, resp.StatusCode,
+			"failed to decode response", err)
+	}
+	return &repository, nil
+}
+
+// GetForks fetches all forks of a repository, oldest first
+func (c *Client) GetForks(ctx context.Context, owner, repo string) ([]Repository, error) {
+	return c.listRepositories(ctx, owner, "forks", fmt.Sprintf("/repos/%s/%s/forks", owner, repo), "sort=oldest")
+}
+
+// listRepositories fetches every page of a repository listing endpoint
+func (c *Client) listRepositories(ctx context.Context, owner, name, endpoint, query string) ([]Repository, error) {
+	var allRepos []Repository
+	page := 1
+
+	for {
+		url := fmt.Sprintf("%s%s?%s&page=%d&per_page=100", c.baseURL, endpoint, query, page)
+
+		resp, err := c.get(ctx, owner, name, endpoint, url, "application/vnd.github+json")
+		if err != nil {
+			return nil, err
+		}
+
+		var repos []Repository
+		if err := json.Unmarshal(resp.Body, &repos); err != nil {
+			return nil, errors.NewGitHubAPIError(endpoint, resp.StatusCode,
+				"failed to decode response", err)
+		}
+		allRepos = append(allRepos, repos...)
+
+		nextPage := c.parseNextPage(resp.Link)
+		if nextPage == 0 {
+			break
+		}
+		page = nextPage
+
+		// Check if context is cancelled
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	return allRepos, nil
+}
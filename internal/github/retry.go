@@ -0,0 +1,185 @@
+package github
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github-stars-notify/internal/errors"
+)
+
+// DefaultMaxRateLimitWait is the longest a call pauses for a rate limit to reset,
+// which covers the hourly primary rate limit window
+const DefaultMaxRateLimitWait = time.Hour
+
+// rateLimitResetMargin is added to reset times to allow for clock drift
+const rateLimitResetMargin = time.Second
+
+// RetryableClient wraps a GitHub API client with retry logic
+type RetryableClient struct {
+	API
+	maxRetries       int
+	backoff          time.Duration
+	maxRateLimitWait time.Duration
+	sleep            func(ctx context.Context, d time.Duration) error
+}
+
+// NewRetryableClient creates a new retryable GitHub client. backoff is the base
+// delay for transient errors, doubled on every attempt.
+func NewRetryableClient(client API, maxRetries int, backoff time.Duration) *RetryableClient {
+	return &RetryableClient{
+		API:              client,
+		maxRetries:       maxRetries,
+		backoff:          backoff,
+		maxRateLimitWait: DefaultMaxRateLimitWait,
+		sleep:            sleepContext,
+	}
+}
+
+// GetStargazersWithRetry fetches stargazers with retry logic
+func (rc *RetryableClient) GetStargazersWithRetry(ctx context.Context, owner, repo string) ([]Stargazer, error) {
+	return withRetry(ctx, rc, func() ([]Stargazer, error) {
+		return rc.API.GetStargazers(ctx, owner, repo)
+	})
+}
+
+// GetStargazersIncrementalWithRetry fetches new stargazers incrementally with retry logic
+func (rc *RetryableClient) GetStargazersIncrementalWithRetry(ctx context.Context, owner, repo string, knownIDs map[int64]bool) ([]Stargazer, error) {
+	return withRetry(ctx, rc, func() ([]Stargazer, error) {
+		return rc.API.GetStargazersIncremental(ctx, owner, repo, knownIDs)
+	})
+}
+
+// GetRateLimitWithRetry fetches rate limit with retry logic
+func (rc *RetryableClient) GetRateLimitWithRetry(ctx context.Context) (*RateLimit, error) {
+	return withRetry(ctx, rc, func() (*RateLimit, error) {
+		return rc.API.GetRateLimit(ctx)
+	})
+}
+
+// ListRepositoriesWithRetry lists the repositories of an organization or user with retry logic
+func (rc *RetryableClient) ListRepositoriesWithRetry(ctx context.Context, owner string) ([]Repository, error) {
+	return withRetry(ctx, rc, func() ([]Repository, error) {
+		return rc.API.ListRepositories(ctx, owner)
+	})
+}
+
+// GetRepositoryWithRetry fetches a repository by name with retry logic
+func (rc *RetryableClient) GetRepositoryWithRetry(ctx context.Context, owner, repo string) (*Repository, error) {
+	return withRetry(ctx, rc, func() (*Repository, error) {
+		return rc.API.GetRepository(ctx, owner, repo)
+	})
+}
+
+// GetRepositoryByIDWithRetry fetches a repository by its numeric ID with retry logic
+func (rc *RetryableClient) GetRepositoryByIDWithRetry(ctx context.Context, owner string, id int64) (*Repository, error) {
+	return withRetry(ctx, rc, func() (*Repository, error) {
+		return rc.API.GetRepositoryByID(ctx, owner, id)
+	})
+}
+
+// GetForksWithRetry fetches the forks of a repository with retry logic
+func (rc *RetryableClient) GetForksWithRetry(ctx context.Context, owner, repo string) ([]Repository, error) {
+	return withRetry(ctx, rc, func() ([]Repository, error) {
+		return rc.API.GetForks(ctx, owner, repo)
+	})
+}
+
+// GetReleasesWithRetry fetches the releases of a repository with retry logic
+func (rc *RetryableClient) GetReleasesWithRetry(ctx context.Context, owner, repo string) ([]Release, error) {
+	return withRetry(ctx, rc, func() ([]Release, error) {
+		return rc.API.GetReleases(ctx, owner, repo)
+	})
+}
+
+// GetFollowersWithRetry fetches the followers of a user or organization with retry logic
+func (rc *RetryableClient) GetFollowersWithRetry(ctx context.Context, login string) ([]Stargazer, error) {
+	return withRetry(ctx, rc, func() ([]Stargazer, error) {
+		return rc.API.GetFollowers(ctx, login)
+	})
+}
+
+// withRetry calls fn until it succeeds, the retries are exhausted or a
+// non-retryable error occurs. Rate limited calls pause until the limit resets,
+// transient failures back off exponentially with jitter.
+func withRetry[T any](ctx context.Context, rc *RetryableClient, fn func() (T, error)) (T, error) {
+	var zero T
+	var lastErr error
+
+	for i := 0; i <= rc.maxRetries; i++ {
+		result, err := fn()
+		if err == nil {
+			return result, nil
+		}
+
+		lastErr = err
+
+		// Don't retry on context cancellation
+		if ctx.Err() != nil {
+			return zero, ctx.Err()
+		}
+
+		if i == rc.maxRetries {
+			break
+		}
+
+		wait, retry := rc.retryDelay(err, i)
+		if !retry {
+			return zero, err
+		}
+
+		if err := rc.sleep(ctx, wait); err != nil {
+			return zero, err
+		}
+	}
+
+	return zero, lastErr
+}
+
+// retryDelay decides whether err is worth retrying and how long to wait first
+func (rc *RetryableClient) retryDelay(err error, attempt int) (time.Duration, bool) {
+	gitHubErr, ok := err.(*errors.GitHubAPIError)
+	if !ok {
+		return rc.exponentialBackoff(attempt), true
+	}
+
+	if gitHubErr.IsRateLimited() {
+		wait := gitHubErr.WaitDuration(time.Now())
+		if wait == 0 {
+			return rc.exponentialBackoff(attempt), true
+		}
+
+		// Pause until the reset rather than failing the check, unless it is too far away
+		if wait > rc.maxRateLimitWait {
+			return 0, false
+		}
+		return wait + rateLimitResetMargin, true
+	}
+
+	if gitHubErr.IsRetryable() {
+		return rc.exponentialBackoff(attempt), true
+	}
+
+	// Other client errors such as 404 will not go away by retrying
+	return 0, false
+}
+
+// exponentialBackoff returns the backoff for an attempt with equal jitter:
+// half of backoff*2^attempt plus a random share of the other half
+func (rc *RetryableClient) exponentialBackoff(attempt int) time.Duration {
+	backoff := rc.backoff << attempt
+	if backoff <= 0 {
+		return 0
+	}
+	return backoff/2 + rand.N(backoff/2+1)
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,193 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github-stars-notify/internal/errors"
+)
+
+// failingAPI returns the queued errors from GetStargazers before succeeding
+type failingAPI struct {
+	API
+	errs  []error
+	calls int
+}
+
+func (f *failingAPI) GetStargazers(ctx context.Context, owner, repo string) ([]Stargazer, error) {
+	f.calls++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return nil, err
+	}
+	return []Stargazer{{Login: "user1", ID: 1}}, nil
+}
+
+// newTestRetryableClient creates a retryable client that records its waits instead of sleeping
+func newTestRetryableClient(api API, waits *[]time.Duration) *RetryableClient {
+	rc := NewRetryableClient(api, 3, 100*time.Millisecond)
+	rc.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	return rc
+}
+
+func TestRetryWaitsForRateLimitReset(t *testing.T) {
+	rateLimited := errors.NewGitHubAPIError("stargazers", http.StatusForbidden, "rate limit exceeded", nil)
+	rateLimited.ResetAt = time.Now().Add(10 * time.Minute)
+
+	secondary := errors.NewGitHubAPIError("stargazers", http.StatusForbidden, "secondary rate limit exceeded", nil)
+	secondary.Secondary = true
+	secondary.RetryAfter = 30 * time.Second
+
+	api := &failingAPI{errs: []error{rateLimited, secondary}}
+	var waits []time.Duration
+	rc := newTestRetryableClient(api, &waits)
+
+	stargazers, err := rc.GetStargazersWithRetry(context.Background(), "owner", "repo")
+	if err != nil {
+		t.Fatalf("Expected the call to succeed after waiting, got %v", err)
+	}
+	if len(stargazers) != 1 || api.calls != 3 {
+		t.Fatalf("Expected 3 calls, got %d", api.calls)
+	}
+
+	if len(waits) != 2 {
+		t.Fatalf("Expected 2 waits, got %v", waits)
+	}
+	if waits[0] < 9*time.Minute || waits[0] > 11*time.Minute {
+		t.Errorf("Expected to wait until the reset, got %v", waits[0])
+	}
+	if waits[1] != 30*time.Second+rateLimitResetMargin {
+		t.Errorf("Expected to honour Retry-After, got %v", waits[1])
+	}
+}
+
+func TestRetryGivesUpOnDistantReset(t *testing.T) {
+	rateLimited := errors.NewGitHubAPIError("stargazers", http.StatusTooManyRequests, "rate limit exceeded", nil)
+	rateLimited.ResetAt = time.Now().Add(2 * DefaultMaxRateLimitWait)
+
+	api := &failingAPI{errs: []error{rateLimited}}
+	var waits []time.Duration
+	rc := newTestRetryableClient(api, &waits)
+
+	if _, err := rc.GetStargazersWithRetry(context.Background(), "owner", "repo"); err != rateLimited {
+		t.Errorf("Expected the rate limit error, got %v", err)
+	}
+	if api.calls != 1 || len(waits) != 0 {
+		t.Errorf("Expected no retry, got %d calls and waits %v", api.calls, waits)
+	}
+}
+
+func TestRetryExponentialBackoff(t *testing.T) {
+	serverErr := errors.NewGitHubAPIError("stargazers", http.StatusBadGateway, "bad gateway", nil)
+
+	api := &failingAPI{errs: []error{serverErr, serverErr, serverErr}}
+	var waits []time.Duration
+	rc := newTestRetryableClient(api, &waits)
+
+	if _, err := rc.GetStargazersWithRetry(context.Background(), "owner", "repo"); err != nil {
+		t.Fatalf("Expected the call to succeed, got %v", err)
+	}
+
+	for i, wait := range waits {
+		max := rc.backoff << i
+		if wait < max/2 || wait > max {
+			t.Errorf("Attempt %d: expected backoff between %v and %v, got %v", i, max/2, max, wait)
+		}
+	}
+}
+
+func TestRetrySkipsClientErrors(t *testing.T) {
+	notFound := errors.NewGitHubAPIError("stargazers", http.StatusNotFound, "not found", nil)
+	forbidden := errors.NewGitHubAPIError("stargazers", http.StatusForbidden, "resource not accessible", nil)
+
+	for _, apiErr := range []error{notFound, forbidden} {
+		api := &failingAPI{errs: []error{apiErr}}
+		var waits []time.Duration
+		rc := newTestRetryableClient(api, &waits)
+
+		if _, err := rc.GetStargazersWithRetry(context.Background(), "owner", "repo"); err != apiErr {
+			t.Errorf("Expected %v, got %v", apiErr, err)
+		}
+		if api.calls != 1 {
+			t.Errorf("Expected no retry for %v, got %d calls", apiErr, api.calls)
+		}
+	}
+}
+
+func TestResponseErrorRateLimits(t *testing.T) {
+	reset := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/primary/stargazers":
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+		case "/repos/owner/secondary/stargazers":
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}`)
+		case "/repos/owner/retry-after/stargazers":
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"Resource not accessible by integration"}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithConfig(Config{BaseURL: server.URL})
+
+	tests := []struct {
+		repo        string
+		rateLimited bool
+		wait        time.Duration
+	}{
+		{"primary", true, time.Until(reset)},
+		{"secondary", true, secondaryRateLimitWait},
+		{"retry-after", true, 120 * time.Second},
+		{"forbidden", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			_, err := client.GetStargazers(context.Background(), "owner", tt.repo)
+			gitHubErr, ok := err.(*errors.GitHubAPIError)
+			if !ok {
+				t.Fatalf("Expected GitHubAPIError, got %v", err)
+			}
+
+			if gitHubErr.IsRateLimited() != tt.rateLimited {
+				t.Errorf("Expected IsRateLimited() = %v, got %v", tt.rateLimited, gitHubErr.IsRateLimited())
+			}
+
+			wait := gitHubErr.WaitDuration(time.Now())
+			if diff := wait - tt.wait; diff < -2*time.Second || diff > 2*time.Second {
+				t.Errorf("Expected wait of about %v, got %v", tt.wait, wait)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Now()
+
+	if got := parseRetryAfter("60", now); got != time.Minute {
+		t.Errorf("Expected 1m, got %v", got)
+	}
+	if got := parseRetryAfter(now.Add(90*time.Second).UTC().Format(http.TimeFormat), now); got < 89*time.Second || got > 90*time.Second {
+		t.Errorf("Expected about 90s, got %v", got)
+	}
+	if got := parseRetryAfter("soon", now); got != 0 {
+		t.Errorf("Expected 0 for invalid value, got %v", got)
+	}
+}
@@ -0,0 +1,164 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github-stars-notify/internal/errors"
+)
+
+// RateLimitTracker is implemented by token sources that want to learn the quota
+// left on a token from the X-RateLimit-* headers of each response
+type RateLimitTracker interface {
+	TrackRateLimit(token string, rateLimit RateLimit)
+}
+
+// RateLimitObserver receives per-token rate limit updates, typically for metrics.
+// token is redacted with RedactToken.
+type RateLimitObserver interface {
+	RecordGitHubTokenRateLimit(resource, token string, limit, remaining int)
+}
+
+// TokenPool rotates requests across several personal access tokens, routing each
+// request to the token with the most remaining quota on the rate limit resource
+// the request counts against. Tokens exhausted on that resource are parked until
+// its reset time.
+type TokenPool struct {
+	tokens   []*pooledToken
+	observer RateLimitObserver
+	mutex    sync.Mutex
+}
+
+// pooledToken tracks the last known quotas of a token
+type pooledToken struct {
+	token  string
+	quotas map[string]tokenQuota // By rate limit resource, such as "core" or "graphql"
+}
+
+// tokenQuota is the quota of a token on a rate limit resource
+type tokenQuota struct {
+	limit     int
+	remaining int
+	reset     time.Time
+}
+
+// rateLimitResourceKey is the context key of the rate limit resource of a request
+type rateLimitResourceKey struct{}
+
+// withRateLimitResource marks the requests made under ctx as counting against
+// the given rate limit resource
+func withRateLimitResource(ctx context.Context, resource string) context.Context {
+	return context.WithValue(ctx, rateLimitResourceKey{}, resource)
+}
+
+// rateLimitResource returns the rate limit resource of the requests made under
+// ctx, "core" unless set by withRateLimitResource
+func rateLimitResource(ctx context.Context) string {
+	if resource, ok := ctx.Value(rateLimitResourceKey{}).(string); ok {
+		return resource
+	}
+	return "core"
+}
+
+// NewTokenPool creates a token pool. The observer is optional.
+func NewTokenPool(tokens []string, observer RateLimitObserver) *TokenPool {
+	pool := &TokenPool{observer: observer}
+	for _, token := range tokens {
+		pool.tokens = append(pool.tokens, &pooledToken{token: token, quotas: make(map[string]tokenQuota)})
+	}
+	return pool
+}
+
+// Token returns the token with the most headroom on the rate limit resource of
+// the request. Owners are not taken into account since personal tokens are not
+// scoped to an installation.
+func (p *TokenPool) Token(ctx context.Context, owner string) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if len(p.tokens) == 0 {
+		return "", nil
+	}
+
+	now := time.Now()
+	resource := rateLimitResource(ctx)
+	var best *pooledToken
+	var earliestReset time.Time
+
+	for _, t := range p.tokens {
+		remaining, parked := t.headroom(resource, now)
+		if parked {
+			if reset := t.quotas[resource].reset; earliestReset.IsZero() || reset.Before(earliestReset) {
+				earliestReset = reset
+			}
+			continue
+		}
+
+		if best == nil {
+			best = t
+			continue
+		}
+		if bestRemaining, _ := best.headroom(resource, now); remaining > bestRemaining {
+			best = t
+		}
+	}
+
+	if best == nil {
+		apiErr := errors.NewGitHubAPIError("token_pool", http.StatusTooManyRequests,
+			fmt.Sprintf("all %d tokens are rate limited on %s until %s", len(p.tokens), resource, earliestReset.Format(time.RFC3339)), nil)
+		apiErr.ResetAt = earliestReset
+		return "", apiErr
+	}
+
+	return best.token, nil
+}
+
+// headroom returns the requests left on a token for a rate limit resource and
+// whether it is parked. Quotas not observed yet, or past their reset time,
+// count as fresh.
+func (t *pooledToken) headroom(resource string, now time.Time) (int, bool) {
+	quota, known := t.quotas[resource]
+	if !known || now.After(quota.reset) {
+		return int(^uint(0) >> 1), false
+	}
+	if quota.remaining <= 0 {
+		return 0, true
+	}
+	return quota.remaining, false
+}
+
+// TrackRateLimit records the quota reported for a token on the resource of the
+// response, "core" when the response does not name it
+func (p *TokenPool) TrackRateLimit(token string, rateLimit RateLimit) {
+	resource := rateLimit.Resource
+	if resource == "" {
+		resource = "core"
+	}
+
+	p.mutex.Lock()
+	for _, t := range p.tokens {
+		if t.token == token {
+			t.quotas[resource] = tokenQuota{
+				limit:     rateLimit.Limit,
+				remaining: rateLimit.Remaining,
+				reset:     rateLimit.Reset,
+			}
+		}
+	}
+	p.mutex.Unlock()
+
+	if p.observer != nil {
+		p.observer.RecordGitHubTokenRateLimit(resource, RedactToken(token), rateLimit.Limit, rateLimit.Remaining)
+	}
+}
+
+// RedactToken returns an identifier for a token that is safe to log or export
+func RedactToken(token string) string {
+	if len(token) <= 4 {
+		return "****"
+	}
+	return "****" + token[len(token)-4:]
+}
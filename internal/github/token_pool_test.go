@@ -0,0 +1,123 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordingRateLimitObserver struct {
+	remaining map[string]int
+}
+
+func (o *recordingRateLimitObserver) RecordGitHubTokenRateLimit(resource, token string, limit, remaining int) {
+	o.remaining[resource+"/"+token] = remaining
+}
+
+func TestTokenPoolRotation(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	quota := map[string]int{"token-aaaa": 3, "token-bbbb": 1}
+	used := make(map[string]int)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "token ")
+		used[token]++
+		quota[token]--
+
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(quota[token]))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		w.Header().Set("X-RateLimit-Resource", "core")
+		fmt.Fprint(w, `[]`)
+	}))
+	defer server.Close()
+
+	observer := &recordingRateLimitObserver{remaining: make(map[string]int)}
+	pool := NewTokenPool([]string{"token-aaaa", "token-bbbb"}, observer)
+	client := NewClientWithConfig(Config{BaseURL: server.URL, TokenSource: pool})
+
+	// Both tokens are tried once, then requests go to the token with headroom
+	// until both are exhausted
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetStargazers(context.Background(), "owner", "repo"); err != nil {
+			t.Fatalf("Request %d failed: %v", i, err)
+		}
+	}
+	if used["token-aaaa"] != 1 || used["token-bbbb"] != 1 {
+		t.Fatalf("Expected both tokens to be used once, got %v", used)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetStargazers(context.Background(), "owner", "repo"); err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+	}
+	if used["token-aaaa"] != 3 {
+		t.Errorf("Expected token with most headroom to be preferred, got %v", used)
+	}
+
+	// All tokens are parked until their reset
+	_, err := client.GetStargazers(context.Background(), "owner", "repo")
+	if err == nil || !strings.Contains(err.Error(), "rate limited") {
+		t.Errorf("Expected rate limit error with all tokens exhausted, got %v", err)
+	}
+
+	if remaining, ok := observer.remaining["core/****aaaa"]; !ok || remaining != 0 {
+		t.Errorf("Expected redacted per-token metric, got %v", observer.remaining)
+	}
+}
+
+func TestTokenPoolUnparksAfterReset(t *testing.T) {
+	pool := NewTokenPool([]string{"token-aaaa"}, nil)
+	pool.TrackRateLimit("token-aaaa", RateLimit{Limit: 5000, Remaining: 0, Reset: time.Now().Add(-time.Second)})
+
+	token, err := pool.Token(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Expected token to be available after reset: %v", err)
+	}
+	if token != "token-aaaa" {
+		t.Errorf("Expected token-aaaa, got %s", token)
+	}
+}
+
+func TestTokenPoolResources(t *testing.T) {
+	pool := NewTokenPool([]string{"token-aaaa", "token-bbbb"}, nil)
+	reset := time.Now().Add(time.Hour)
+
+	// token-aaaa ran out of GraphQL quota but has plenty of REST quota left
+	pool.TrackRateLimit("token-aaaa", RateLimit{Resource: "core", Limit: 5000, Remaining: 4000, Reset: reset})
+	pool.TrackRateLimit("token-aaaa", RateLimit{Resource: "graphql", Limit: 5000, Remaining: 0, Reset: reset})
+	pool.TrackRateLimit("token-bbbb", RateLimit{Resource: "graphql", Limit: 5000, Remaining: 3000, Reset: reset})
+	pool.TrackRateLimit("token-bbbb", RateLimit{Limit: 5000, Remaining: 100, Reset: reset})
+
+	ctx := context.Background()
+	if token, err := pool.Token(ctx, ""); err != nil || token != "token-aaaa" {
+		t.Errorf("Expected REST requests on token-aaaa, got %s (%v)", token, err)
+	}
+	if token, err := pool.Token(withRateLimitResource(ctx, "graphql"), ""); err != nil || token != "token-bbbb" {
+		t.Errorf("Expected GraphQL requests on token-bbbb, got %s (%v)", token, err)
+	}
+
+	// Exhausting the GraphQL quota of both tokens leaves REST requests unaffected
+	pool.TrackRateLimit("token-bbbb", RateLimit{Resource: "graphql", Limit: 5000, Remaining: 0, Reset: reset})
+	if _, err := pool.Token(withRateLimitResource(ctx, "graphql"), ""); err == nil || !strings.Contains(err.Error(), "graphql") {
+		t.Errorf("Expected GraphQL requests to be rate limited, got %v", err)
+	}
+	if token, err := pool.Token(ctx, ""); err != nil || token != "token-aaaa" {
+		t.Errorf("Expected REST requests to keep using token-aaaa, got %s (%v)", token, err)
+	}
+}
+
+func TestRedactToken(t *testing.T) {
+	if got := RedactToken("ghp_abcdef1234"); got != "****1234" {
+		t.Errorf("Expected ****1234, got %s", got)
+	}
+	if got := RedactToken("abc"); got != "****" {
+		t.Errorf("Expected short tokens to be fully redacted, got %s", got)
+	}
+}
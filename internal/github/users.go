@@ -0,0 +1,43 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github-stars-notify/internal/errors"
+)
+
+// UserProfile represents the public profile of a GitHub user
+type UserProfile struct {
+	Login       string    `json:"login"`
+	ID          int64     `json:"id"`
+	Name        string    `json:"name,omitempty"`
+	Company     string    `json:"company,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	Bio         string    `json:"bio,omitempty"`
+	Followers   int       `json:"followers"`
+	PublicRepos int       `json:"public_repos"`
+	HTMLURL     string    `json:"html_url,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// GetUser fetches the public profile of a user
+func (c *Client) GetUser(ctx context.Context, login string) (*UserProfile, error) {
+	endpoint := fmt.Sprintf("/users/%s", login)
+	url := c.baseURL + endpoint
+
+	resp, err := c.get(ctx, "", "users", endpoint, url, "application/vnd.github+json")
+	if err != nil {
+		return nil, err
+	}
+
+	var profile UserProfile
+	if err := json.Unmarshal(resp.Body, &profile); err != nil {
+		return nil, errors.NewGitHubAPIError(endpoint, resp.StatusCode,
+			"failed to decode response", err)
+	}
+
+	return &profile, nil
+}
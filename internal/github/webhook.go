@@ -0,0 +1,76 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github-stars-notify/internal/errors"
+)
+
+// Webhook event names, from the X-GitHub-Event header
+const (
+	WebhookEventPing  = "ping"
+	WebhookEventWatch = "watch" // Sent with action "started" when a repository is starred
+	WebhookEventStar  = "star"  // Sent with action "created" or "deleted"
+)
+
+// StarEvent is the payload of a watch or star webhook delivery
+type StarEvent struct {
+	Action     string     `json:"action"`
+	StarredAt  *time.Time `json:"starred_at"` // Only set by star events, null when deleted
+	Repository Repository `json:"repository"`
+	Sender     Stargazer  `json:"sender"`
+}
+
+// ParseStarEvent decodes the payload of a watch or star webhook delivery
+func ParseStarEvent(payload []byte) (*StarEvent, error) {
+	var event StarEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, errors.NewGitHubAPIError("webhook", 0, "failed to decode payload", err)
+	}
+	if event.Repository.FullName == "" || event.Sender.ID == 0 {
+		return nil, errors.NewGitHubAPIError("webhook", 0, "payload is missing the repository or sender", nil)
+	}
+	return &event, nil
+}
+
+// IsStarred reports whether the event announces a new star
+func (e *StarEvent) IsStarred() bool {
+	return e.Action == "started" || e.Action == "created"
+}
+
+// Stargazer returns the user who starred the repository. Watch events carry no
+// timestamp, the time of delivery is used instead.
+func (e *StarEvent) Stargazer(now time.Time) Stargazer {
+	stargazer := e.Sender
+	stargazer.StarredAt = now
+	if e.StarredAt != nil {
+		stargazer.StarredAt = *e.StarredAt
+	}
+	return stargazer
+}
+
+// VerifyWebhookSignature checks the X-Hub-Signature-256 header of a delivery
+// against the HMAC-SHA256 of its payload
+func VerifyWebhookSignature(secret string, payload []byte, signature string) bool {
+	if secret == "" {
+		return false
+	}
+
+	digest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	expected, err := hex.DecodeString(digest)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
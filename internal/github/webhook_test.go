@@ -0,0 +1,70 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	payload := []byte(`{"action":"started"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(payload)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !VerifyWebhookSignature("secret", payload, signature) {
+		t.Error("Expected a valid signature to be accepted")
+	}
+
+	tests := []struct {
+		name      string
+		secret    string
+		payload   []byte
+		signature string
+	}{
+		{"wrong secret", "other", payload, signature},
+		{"tampered payload", "secret", []byte(`{"action":"deleted"}`), signature},
+		{"missing prefix", "secret", payload, hex.EncodeToString(mac.Sum(nil))},
+		{"not hex", "secret", payload, "sha256=zz"},
+		{"no secret", "", payload, signature},
+	}
+	for _, tt := range tests {
+		if VerifyWebhookSignature(tt.secret, tt.payload, tt.signature) {
+			t.Errorf("%s: expected the signature to be rejected", tt.name)
+		}
+	}
+}
+
+func TestParseStarEvent(t *testing.T) {
+	now := time.Now()
+
+	watch, err := ParseStarEvent([]byte(`{"action":"started","repository":{"id":1,"full_name":"octocat/hello"},"sender":{"login":"alice","id":7}}`))
+	if err != nil {
+		t.Fatalf("ParseStarEvent failed: %v", err)
+	}
+	if !watch.IsStarred() || watch.Stargazer(now).Login != "alice" || !watch.Stargazer(now).StarredAt.Equal(now) {
+		t.Errorf("Unexpected watch event: %+v", watch)
+	}
+
+	star, err := ParseStarEvent([]byte(`{"action":"created","starred_at":"2024-05-01T10:00:00Z","repository":{"id":1,"full_name":"octocat/hello"},"sender":{"login":"bob","id":8}}`))
+	if err != nil {
+		t.Fatalf("ParseStarEvent failed: %v", err)
+	}
+	if got := star.Stargazer(now).StarredAt; got.Year() != 2024 {
+		t.Errorf("Expected the star time of the payload, got %v", got)
+	}
+
+	deleted, err := ParseStarEvent([]byte(`{"action":"deleted","starred_at":null,"repository":{"id":1,"full_name":"octocat/hello"},"sender":{"login":"bob","id":8}}`))
+	if err != nil {
+		t.Fatalf("ParseStarEvent failed: %v", err)
+	}
+	if deleted.IsStarred() {
+		t.Error("Expected a deleted star not to count as starred")
+	}
+
+	if _, err := ParseStarEvent([]byte(`{"action":"started"}`)); err == nil {
+		t.Error("Expected an error for a payload without repository")
+	}
+}
@@ -11,18 +11,36 @@ import (
 // Metrics holds all the Prometheus metrics for the GitHub Stars Notify service
 type Metrics struct {
 	// Repository metrics
-	TotalStars    *prometheus.GaugeVec
-	NewStars      *prometheus.CounterVec
-	CheckDuration *prometheus.HistogramVec
-	LastCheckTime *prometheus.GaugeVec
-	ChecksTotal   *prometheus.CounterVec
-	CheckErrors   *prometheus.CounterVec
+	TotalStars       *prometheus.GaugeVec
+	NewStars         *prometheus.CounterVec
+	LostStars        *prometheus.CounterVec
+	TotalForks       *prometheus.GaugeVec
+	NewForks         *prometheus.CounterVec
+	NewReleases      *prometheus.CounterVec
+	TotalFollowers   *prometheus.GaugeVec
+	NewFollowers     *prometheus.CounterVec
+	ReleaseDownloads *prometheus.GaugeVec
+	CheckDuration    *prometheus.HistogramVec
+	LastCheckTime    *prometheus.GaugeVec
+	ChecksTotal      *prometheus.CounterVec
+	CheckErrors      *prometheus.CounterVec
+	StarPrechecks    *prometheus.CounterVec
+
+	// Check cycle metrics
+	CycleDuration    prometheus.Histogram
+	ChecksInFlight   prometheus.Gauge
+	CheckConcurrency prometheus.Gauge
+	ChecksSkipped    *prometheus.CounterVec
 
 	// GitHub API metrics
-	GitHubAPIRequests        *prometheus.CounterVec
-	GitHubAPIErrors          *prometheus.CounterVec
-	GitHubRateLimit          *prometheus.GaugeVec
-	GitHubRateLimitRemaining *prometheus.GaugeVec
+	GitHubAPIRequests             *prometheus.CounterVec
+	GitHubAPIErrors               *prometheus.CounterVec
+	GitHubRateLimit               *prometheus.GaugeVec
+	GitHubRateLimitRemaining      *prometheus.GaugeVec
+	GitHubTokenRateLimit          *prometheus.GaugeVec
+	GitHubTokenRateLimitRemaining *prometheus.GaugeVec
+	GitHubCacheRequests           *prometheus.CounterVec
+	WebhookDeliveries             *prometheus.CounterVec
 
 	// Notification metrics (provider-agnostic)
 	NotificationsSent   *prometheus.CounterVec
@@ -70,6 +88,55 @@ func NewMetricsWithRegistry(registry *prometheus.Registry) *Metrics {
 			},
 			[]string{"owner", "repo"},
 		),
+		LostStars: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "github_stars_lost_total",
+				Help: "Total number of removed stars detected",
+			},
+			[]string{"owner", "repo"},
+		),
+		TotalForks: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "github_forks_total",
+				Help: "Total number of forks for each repository",
+			},
+			[]string{"owner", "repo"},
+		),
+		NewForks: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "github_forks_new_total",
+				Help: "Total number of new forks detected",
+			},
+			[]string{"owner", "repo"},
+		),
+		NewReleases: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "github_releases_new_total",
+				Help: "Total number of new releases detected",
+			},
+			[]string{"owner", "repo"},
+		),
+		ReleaseDownloads: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "github_release_downloads",
+				Help: "Total asset downloads of the most recent releases of each repository",
+			},
+			[]string{"owner", "repo"},
+		),
+		TotalFollowers: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "github_followers_total",
+				Help: "Total number of followers for each watched user or organization",
+			},
+			[]string{"login"},
+		),
+		NewFollowers: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "github_followers_new_total",
+				Help: "Total number of new followers detected",
+			},
+			[]string{"login"},
+		),
 		CheckDuration: factory.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Name:    "github_stars_check_duration_seconds",
@@ -99,6 +166,41 @@ func NewMetricsWithRegistry(registry *prometheus.Registry) *Metrics {
 			},
 			[]string{"owner", "repo", "error_type"},
 		),
+		StarPrechecks: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "github_stars_precheck_total",
+				Help: "Total number of star count prechecks by outcome (skipped or fetched)",
+			},
+			[]string{"owner", "repo", "result"},
+		),
+
+		// Check cycle metrics
+		CycleDuration: factory.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "github_stars_cycle_duration_seconds",
+				Help:    "Duration of check cycles in seconds",
+				Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+			},
+		),
+		ChecksInFlight: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "github_stars_checks_in_flight",
+				Help: "Number of repository and follower checks currently running",
+			},
+		),
+		CheckConcurrency: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "github_stars_check_concurrency",
+				Help: "Number of workers used by the current check cycle",
+			},
+		),
+		ChecksSkipped: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "github_stars_checks_skipped_total",
+				Help: "Total number of checks skipped by a check cycle",
+			},
+			[]string{"reason"},
+		),
 
 		// GitHub API metrics
 		GitHubAPIRequests: factory.NewCounterVec(
@@ -129,6 +231,34 @@ func NewMetricsWithRegistry(registry *prometheus.Registry) *Metrics {
 			},
 			[]string{"resource"},
 		),
+		GitHubTokenRateLimit: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "github_api_token_rate_limit_limit",
+				Help: "GitHub API rate limit limit of each pooled token",
+			},
+			[]string{"resource", "token"},
+		),
+		GitHubTokenRateLimitRemaining: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "github_api_token_rate_limit_remaining",
+				Help: "GitHub API rate limit remaining of each pooled token",
+			},
+			[]string{"resource", "token"},
+		),
+		GitHubCacheRequests: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "github_api_cache_requests_total",
+				Help: "Total number of cacheable GitHub API requests by cache result",
+			},
+			[]string{"endpoint", "result"},
+		),
+		WebhookDeliveries: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "github_webhook_deliveries_total",
+				Help: "Total number of GitHub webhook deliveries received by result",
+			},
+			[]string{"event", "result"},
+		),
 
 		// Notification metrics (provider-agnostic)
 		NotificationsSent: factory.NewCounterVec(
@@ -181,11 +311,46 @@ func (m *Metrics) RecordRepositoryStars(owner, repo string, stars int) {
 	m.TotalStars.WithLabelValues(owner, repo).Set(float64(stars))
 }
 
+// RecordRepositoryForks records the total number of forks for a repository
+func (m *Metrics) RecordRepositoryForks(owner, repo string, forks int) {
+	m.TotalForks.WithLabelValues(owner, repo).Set(float64(forks))
+}
+
+// RecordNewForks records new forks detected for a repository
+func (m *Metrics) RecordNewForks(owner, repo string, newForks int) {
+	m.NewForks.WithLabelValues(owner, repo).Add(float64(newForks))
+}
+
+// RecordNewReleases records new releases detected for a repository
+func (m *Metrics) RecordNewReleases(owner, repo string, newReleases int) {
+	m.NewReleases.WithLabelValues(owner, repo).Add(float64(newReleases))
+}
+
+// RecordReleaseDownloads records the asset downloads of a repository's recent releases
+func (m *Metrics) RecordReleaseDownloads(owner, repo string, downloads int) {
+	m.ReleaseDownloads.WithLabelValues(owner, repo).Set(float64(downloads))
+}
+
+// RecordFollowers records the total number of followers for a user or organization
+func (m *Metrics) RecordFollowers(login string, followers int) {
+	m.TotalFollowers.WithLabelValues(login).Set(float64(followers))
+}
+
+// RecordNewFollowers records new followers detected for a user or organization
+func (m *Metrics) RecordNewFollowers(login string, newFollowers int) {
+	m.NewFollowers.WithLabelValues(login).Add(float64(newFollowers))
+}
+
 // RecordNewStars records new stars detected for a repository
 func (m *Metrics) RecordNewStars(owner, repo string, newStars int) {
 	m.NewStars.WithLabelValues(owner, repo).Add(float64(newStars))
 }
 
+// RecordLostStars records removed stars detected for a repository
+func (m *Metrics) RecordLostStars(owner, repo string, lostStars int) {
+	m.LostStars.WithLabelValues(owner, repo).Add(float64(lostStars))
+}
+
 // RecordCheckDuration records the duration of a repository check
 func (m *Metrics) RecordCheckDuration(owner, repo string, duration time.Duration) {
 	m.CheckDuration.WithLabelValues(owner, repo).Observe(duration.Seconds())
@@ -206,6 +371,37 @@ func (m *Metrics) RecordCheckError(owner, repo, errorType string) {
 	m.CheckErrors.WithLabelValues(owner, repo, errorType).Inc()
 }
 
+// RecordCycleDuration records the duration of a check cycle
+func (m *Metrics) RecordCycleDuration(duration time.Duration) {
+	m.CycleDuration.Observe(duration.Seconds())
+}
+
+// RecordStarPrecheck records whether the star count precheck of a repository
+// skipped the stargazer download or let it run
+func (m *Metrics) RecordStarPrecheck(owner, repo, result string) {
+	m.StarPrechecks.WithLabelValues(owner, repo, result).Inc()
+}
+
+// RecordCheckStarted records a check entering the worker pool
+func (m *Metrics) RecordCheckStarted() {
+	m.ChecksInFlight.Inc()
+}
+
+// RecordCheckFinished records a check leaving the worker pool
+func (m *Metrics) RecordCheckFinished() {
+	m.ChecksInFlight.Dec()
+}
+
+// RecordCheckConcurrency records the number of workers of the current check cycle
+func (m *Metrics) RecordCheckConcurrency(workers int) {
+	m.CheckConcurrency.Set(float64(workers))
+}
+
+// RecordChecksSkipped records checks a cycle did not run
+func (m *Metrics) RecordChecksSkipped(reason string, count int) {
+	m.ChecksSkipped.WithLabelValues(reason).Add(float64(count))
+}
+
 // RecordGitHubAPIRequest records a GitHub API request
 func (m *Metrics) RecordGitHubAPIRequest(endpoint, status string) {
 	m.GitHubAPIRequests.WithLabelValues(endpoint, status).Inc()
@@ -222,6 +418,28 @@ func (m *Metrics) RecordGitHubRateLimit(resource string, limit, remaining int) {
 	m.GitHubRateLimitRemaining.WithLabelValues(resource).Set(float64(remaining))
 }
 
+// RecordGitHubTokenRateLimit records the rate limit of a pooled token,
+// identified by its redacted form
+func (m *Metrics) RecordGitHubTokenRateLimit(resource, token string, limit, remaining int) {
+	m.GitHubTokenRateLimit.WithLabelValues(resource, token).Set(float64(limit))
+	m.GitHubTokenRateLimitRemaining.WithLabelValues(resource, token).Set(float64(remaining))
+}
+
+// RecordGitHubCacheHit records a GitHub API response served from the cache (304 Not Modified)
+func (m *Metrics) RecordGitHubCacheHit(endpoint string) {
+	m.GitHubCacheRequests.WithLabelValues(endpoint, "hit").Inc()
+}
+
+// RecordGitHubCacheMiss records a GitHub API response that had to be downloaded
+func (m *Metrics) RecordGitHubCacheMiss(endpoint string) {
+	m.GitHubCacheRequests.WithLabelValues(endpoint, "miss").Inc()
+}
+
+// RecordWebhookDelivery records a GitHub webhook delivery and how it was handled
+func (m *Metrics) RecordWebhookDelivery(event, result string) {
+	m.WebhookDeliveries.WithLabelValues(event, result).Inc()
+}
+
 // RecordNotificationSent records a notification attempt
 func (m *Metrics) RecordNotificationSent(provider, status string) {
 	m.NotificationsSent.WithLabelValues(provider, status).Inc()
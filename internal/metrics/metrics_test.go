@@ -16,15 +16,34 @@ func TestMetricsBasic(t *testing.T) {
 	// Test repository metrics
 	m.RecordRepositoryStars("facebook", "react", 100)
 	m.RecordNewStars("facebook", "react", 5)
+	m.RecordLostStars("facebook", "react", 2)
+	m.RecordRepositoryForks("facebook", "react", 40)
+	m.RecordNewForks("facebook", "react", 3)
+	m.RecordNewReleases("facebook", "react", 1)
+	m.RecordReleaseDownloads("facebook", "react", 250)
+	m.RecordFollowers("facebook", 900)
+	m.RecordNewFollowers("facebook", 4)
 	m.RecordCheckDuration("facebook", "react", time.Second*2)
 	m.RecordLastCheckTime("facebook", "react")
 	m.RecordCheck("facebook", "react", "success")
 	m.RecordCheckError("facebook", "react", "api_error")
+	m.RecordStarPrecheck("facebook", "react", "skipped")
+	m.RecordStarPrecheck("facebook", "react", "skipped")
+	m.RecordCycleDuration(time.Minute)
+	m.RecordCheckStarted()
+	m.RecordCheckStarted()
+	m.RecordCheckFinished()
+	m.RecordCheckConcurrency(4)
+	m.RecordChecksSkipped("cycle_deadline", 3)
 
 	// Test GitHub API metrics
 	m.RecordGitHubAPIRequest("stargazers", "success")
 	m.RecordGitHubAPIError("stargazers", "timeout")
 	m.RecordGitHubRateLimit("core", 5000, 4900)
+	m.RecordGitHubTokenRateLimit("core", "****aaaa", 5000, 4900)
+	m.RecordGitHubCacheHit("stargazers")
+	m.RecordGitHubCacheMiss("stargazers")
+	m.RecordWebhookDelivery("star", "processed")
 
 	// Test notification metrics (provider-agnostic)
 	m.RecordNotificationSent("discord", "success")
@@ -50,12 +69,51 @@ func TestMetricsBasic(t *testing.T) {
 	if testutil.ToFloat64(m.NewStars.WithLabelValues("facebook", "react")) != 5 {
 		t.Error("New stars not recorded correctly")
 	}
+	if testutil.ToFloat64(m.LostStars.WithLabelValues("facebook", "react")) != 2 {
+		t.Error("Lost stars not recorded correctly")
+	}
+	if testutil.ToFloat64(m.TotalForks.WithLabelValues("facebook", "react")) != 40 {
+		t.Error("Repository forks not recorded correctly")
+	}
+	if testutil.ToFloat64(m.NewForks.WithLabelValues("facebook", "react")) != 3 {
+		t.Error("New forks not recorded correctly")
+	}
+	if testutil.ToFloat64(m.NewReleases.WithLabelValues("facebook", "react")) != 1 {
+		t.Error("New releases not recorded correctly")
+	}
+	if testutil.ToFloat64(m.ReleaseDownloads.WithLabelValues("facebook", "react")) != 250 {
+		t.Error("Release downloads not recorded correctly")
+	}
+	if testutil.ToFloat64(m.TotalFollowers.WithLabelValues("facebook")) != 900 {
+		t.Error("Followers not recorded correctly")
+	}
+	if testutil.ToFloat64(m.NewFollowers.WithLabelValues("facebook")) != 4 {
+		t.Error("New followers not recorded correctly")
+	}
 	if testutil.ToFloat64(m.ChecksTotal.WithLabelValues("facebook", "react", "success")) != 1 {
 		t.Error("Check not recorded correctly")
 	}
+	if testutil.ToFloat64(m.StarPrechecks.WithLabelValues("facebook", "react", "skipped")) != 2 {
+		t.Error("Star precheck not recorded correctly")
+	}
+	if testutil.ToFloat64(m.ChecksInFlight) != 1 {
+		t.Error("Checks in flight not recorded correctly")
+	}
+	if testutil.ToFloat64(m.CheckConcurrency) != 4 {
+		t.Error("Check concurrency not recorded correctly")
+	}
+	if testutil.ToFloat64(m.ChecksSkipped.WithLabelValues("cycle_deadline")) != 3 {
+		t.Error("Skipped checks not recorded correctly")
+	}
 	if testutil.ToFloat64(m.GitHubRateLimit.WithLabelValues("core")) != 5000 {
 		t.Error("Rate limit not recorded correctly")
 	}
+	if testutil.ToFloat64(m.GitHubCacheRequests.WithLabelValues("stargazers", "hit")) != 1 {
+		t.Error("Cache hit not recorded correctly")
+	}
+	if testutil.ToFloat64(m.WebhookDeliveries.WithLabelValues("star", "processed")) != 1 {
+		t.Error("Webhook delivery not recorded correctly")
+	}
 	if testutil.ToFloat64(m.NotificationsSent.WithLabelValues("discord", "success")) != 2 {
 		t.Error("Discord notification not recorded correctly")
 	}
@@ -33,6 +33,7 @@ type DiscordMessage struct {
 // DiscordEmbed represents a Discord embed
 type DiscordEmbed struct {
 	Title       string              `json:"title,omitempty"`
+	URL         string              `json:"url,omitempty"`
 	Description string              `json:"description,omitempty"`
 	Color       int                 `json:"color,omitempty"`
 	Fields      []DiscordEmbedField `json:"fields,omitempty"`
@@ -85,19 +86,48 @@ func (d *DiscordNotifier) GetProviderName() string {
 	return ProviderDiscord
 }
 
-// NotifyNewStars sends a notification about new stars with context support
-func (d *DiscordNotifier) NotifyNewStars(ctx context.Context, owner, repo string, newStargazers []github.Stargazer) error {
-	if len(newStargazers) == 0 {
+// Notify renders an event as a Discord message and sends it with context support
+func (d *DiscordNotifier) Notify(ctx context.Context, event Event) error {
+	if event.IsEmpty() {
+		return nil
+	}
+
+	webURL := webURLFromContext(ctx)
+	owner, repo := event.Owner, event.Repo
+
+	var message DiscordMessage
+	switch event.Kind {
+	case EventNewStars:
+		message = d.createMessage(webURL, owner, repo, event.Stargazers)
+	case EventLostStars:
+		message = d.createLostStarsMessage(webURL, owner, repo, event.Stargazers)
+	case EventNewForks:
+		message = d.createForksMessage(webURL, owner, repo, event.Forks)
+	case EventReleases:
+		message = d.createReleasesMessage(webURL, owner, repo, event.Releases)
+	case EventFollowers:
+		message = d.createFollowersMessage(webURL, owner, event.Stargazers)
+	case EventMilestone:
+		message = d.createMilestoneMessage(webURL, owner, repo, event.Milestone, event.Stars)
+	case EventRenamed:
+		message = d.createRenamedMessage(webURL, owner, repo, event.RenamedTo)
+	case EventSuspended:
+		message = d.createSuspendedMessage(owner, repo)
+	default:
 		return nil
 	}
 
-	message := d.createMessage(owner, repo, newStargazers)
 	return d.sendMessage(ctx, message)
 }
 
+// NotifyNewStars sends a notification about new stars with context support
+func (d *DiscordNotifier) NotifyNewStars(ctx context.Context, owner, repo string, newStargazers []github.Stargazer) error {
+	return d.Notify(ctx, NewStarsEvent(owner, repo, newStargazers))
+}
+
 // createMessage creates a Discord message for new stars
-func (d *DiscordNotifier) createMessage(owner, repo string, newStargazers []github.Stargazer) DiscordMessage {
-	repoURL := fmt.Sprintf("https://github.com/%s/%s", owner, repo)
+func (d *DiscordNotifier) createMessage(webURL, owner, repo string, newStargazers []github.Stargazer) DiscordMessage {
+	repoURL := repositoryURL(webURL, owner, repo)
 
 	var description string
 	if len(newStargazers) == 1 {
@@ -128,9 +158,9 @@ func (d *DiscordNotifier) createMessage(owner, repo string, newStargazers []gith
 			break
 		}
 
-		stargazerURL := fmt.Sprintf("https://github.com/%s", sg.Login)
+		stargazerURL := profileURL(webURL, sg)
 		embed.Fields = append(embed.Fields, DiscordEmbedField{
-			Name:   fmt.Sprintf("⭐ %s", sg.Login),
+			Name:   fmt.Sprintf("⭐ %s", describeStargazer(sg)),
 			Value:  fmt.Sprintf("[View Profile](%s)", stargazerURL),
 			Inline: true,
 		})
@@ -141,6 +171,214 @@ func (d *DiscordNotifier) createMessage(owner, repo string, newStargazers []gith
 	}
 }
 
+// createLostStarsMessage creates a Discord message for removed stars
+func (d *DiscordNotifier) createLostStarsMessage(webURL, owner, repo string, lostStargazers []github.Stargazer) DiscordMessage {
+	repoURL := repositoryURL(webURL, owner, repo)
+
+	description := fmt.Sprintf("💔 **-%d %s** on [%s/%s](%s): %s",
+		len(lostStargazers), pluralize(len(lostStargazers), "star", "stars"),
+		owner, repo, repoURL, joinLogins(lostStargazers, 10))
+
+	embed := DiscordEmbed{
+		Title:       "Lost GitHub Stars",
+		Description: description,
+		Color:       0xff0000, // Red color
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Footer: &DiscordEmbedFooter{
+			Text: "GitHub Stars Notify",
+		},
+	}
+
+	return DiscordMessage{
+		Embeds: []DiscordEmbed{embed},
+	}
+}
+
+// createForksMessage creates a Discord message for new forks
+func (d *DiscordNotifier) createForksMessage(webURL, owner, repo string, forks []github.Repository) DiscordMessage {
+	repoURL := repositoryURL(webURL, owner, repo)
+
+	description := fmt.Sprintf("🍴 **%d new %s** of [%s/%s](%s)!",
+		len(forks), pluralize(len(forks), "fork", "forks"), owner, repo, repoURL)
+
+	embed := DiscordEmbed{
+		Title:       "New GitHub Forks",
+		Description: description,
+		Color:       0x2f81f7, // Blue color
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Footer: &DiscordEmbedFooter{
+			Text: "GitHub Stars Notify",
+		},
+	}
+
+	// Add fields for each fork (limit to 10 to avoid message size limits)
+	maxForks := 10
+	for i, fork := range forks {
+		if i >= maxForks {
+			embed.Fields = append(embed.Fields, DiscordEmbedField{
+				Name:   "And more...",
+				Value:  fmt.Sprintf("+ %d more forks", len(forks)-maxForks),
+				Inline: false,
+			})
+			break
+		}
+
+		embed.Fields = append(embed.Fields, DiscordEmbedField{
+			Name:   fmt.Sprintf("🍴 %s", fork.FullName),
+			Value:  fmt.Sprintf("[View Fork](%s)", forkURL(webURL, fork)),
+			Inline: true,
+		})
+	}
+
+	return DiscordMessage{
+		Embeds: []DiscordEmbed{embed},
+	}
+}
+
+// createReleasesMessage creates a Discord message with one embed per new release
+func (d *DiscordNotifier) createReleasesMessage(webURL, owner, repo string, releases []github.Release) DiscordMessage {
+	var embeds []DiscordEmbed
+
+	// Discord allows at most 10 embeds per message
+	maxReleases := 10
+	for i, release := range releases {
+		if i >= maxReleases {
+			break
+		}
+
+		embed := DiscordEmbed{
+			Title:       fmt.Sprintf("📦 %s/%s %s", owner, repo, releaseTitle(release)),
+			URL:         releaseURL(webURL, owner, repo, release),
+			Description: truncate(release.Body, 500),
+			Color:       0x8957e5, // Purple color
+			Timestamp:   time.Now().Format(time.RFC3339),
+			Footer: &DiscordEmbedFooter{
+				Text: "GitHub Stars Notify",
+			},
+		}
+
+		if label := releaseLabel(release); label != "" {
+			embed.Fields = append(embed.Fields, DiscordEmbedField{
+				Name:   "Type",
+				Value:  label,
+				Inline: true,
+			})
+		}
+		if len(release.Assets) > 0 {
+			embed.Fields = append(embed.Fields, DiscordEmbedField{
+				Name:   fmt.Sprintf("Assets (%s %s)", formatCount(release.DownloadCount()), pluralize(release.DownloadCount(), "download", "downloads")),
+				Value:  describeAssets(release, 5),
+				Inline: false,
+			})
+		}
+
+		embeds = append(embeds, embed)
+	}
+
+	var content string
+	if len(releases) > maxReleases {
+		content = fmt.Sprintf("+ %d more releases of %s/%s", len(releases)-maxReleases, owner, repo)
+	}
+
+	return DiscordMessage{
+		Content: content,
+		Embeds:  embeds,
+	}
+}
+
+// createFollowersMessage creates a Discord message for new followers of an account
+func (d *DiscordNotifier) createFollowersMessage(webURL, login string, followers []github.Stargazer) DiscordMessage {
+	description := fmt.Sprintf("👥 **%d new %s** for [%s](%s)!",
+		len(followers), pluralize(len(followers), "follower", "followers"), login, accountURL(webURL, login))
+
+	embed := DiscordEmbed{
+		Title:       "New GitHub Followers",
+		Description: description,
+		Color:       0x1f883d, // Dark green color
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Footer: &DiscordEmbedFooter{
+			Text: "GitHub Stars Notify",
+		},
+	}
+
+	// Add fields for each new follower (limit to 10 to avoid message size limits)
+	maxFollowers := 10
+	for i, follower := range followers {
+		if i >= maxFollowers {
+			embed.Fields = append(embed.Fields, DiscordEmbedField{
+				Name:   "And more...",
+				Value:  fmt.Sprintf("+ %d more followers", len(followers)-maxFollowers),
+				Inline: false,
+			})
+			break
+		}
+
+		embed.Fields = append(embed.Fields, DiscordEmbedField{
+			Name:   fmt.Sprintf("👤 %s", describeStargazer(follower)),
+			Value:  fmt.Sprintf("[View Profile](%s)", profileURL(webURL, follower)),
+			Inline: true,
+		})
+	}
+
+	return DiscordMessage{
+		Embeds: []DiscordEmbed{embed},
+	}
+}
+
+// createMilestoneMessage creates a Discord message celebrating a star milestone
+func (d *DiscordNotifier) createMilestoneMessage(webURL, owner, repo string, milestone, stars int) DiscordMessage {
+	repoURL := repositoryURL(webURL, owner, repo)
+
+	embed := DiscordEmbed{
+		Title:       fmt.Sprintf("🏆 %s/%s reached %s!", owner, repo, formatMilestone(milestone)),
+		Description: fmt.Sprintf("🎉 [%s/%s](%s) now has **%d stars**. Thank you to every stargazer!", owner, repo, repoURL, stars),
+		Color:       0xffd700, // Gold color
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Footer: &DiscordEmbedFooter{
+			Text: "GitHub Stars Notify",
+		},
+	}
+
+	return DiscordMessage{
+		Content: fmt.Sprintf("🎉 **Milestone reached: %s/%s hit %s!**", owner, repo, formatMilestone(milestone)),
+		Embeds:  []DiscordEmbed{embed},
+	}
+}
+
+// createRenamedMessage creates a Discord message for a renamed or transferred repository
+func (d *DiscordNotifier) createRenamedMessage(webURL, owner, repo, renamedTo string) DiscordMessage {
+	embed := DiscordEmbed{
+		Title:       "🔀 " + describeRename(owner, repo, renamedTo),
+		Description: fmt.Sprintf("Stars are now tracked for [%s](%s). Update the configuration to use the new name.", renamedTo, webURL+"/"+renamedTo),
+		Color:       0x0099ff, // Blue color
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Footer: &DiscordEmbedFooter{
+			Text: "GitHub Stars Notify",
+		},
+	}
+
+	return DiscordMessage{
+		Embeds: []DiscordEmbed{embed},
+	}
+}
+
+// createSuspendedMessage creates a Discord message for a repository that can no longer be found
+func (d *DiscordNotifier) createSuspendedMessage(owner, repo string) DiscordMessage {
+	embed := DiscordEmbed{
+		Title:       fmt.Sprintf("🚫 %s/%s is no longer accessible", owner, repo),
+		Description: "The repository was deleted or made private. Checks are suspended until it is accessible again.",
+		Color:       0x808080, // Gray color
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Footer: &DiscordEmbedFooter{
+			Text: "GitHub Stars Notify",
+		},
+	}
+
+	return DiscordMessage{
+		Embeds: []DiscordEmbed{embed},
+	}
+}
+
 // sendMessage sends a message to the Discord webhook with context support
 func (d *DiscordNotifier) sendMessage(ctx context.Context, message DiscordMessage) error {
 	jsonData, err := json.Marshal(message)
@@ -0,0 +1,169 @@
+package notify
+
+import (
+	"context"
+
+	"github-stars-notify/internal/github"
+)
+
+// EventKind identifies the type of a notification event
+type EventKind string
+
+// Event kind constants
+const (
+	EventNewStars  EventKind = "new_stars"  // Repository received new stars
+	EventLostStars EventKind = "lost_stars" // Stars were removed from a repository
+	EventNewForks  EventKind = "new_forks"  // Repository was forked
+	EventMilestone EventKind = "milestone"  // Repository reached a star milestone
+	EventReleases  EventKind = "releases"   // Repository published new releases
+	EventFollowers EventKind = "followers"  // User or organization gained followers
+	EventRenamed   EventKind = "renamed"    // Repository was renamed or transferred
+	EventSuspended EventKind = "suspended"  // Repository was deleted or made private, checks are suspended
+)
+
+// Event is a notification about activity on a repository, or on an account for
+// EventFollowers. Kind determines which of the payload fields are set.
+type Event struct {
+	Kind  EventKind
+	Owner string // Repository owner, or the followed account
+	Repo  string // Empty for account events
+
+	Stargazers []github.Stargazer  // EventNewStars, EventLostStars and EventFollowers
+	Forks      []github.Repository // EventNewForks
+	Releases   []github.Release    // EventReleases
+	Milestone  int                 // EventMilestone: the star count that was reached
+	Stars      int                 // EventMilestone: the current star count
+	RenamedTo  string              // EventRenamed: the new "owner/repo"
+}
+
+// NewStarsEvent creates an event for new stargazers of a repository
+func NewStarsEvent(owner, repo string, stargazers []github.Stargazer) Event {
+	return Event{Kind: EventNewStars, Owner: owner, Repo: repo, Stargazers: stargazers}
+}
+
+// LostStarsEvent creates an event for stargazers that removed their star
+func LostStarsEvent(owner, repo string, stargazers []github.Stargazer) Event {
+	return Event{Kind: EventLostStars, Owner: owner, Repo: repo, Stargazers: stargazers}
+}
+
+// NewForksEvent creates an event for new forks of a repository
+func NewForksEvent(owner, repo string, forks []github.Repository) Event {
+	return Event{Kind: EventNewForks, Owner: owner, Repo: repo, Forks: forks}
+}
+
+// ReleasesEvent creates an event for new releases of a repository
+func ReleasesEvent(owner, repo string, releases []github.Release) Event {
+	return Event{Kind: EventReleases, Owner: owner, Repo: repo, Releases: releases}
+}
+
+// FollowersEvent creates an event for new followers of a user or organization
+func FollowersEvent(login string, followers []github.Stargazer) Event {
+	return Event{Kind: EventFollowers, Owner: login, Stargazers: followers}
+}
+
+// MilestoneEvent creates an event for a repository reaching a star milestone
+func MilestoneEvent(owner, repo string, milestone, stars int) Event {
+	return Event{Kind: EventMilestone, Owner: owner, Repo: repo, Milestone: milestone, Stars: stars}
+}
+
+// RenamedEvent creates an event for a repository that was renamed or
+// transferred to renamedTo ("owner/repo")
+func RenamedEvent(owner, repo, renamedTo string) Event {
+	return Event{Kind: EventRenamed, Owner: owner, Repo: repo, RenamedTo: renamedTo}
+}
+
+// SuspendedEvent creates an event for a repository that can no longer be found
+func SuspendedEvent(owner, repo string) Event {
+	return Event{Kind: EventSuspended, Owner: owner, Repo: repo}
+}
+
+// Count returns the number of stargazers, forks or releases the event reports,
+// or the star count for milestones
+func (e Event) Count() int {
+	switch e.Kind {
+	case EventNewForks:
+		return len(e.Forks)
+	case EventReleases:
+		return len(e.Releases)
+	case EventMilestone:
+		return e.Stars
+	default:
+		return len(e.Stargazers)
+	}
+}
+
+// Subject returns the repository of the event as "owner/repo", or the login of
+// the account for account events
+func (e Event) Subject() string {
+	if e.Repo == "" {
+		return e.Owner
+	}
+	return e.Owner + "/" + e.Repo
+}
+
+// IsEmpty reports whether the event has nothing to announce
+func (e Event) IsEmpty() bool {
+	switch e.Kind {
+	case EventMilestone, EventRenamed, EventSuspended:
+		return false
+	default:
+		return e.Count() == 0
+	}
+}
+
+// EventFilter is implemented by notifiers that only handle some event kinds, so
+// wrappers skip the other kinds without retrying or rate limiting them.
+// Notifiers without it handle every kind.
+type EventFilter interface {
+	// Handles reports whether events of the given kind are sent
+	Handles(kind EventKind) bool
+}
+
+// Handles reports whether notifier sends events of the given kind
+func Handles(notifier Notifier, kind EventKind) bool {
+	if filter, ok := notifier.(EventFilter); ok {
+		return filter.Handles(kind)
+	}
+	return true
+}
+
+// StarNotifier is the original notifier interface, which can only report new
+// stars. Wrap implementations with AdaptStarNotifier, or WrapNotifier for retries
+// and rate limiting, to use them as a Notifier.
+type StarNotifier interface {
+	// NotifyNewStars sends a notification about new stars for a repository
+	NotifyNewStars(ctx context.Context, owner, repo string, newStargazers []github.Stargazer) error
+
+	// TestConnection tests the notification provider connection
+	TestConnection(ctx context.Context) error
+
+	// GetProviderName returns the name of the notification provider
+	GetProviderName() string
+}
+
+// starNotifierAdapter turns a StarNotifier into a Notifier
+type starNotifierAdapter struct {
+	StarNotifier
+}
+
+// AdaptStarNotifier returns a Notifier that forwards new stars events to a
+// StarNotifier and ignores every other event kind
+func AdaptStarNotifier(notifier StarNotifier) Notifier {
+	if n, ok := notifier.(Notifier); ok {
+		return n
+	}
+	return &starNotifierAdapter{StarNotifier: notifier}
+}
+
+// Notify forwards new stars events to the wrapped notifier
+func (a *starNotifierAdapter) Notify(ctx context.Context, event Event) error {
+	if !a.Handles(event.Kind) {
+		return nil
+	}
+	return a.NotifyNewStars(ctx, event.Owner, event.Repo, event.Stargazers)
+}
+
+// Handles reports whether events of the given kind are forwarded, only new stars are
+func (a *starNotifierAdapter) Handles(kind EventKind) bool {
+	return kind == EventNewStars
+}
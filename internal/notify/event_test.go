@@ -0,0 +1,164 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github-stars-notify/internal/github"
+	"github-stars-notify/internal/logger"
+)
+
+// recordingNotifier records the events it receives
+type recordingNotifier struct {
+	events []Event
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, event Event) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *recordingNotifier) TestConnection(ctx context.Context) error { return nil }
+
+func (r *recordingNotifier) GetProviderName() string { return "recording" }
+
+// legacyNotifier only implements the original new stars method
+type legacyNotifier struct {
+	stars int
+}
+
+func (l *legacyNotifier) NotifyNewStars(ctx context.Context, owner, repo string, newStargazers []github.Stargazer) error {
+	l.stars += len(newStargazers)
+	return nil
+}
+
+func (l *legacyNotifier) TestConnection(ctx context.Context) error { return nil }
+
+func (l *legacyNotifier) GetProviderName() string { return "legacy" }
+
+func TestWrappedNotifiersForwardEvents(t *testing.T) {
+	recorder := &recordingNotifier{}
+	notifier := NewRetryableNotifier(NewRateLimitedNotifier(recorder, time.Millisecond, logger.Default()), 0, time.Millisecond, logger.Default())
+
+	events := []Event{
+		NewStarsEvent("org", "repo", []github.Stargazer{{Login: "alice", ID: 1}}),
+		LostStarsEvent("org", "repo", []github.Stargazer{{Login: "bob", ID: 2}}),
+		NewForksEvent("org", "repo", []github.Repository{{ID: 3, FullName: "carol/repo"}}),
+		MilestoneEvent("org", "repo", 100, 101),
+	}
+	for _, event := range events {
+		if err := notifier.Notify(context.Background(), event); err != nil {
+			t.Fatalf("Notify(%s) failed: %v", event.Kind, err)
+		}
+	}
+
+	if len(recorder.events) != len(events) {
+		t.Fatalf("Expected %d events, got %d", len(events), len(recorder.events))
+	}
+	for i, event := range recorder.events {
+		if event.Kind != events[i].Kind {
+			t.Errorf("Expected event %s, got %s", events[i].Kind, event.Kind)
+		}
+	}
+}
+
+func TestAdaptStarNotifier(t *testing.T) {
+	legacy := &legacyNotifier{}
+	notifier := AdaptStarNotifier(legacy)
+
+	stargazers := []github.Stargazer{{Login: "alice", ID: 1}, {Login: "bob", ID: 2}}
+	if err := notifier.Notify(context.Background(), NewStarsEvent("org", "repo", stargazers)); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	if err := notifier.Notify(context.Background(), MilestoneEvent("org", "repo", 100, 100)); err != nil {
+		t.Fatalf("Expected unsupported events to be ignored, got %v", err)
+	}
+
+	if legacy.stars != 2 {
+		t.Errorf("Expected 2 stars to be forwarded, got %d", legacy.stars)
+	}
+	if notifier.GetProviderName() != "legacy" {
+		t.Errorf("Expected provider name to be forwarded, got %s", notifier.GetProviderName())
+	}
+
+	// Notifiers that already handle events are returned as is
+	slack := NewSlackNotifier("https://hooks.slack.com/test", "")
+	if AdaptStarNotifier(slack) != Notifier(slack) {
+		t.Error("Expected event-aware notifier to be returned unchanged")
+	}
+}
+
+func TestWrapNotifierSkipsUnhandledEvents(t *testing.T) {
+	legacy := &legacyNotifier{}
+	cfg := NotifierConfig{MaxRetries: 3, RetryBackoff: time.Hour, RateLimitWindow: time.Hour}
+	notifier := WrapNotifier(legacy, cfg, logger.Default())
+
+	if Handles(notifier, EventMilestone) || !Handles(notifier, EventNewStars) {
+		t.Error("Expected the wrapped notifier to only handle new stars")
+	}
+
+	// A milestone the legacy notifier ignores does not take the only slot of
+	// the hour, the stars that follow are sent right away
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := notifier.Notify(ctx, MilestoneEvent("org", "repo", 100, 100)); err != nil {
+		t.Fatalf("Notify(milestone) failed: %v", err)
+	}
+	if err := notifier.Notify(ctx, NewStarsEvent("org", "repo", []github.Stargazer{{Login: "alice", ID: 1}})); err != nil {
+		t.Fatalf("Notify(new stars) failed: %v", err)
+	}
+	if legacy.stars != 1 {
+		t.Errorf("Expected 1 star to be forwarded, got %d", legacy.stars)
+	}
+}
+
+func TestDiscordNotifyMilestone(t *testing.T) {
+	var message DiscordMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("Failed to decode message: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewDiscordNotifier(server.URL)
+	if err := notifier.Notify(context.Background(), MilestoneEvent("org", "repo", 1000, 1002)); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	if len(message.Embeds) != 1 || message.Embeds[0].Title != "🏆 org/repo reached 1k stars!" {
+		t.Errorf("Unexpected milestone message: %+v", message)
+	}
+	if !strings.Contains(message.Content, "Milestone reached") {
+		t.Errorf("Expected a prominent milestone announcement, got %q", message.Content)
+	}
+}
+
+func TestRateLimiterSpacesConcurrentCallers(t *testing.T) {
+	limiter := NewRateLimiter(20 * time.Millisecond)
+	start := time.Now()
+
+	done := make(chan struct{})
+	for i := 0; i < 3; i++ {
+		go func() {
+			if err := limiter.Wait(context.Background()); err != nil {
+				t.Errorf("Wait failed: %v", err)
+			}
+			done <- struct{}{}
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		<-done
+	}
+
+	// The first caller goes immediately, the others take the next two slots
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Expected concurrent callers to be spaced by the interval, took %v", elapsed)
+	}
+}
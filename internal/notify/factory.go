@@ -44,26 +44,14 @@ func CreateNotifiersWithConfig(cfg *config.Config, notifierCfg NotifierConfig, l
 	if cfg.Notifications.Discord.Enabled {
 		baseNotifier := NewDiscordNotifierWithTimeout(cfg.Notifications.Discord.WebhookURL, notifierCfg.Timeout)
 
-		// Wrap with rate limiting
-		rateLimitedNotifier := NewRateLimitedNotifier(baseNotifier, notifierCfg.RateLimitWindow, log)
-
-		// Wrap with retry logic
-		retryableNotifier := NewRetryableNotifier(rateLimitedNotifier, notifierCfg.MaxRetries, notifierCfg.RetryBackoff, log)
-
-		notifiers = append(notifiers, retryableNotifier)
+		notifiers = append(notifiers, WrapNotifier(baseNotifier, notifierCfg, log))
 	}
 
 	// Create Slack notifier if enabled
 	if cfg.Notifications.Slack.Enabled {
 		baseNotifier := NewSlackNotifierWithTimeout(cfg.Notifications.Slack.WebhookURL, cfg.Notifications.Slack.Channel, notifierCfg.Timeout)
 
-		// Wrap with rate limiting
-		rateLimitedNotifier := NewRateLimitedNotifier(baseNotifier, notifierCfg.RateLimitWindow, log)
-
-		// Wrap with retry logic
-		retryableNotifier := NewRetryableNotifier(rateLimitedNotifier, notifierCfg.MaxRetries, notifierCfg.RetryBackoff, log)
-
-		notifiers = append(notifiers, retryableNotifier)
+		notifiers = append(notifiers, WrapNotifier(baseNotifier, notifierCfg, log))
 	}
 
 	return notifiers, nil
@@ -76,7 +64,7 @@ func CreateNotifier(notifierType string, webhookURL string, options ...string) (
 
 // CreateNotifierWithConfig creates a single notifier with custom configuration
 func CreateNotifierWithConfig(notifierType string, webhookURL string, cfg NotifierConfig, log *logger.Logger, options ...string) (Notifier, error) {
-	var baseNotifier Notifier
+	var baseNotifier StarNotifier
 
 	switch notifierType {
 	case ProviderDiscord:
@@ -91,13 +79,18 @@ func CreateNotifierWithConfig(notifierType string, webhookURL string, cfg Notifi
 		return nil, fmt.Errorf("unsupported notifier type: %s", notifierType)
 	}
 
+	return WrapNotifier(baseNotifier, cfg, log), nil
+}
+
+// WrapNotifier adds rate limiting and retries to a notifier. Notifiers only
+// implementing the original StarNotifier interface are adapted first, so they
+// keep receiving new stars and skip the other event kinds.
+func WrapNotifier(notifier StarNotifier, cfg NotifierConfig, log *logger.Logger) Notifier {
 	// Wrap with rate limiting
-	rateLimitedNotifier := NewRateLimitedNotifier(baseNotifier, cfg.RateLimitWindow, log)
+	rateLimitedNotifier := NewRateLimitedNotifier(AdaptStarNotifier(notifier), cfg.RateLimitWindow, log)
 
 	// Wrap with retry logic
-	retryableNotifier := NewRetryableNotifier(rateLimitedNotifier, cfg.MaxRetries, cfg.RetryBackoff, log)
-
-	return retryableNotifier, nil
+	return NewRetryableNotifier(rateLimitedNotifier, cfg.MaxRetries, cfg.RetryBackoff, log)
 }
 
 // CreateBasicNotifier creates a basic notifier without enhancements (for testing)
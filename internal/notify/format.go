@@ -0,0 +1,183 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github-stars-notify/internal/github"
+)
+
+// DefaultWebURL is the web URL used for links when none is set on the context
+const DefaultWebURL = "https://github.com"
+
+// webURLKey is the context key holding the web URL of the repository's GitHub instance
+type webURLKey struct{}
+
+// WithWebURL returns a context whose notifications link to the GitHub instance at
+// webURL, e.g. a GitHub Enterprise Server
+func WithWebURL(ctx context.Context, webURL string) context.Context {
+	return context.WithValue(ctx, webURLKey{}, strings.TrimSuffix(webURL, "/"))
+}
+
+// webURLFromContext returns the web URL set with WithWebURL, or DefaultWebURL
+func webURLFromContext(ctx context.Context) string {
+	if webURL, ok := ctx.Value(webURLKey{}).(string); ok && webURL != "" {
+		return webURL
+	}
+	return DefaultWebURL
+}
+
+// repositoryURL returns the web URL of a repository
+func repositoryURL(webURL, owner, repo string) string {
+	return fmt.Sprintf("%s/%s/%s", webURL, owner, repo)
+}
+
+// accountURL returns the web URL of a user or organization
+func accountURL(webURL, login string) string {
+	return fmt.Sprintf("%s/%s", webURL, login)
+}
+
+// profileURL returns the web URL of a stargazer's profile, preferring the URL reported by the API
+func profileURL(webURL string, sg github.Stargazer) string {
+	if sg.HTMLURL != "" {
+		return sg.HTMLURL
+	}
+	return fmt.Sprintf("%s/%s", webURL, sg.Login)
+}
+
+// describeStargazer returns the login of a stargazer followed by its company and
+// follower count when the profile was enriched, e.g. "alice (Acme Corp, 2.3k followers)"
+func describeStargazer(sg github.Stargazer) string {
+	if sg.Profile == nil {
+		return sg.Login
+	}
+
+	var details []string
+	if company := strings.TrimSpace(sg.Profile.Company); company != "" {
+		details = append(details, company)
+	}
+	if sg.Profile.Followers > 0 {
+		details = append(details, fmt.Sprintf("%s %s", formatCount(sg.Profile.Followers),
+			pluralize(sg.Profile.Followers, "follower", "followers")))
+	}
+
+	if len(details) == 0 {
+		return sg.Login
+	}
+	return fmt.Sprintf("%s (%s)", sg.Login, strings.Join(details, ", "))
+}
+
+// formatCount abbreviates large counts, e.g. 2300 becomes "2.3k"
+func formatCount(count int) string {
+	switch {
+	case count >= 1_000_000:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(count)/1_000_000), ".0") + "M"
+	case count >= 1_000:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(count)/1_000), ".0") + "k"
+	default:
+		return fmt.Sprintf("%d", count)
+	}
+}
+
+// formatMilestone renders a star milestone, e.g. "1k stars" or "500 stars"
+func formatMilestone(milestone int) string {
+	return fmt.Sprintf("%s %s", formatCount(milestone), pluralize(milestone, "star", "stars"))
+}
+
+// releaseTitle returns the tag of a release followed by its name when they differ
+func releaseTitle(release github.Release) string {
+	name := strings.TrimSpace(release.Name)
+	if name == "" || name == release.TagName {
+		return release.TagName
+	}
+	return fmt.Sprintf("%s (%s)", release.TagName, name)
+}
+
+// releaseURL returns the web URL of a release, preferring the URL reported by the API
+func releaseURL(webURL, owner, repo string, release github.Release) string {
+	if release.HTMLURL != "" {
+		return release.HTMLURL
+	}
+	return fmt.Sprintf("%s/releases/tag/%s", repositoryURL(webURL, owner, repo), release.TagName)
+}
+
+// releaseLabel returns a marker for prereleases and drafts, or "" for regular releases
+func releaseLabel(release github.Release) string {
+	switch {
+	case release.Draft:
+		return "draft"
+	case release.Prerelease:
+		return "prerelease"
+	default:
+		return ""
+	}
+}
+
+// describeAssets lists the assets of a release with their download counts, one
+// per line, listing at most limit of them
+func describeAssets(release github.Release, limit int) string {
+	lines := make([]string, 0, limit+1)
+	for i, asset := range release.Assets {
+		if i >= limit {
+			lines = append(lines, fmt.Sprintf("and %d more", len(release.Assets)-limit))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s %s", asset.Name, formatCount(asset.DownloadCount),
+			pluralize(asset.DownloadCount, "download", "downloads")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// truncate shortens text to at most limit characters, ending with an ellipsis when cut
+func truncate(text string, limit int) string {
+	text = strings.TrimSpace(text)
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:limit-1])) + "…"
+}
+
+// pluralize returns singular when count is 1, plural otherwise
+func pluralize(count int, singular, plural string) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
+
+// joinLogins joins stargazer logins with commas, listing at most limit of them
+func joinLogins(stargazers []github.Stargazer, limit int) string {
+	logins := make([]string, 0, limit)
+	for i, sg := range stargazers {
+		if i >= limit {
+			break
+		}
+		logins = append(logins, sg.Login)
+	}
+
+	joined := strings.Join(logins, ", ")
+	if len(stargazers) > limit {
+		joined += fmt.Sprintf(" and %d more", len(stargazers)-limit)
+	}
+	return joined
+}
+
+// forkURL returns the web URL of a fork, preferring the URL reported by the API
+func forkURL(webURL string, fork github.Repository) string {
+	if fork.HTMLURL != "" {
+		return fork.HTMLURL
+	}
+	return fmt.Sprintf("%s/%s", webURL, fork.FullName)
+}
+
+// describeRename describes the move of owner/repo to renamedTo, calling it a
+// transfer when the owner changed
+func describeRename(owner, repo, renamedTo string) string {
+	verb := "renamed"
+	if newOwner, _, _ := strings.Cut(renamedTo, "/"); !strings.EqualFold(newOwner, owner) {
+		verb = "transferred"
+	}
+	return fmt.Sprintf("%s/%s was %s to %s", owner, repo, verb, renamedTo)
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"testing"
+
+	"github-stars-notify/internal/github"
+)
+
+func TestDescribeStargazer(t *testing.T) {
+	tests := []struct {
+		name     string
+		sg       github.Stargazer
+		expected string
+	}{
+		{"not enriched", github.Stargazer{Login: "alice"}, "alice"},
+		{"company and followers", github.Stargazer{Login: "alice", Profile: &github.UserProfile{Company: "Acme Corp", Followers: 2300}}, "alice (Acme Corp, 2.3k followers)"},
+		{"single follower", github.Stargazer{Login: "bob", Profile: &github.UserProfile{Followers: 1}}, "bob (1 follower)"},
+		{"empty profile", github.Stargazer{Login: "carol", Profile: &github.UserProfile{}}, "carol"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeStargazer(tt.sg); got != tt.expected {
+				t.Errorf("describeStargazer() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	tests := map[int]string{
+		999:       "999",
+		1000:      "1k",
+		2345:      "2.3k",
+		1_500_000: "1.5M",
+	}
+
+	for count, expected := range tests {
+		if got := formatCount(count); got != expected {
+			t.Errorf("formatCount(%d) = %q, want %q", count, got, expected)
+		}
+	}
+}
+
+func TestFormatMilestone(t *testing.T) {
+	if got := formatMilestone(1000); got != "1k stars" {
+		t.Errorf("Expected 1k stars, got %s", got)
+	}
+	if got := formatMilestone(500); got != "500 stars" {
+		t.Errorf("Expected 500 stars, got %s", got)
+	}
+}
+
+func TestDescribeRename(t *testing.T) {
+	if got := describeRename("org", "old", "org/new"); got != "org/old was renamed to org/new" {
+		t.Errorf("Unexpected rename description: %s", got)
+	}
+	if got := describeRename("org", "repo", "other-org/repo"); got != "org/repo was transferred to other-org/repo" {
+		t.Errorf("Unexpected transfer description: %s", got)
+	}
+}
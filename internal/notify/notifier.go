@@ -2,16 +2,17 @@ package notify
 
 import (
 	"context"
+	"sync"
 	"time"
 
-	"github-stars-notify/internal/github"
 	"github-stars-notify/internal/logger"
 )
 
 // Notifier defines the interface for notification providers
 type Notifier interface {
-	// NotifyNewStars sends a notification about new stars for a repository
-	NotifyNewStars(ctx context.Context, owner, repo string, newStargazers []github.Stargazer) error
+	// Notify sends a notification about an event. Providers ignore event
+	// kinds they cannot render.
+	Notify(ctx context.Context, event Event) error
 
 	// TestConnection tests the notification provider connection
 	TestConnection(ctx context.Context) error
@@ -38,20 +39,27 @@ func NewRetryableNotifier(notifier Notifier, maxRetries int, backoff time.Durati
 	}
 }
 
-// NotifyNewStars sends a notification with retry logic
-func (rn *RetryableNotifier) NotifyNewStars(ctx context.Context, owner, repo string, newStargazers []github.Stargazer) error {
+// Notify sends a notification with retry logic. Events the wrapped notifier
+// does not handle are dropped without an attempt.
+func (rn *RetryableNotifier) Notify(ctx context.Context, event Event) error {
+	if !rn.Handles(event.Kind) {
+		return nil
+	}
+
 	var lastErr error
 	provider := rn.notifier.GetProviderName()
+	repo := event.Subject()
 
 	for i := 0; i <= rn.maxRetries; i++ {
 		start := time.Now()
 
-		err := rn.notifier.NotifyNewStars(ctx, owner, repo, newStargazers)
+		err := rn.notifier.Notify(ctx, event)
 		if err == nil {
 			rn.logger.Info("notification sent successfully",
 				"provider", provider,
-				"repo", owner+"/"+repo,
-				"stargazers", len(newStargazers),
+				"repo", repo,
+				"event", event.Kind,
+				"count", event.Count(),
 				"attempt", i+1,
 				"duration", time.Since(start))
 			return nil
@@ -61,7 +69,8 @@ func (rn *RetryableNotifier) NotifyNewStars(ctx context.Context, owner, repo str
 
 		rn.logger.Warn("notification failed",
 			"provider", provider,
-			"repo", owner+"/"+repo,
+			"repo", repo,
+			"event", event.Kind,
 			"attempt", i+1,
 			"error", err,
 			"duration", time.Since(start))
@@ -90,7 +99,8 @@ func (rn *RetryableNotifier) NotifyNewStars(ctx context.Context, owner, repo str
 
 	rn.logger.Error("notification failed after all retries",
 		"provider", provider,
-		"repo", owner+"/"+repo,
+		"repo", repo,
+		"event", event.Kind,
 		"max_retries", rn.maxRetries,
 		"error", lastErr)
 
@@ -148,6 +158,11 @@ func (rn *RetryableNotifier) GetProviderName() string {
 	return rn.notifier.GetProviderName()
 }
 
+// Handles reports whether the wrapped notifier handles events of the given kind
+func (rn *RetryableNotifier) Handles(kind EventKind) bool {
+	return Handles(rn.notifier, kind)
+}
+
 // NotificationConfig represents configuration for a notification provider
 type NotificationConfig struct {
 	Enabled bool   `yaml:"enabled"`
@@ -167,10 +182,12 @@ type SlackConfig struct {
 	Channel            string `yaml:"channel,omitempty"`
 }
 
-// RateLimiter provides rate limiting for notifications
+// RateLimiter provides rate limiting for notifications. It is safe for
+// concurrent use, each caller reserves its own slot.
 type RateLimiter struct {
 	lastNotification time.Time
 	interval         time.Duration
+	mutex            sync.Mutex
 }
 
 // NewRateLimiter creates a new rate limiter
@@ -182,6 +199,9 @@ func NewRateLimiter(interval time.Duration) *RateLimiter {
 
 // Allow checks if a notification is allowed (not rate limited)
 func (rl *RateLimiter) Allow() bool {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
 	now := time.Now()
 	if now.Sub(rl.lastNotification) >= rl.interval {
 		rl.lastNotification = now
@@ -190,23 +210,25 @@ func (rl *RateLimiter) Allow() bool {
 	return false
 }
 
-// Wait waits until the next notification is allowed
+// Wait waits until the next notification is allowed. The slot is reserved
+// before waiting, so concurrent callers are spaced by the interval.
 func (rl *RateLimiter) Wait(ctx context.Context) error {
+	rl.mutex.Lock()
 	now := time.Now()
-	nextAllowed := rl.lastNotification.Add(rl.interval)
+	slot := rl.lastNotification.Add(rl.interval)
+	if slot.Before(now) {
+		slot = now
+	}
+	rl.lastNotification = slot
+	rl.mutex.Unlock()
 
-	if now.Before(nextAllowed) {
-		waitTime := nextAllowed.Sub(now)
+	if waitTime := slot.Sub(now); waitTime > 0 {
 		select {
 		case <-time.After(waitTime):
-			rl.lastNotification = time.Now()
-			return nil
 		case <-ctx.Done():
 			return ctx.Err()
 		}
 	}
-
-	rl.lastNotification = now
 	return nil
 }
 
@@ -226,21 +248,24 @@ func NewRateLimitedNotifier(notifier Notifier, interval time.Duration, logger *l
 	}
 }
 
-// NotifyNewStars sends a notification with rate limiting
-func (rln *RateLimitedNotifier) NotifyNewStars(ctx context.Context, owner, repo string, newStargazers []github.Stargazer) error {
-	provider := rln.notifier.GetProviderName()
+// Notify sends a notification with rate limiting. Events the wrapped notifier
+// does not handle are dropped without reserving a slot.
+func (rln *RateLimitedNotifier) Notify(ctx context.Context, event Event) error {
+	if !rln.Handles(event.Kind) {
+		return nil
+	}
 
 	if !rln.rateLimiter.Allow() {
 		rln.logger.Debug("rate limit hit, waiting",
-			"provider", provider,
-			"repo", owner+"/"+repo)
+			"provider", rln.notifier.GetProviderName(),
+			"repo", event.Subject())
 
 		if err := rln.rateLimiter.Wait(ctx); err != nil {
 			return err
 		}
 	}
 
-	return rln.notifier.NotifyNewStars(ctx, owner, repo, newStargazers)
+	return rln.notifier.Notify(ctx, event)
 }
 
 // TestConnection tests the connection (not rate limited)
@@ -252,3 +277,8 @@ func (rln *RateLimitedNotifier) TestConnection(ctx context.Context) error {
 func (rln *RateLimitedNotifier) GetProviderName() string {
 	return rln.notifier.GetProviderName()
 }
+
+// Handles reports whether the wrapped notifier handles events of the given kind
+func (rln *RateLimitedNotifier) Handles(kind EventKind) bool {
+	return Handles(rln.notifier, kind)
+}
@@ -73,19 +73,48 @@ func (s *SlackNotifier) GetProviderName() string {
 	return ProviderSlack
 }
 
-// NotifyNewStars sends a notification about new stars with context support
-func (s *SlackNotifier) NotifyNewStars(ctx context.Context, owner, repo string, newStargazers []github.Stargazer) error {
-	if len(newStargazers) == 0 {
+// Notify renders an event as a Slack message and sends it with context support
+func (s *SlackNotifier) Notify(ctx context.Context, event Event) error {
+	if event.IsEmpty() {
+		return nil
+	}
+
+	webURL := webURLFromContext(ctx)
+	owner, repo := event.Owner, event.Repo
+
+	var message SlackMessage
+	switch event.Kind {
+	case EventNewStars:
+		message = s.createMessage(webURL, owner, repo, event.Stargazers)
+	case EventLostStars:
+		message = s.createLostStarsMessage(webURL, owner, repo, event.Stargazers)
+	case EventNewForks:
+		message = s.createForksMessage(webURL, owner, repo, event.Forks)
+	case EventReleases:
+		message = s.createReleasesMessage(webURL, owner, repo, event.Releases)
+	case EventFollowers:
+		message = s.createFollowersMessage(webURL, owner, event.Stargazers)
+	case EventMilestone:
+		message = s.createMilestoneMessage(webURL, owner, repo, event.Milestone, event.Stars)
+	case EventRenamed:
+		message = s.createRenamedMessage(webURL, owner, repo, event.RenamedTo)
+	case EventSuspended:
+		message = s.createSuspendedMessage(owner, repo)
+	default:
 		return nil
 	}
 
-	message := s.createMessage(owner, repo, newStargazers)
 	return s.sendMessage(ctx, message)
 }
 
+// NotifyNewStars sends a notification about new stars with context support
+func (s *SlackNotifier) NotifyNewStars(ctx context.Context, owner, repo string, newStargazers []github.Stargazer) error {
+	return s.Notify(ctx, NewStarsEvent(owner, repo, newStargazers))
+}
+
 // createMessage creates a Slack message for new stars
-func (s *SlackNotifier) createMessage(owner, repo string, newStargazers []github.Stargazer) SlackMessage {
-	repoURL := fmt.Sprintf("https://github.com/%s/%s", owner, repo)
+func (s *SlackNotifier) createMessage(webURL, owner, repo string, newStargazers []github.Stargazer) SlackMessage {
+	repoURL := repositoryURL(webURL, owner, repo)
 
 	var title, text string
 	if len(newStargazers) == 1 {
@@ -118,9 +147,9 @@ func (s *SlackNotifier) createMessage(owner, repo string, newStargazers []github
 			break
 		}
 
-		stargazerURL := fmt.Sprintf("https://github.com/%s", sg.Login)
+		stargazerURL := profileURL(webURL, sg)
 		attachment.Fields = append(attachment.Fields, SlackField{
-			Title: sg.Login,
+			Title: describeStargazer(sg),
 			Value: fmt.Sprintf("<%s|View Profile>", stargazerURL),
 			Short: true,
 		})
@@ -139,6 +168,259 @@ func (s *SlackNotifier) createMessage(owner, repo string, newStargazers []github
 	return message
 }
 
+// createLostStarsMessage creates a Slack message for removed stars
+func (s *SlackNotifier) createLostStarsMessage(webURL, owner, repo string, lostStargazers []github.Stargazer) SlackMessage {
+	repoURL := repositoryURL(webURL, owner, repo)
+
+	title := fmt.Sprintf("💔 -%d %s on %s/%s",
+		len(lostStargazers), pluralize(len(lostStargazers), "star", "stars"), owner, repo)
+
+	attachment := SlackAttachment{
+		Color:     "danger",
+		Title:     title,
+		TitleLink: repoURL,
+		Text:      joinLogins(lostStargazers, 10),
+		Footer:    "GitHub Stars Notify",
+		Timestamp: time.Now().Unix(),
+	}
+
+	message := SlackMessage{
+		Username:    "GitHub Stars Notify",
+		IconEmoji:   ":star:",
+		Attachments: []SlackAttachment{attachment},
+	}
+
+	if s.channel != "" {
+		message.Channel = s.channel
+	}
+
+	return message
+}
+
+// createForksMessage creates a Slack message for new forks
+func (s *SlackNotifier) createForksMessage(webURL, owner, repo string, forks []github.Repository) SlackMessage {
+	repoURL := repositoryURL(webURL, owner, repo)
+
+	title := fmt.Sprintf("🍴 %d new %s of %s/%s", len(forks), pluralize(len(forks), "fork", "forks"), owner, repo)
+	text := fmt.Sprintf("Repository <%s|%s/%s> was forked %d %s!",
+		repoURL, owner, repo, len(forks), pluralize(len(forks), "time", "times"))
+
+	attachment := SlackAttachment{
+		Color:     "#2f81f7",
+		Title:     title,
+		TitleLink: repoURL,
+		Text:      text,
+		Footer:    "GitHub Stars Notify",
+		Timestamp: time.Now().Unix(),
+	}
+
+	// Add fields for forks (limit to 10)
+	maxForks := 10
+	for i, fork := range forks {
+		if i >= maxForks {
+			remaining := len(forks) - maxForks
+			attachment.Fields = append(attachment.Fields, SlackField{
+				Title: "And more...",
+				Value: fmt.Sprintf("%d more forks", remaining),
+				Short: false,
+			})
+			break
+		}
+
+		attachment.Fields = append(attachment.Fields, SlackField{
+			Title: fork.FullName,
+			Value: fmt.Sprintf("<%s|View Fork>", forkURL(webURL, fork)),
+			Short: true,
+		})
+	}
+
+	message := SlackMessage{
+		Username:    "GitHub Stars Notify",
+		IconEmoji:   ":star:",
+		Attachments: []SlackAttachment{attachment},
+	}
+
+	if s.channel != "" {
+		message.Channel = s.channel
+	}
+
+	return message
+}
+
+// createReleasesMessage creates a Slack message with one attachment per new release
+func (s *SlackNotifier) createReleasesMessage(webURL, owner, repo string, releases []github.Release) SlackMessage {
+	var attachments []SlackAttachment
+
+	// Add attachments for releases (limit to 10)
+	maxReleases := 10
+	for i, release := range releases {
+		if i >= maxReleases {
+			break
+		}
+
+		attachment := SlackAttachment{
+			Color:     "#8957e5",
+			Title:     fmt.Sprintf("📦 %s/%s %s", owner, repo, releaseTitle(release)),
+			TitleLink: releaseURL(webURL, owner, repo, release),
+			Text:      truncate(release.Body, 500),
+			Footer:    "GitHub Stars Notify",
+			Timestamp: time.Now().Unix(),
+		}
+
+		if label := releaseLabel(release); label != "" {
+			attachment.Fields = append(attachment.Fields, SlackField{
+				Title: "Type",
+				Value: label,
+				Short: true,
+			})
+		}
+		if len(release.Assets) > 0 {
+			attachment.Fields = append(attachment.Fields, SlackField{
+				Title: fmt.Sprintf("Assets (%s %s)", formatCount(release.DownloadCount()), pluralize(release.DownloadCount(), "download", "downloads")),
+				Value: describeAssets(release, 5),
+				Short: false,
+			})
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	var text string
+	if len(releases) > maxReleases {
+		text = fmt.Sprintf("%d more releases of %s/%s", len(releases)-maxReleases, owner, repo)
+	}
+
+	message := SlackMessage{
+		Text:        text,
+		Username:    "GitHub Stars Notify",
+		IconEmoji:   ":star:",
+		Attachments: attachments,
+	}
+
+	if s.channel != "" {
+		message.Channel = s.channel
+	}
+
+	return message
+}
+
+// createFollowersMessage creates a Slack message for new followers of an account
+func (s *SlackNotifier) createFollowersMessage(webURL, login string, followers []github.Stargazer) SlackMessage {
+	accountLink := accountURL(webURL, login)
+	followerWord := pluralize(len(followers), "follower", "followers")
+
+	attachment := SlackAttachment{
+		Color:     "#1f883d",
+		Title:     fmt.Sprintf("👥 %d new %s for %s", len(followers), followerWord, login),
+		TitleLink: accountLink,
+		Text:      fmt.Sprintf("<%s|%s> gained %d new %s!", accountLink, login, len(followers), followerWord),
+		Footer:    "GitHub Stars Notify",
+		Timestamp: time.Now().Unix(),
+	}
+
+	// Add fields for followers (limit to 10)
+	maxFollowers := 10
+	for i, follower := range followers {
+		if i >= maxFollowers {
+			attachment.Fields = append(attachment.Fields, SlackField{
+				Title: "And more...",
+				Value: fmt.Sprintf("%d more followers", len(followers)-maxFollowers),
+				Short: false,
+			})
+			break
+		}
+
+		attachment.Fields = append(attachment.Fields, SlackField{
+			Title: describeStargazer(follower),
+			Value: fmt.Sprintf("<%s|View Profile>", profileURL(webURL, follower)),
+			Short: true,
+		})
+	}
+
+	message := SlackMessage{
+		Username:    "GitHub Stars Notify",
+		IconEmoji:   ":star:",
+		Attachments: []SlackAttachment{attachment},
+	}
+
+	if s.channel != "" {
+		message.Channel = s.channel
+	}
+
+	return message
+}
+
+// createMilestoneMessage creates a Slack message celebrating a star milestone
+func (s *SlackNotifier) createMilestoneMessage(webURL, owner, repo string, milestone, stars int) SlackMessage {
+	repoURL := repositoryURL(webURL, owner, repo)
+
+	attachment := SlackAttachment{
+		Color:     "#ffd700",
+		Title:     fmt.Sprintf("🏆 %s/%s reached %s!", owner, repo, formatMilestone(milestone)),
+		TitleLink: repoURL,
+		Text:      fmt.Sprintf("Repository <%s|%s/%s> now has %d stars. Thank you to every stargazer!", repoURL, owner, repo, stars),
+		Footer:    "GitHub Stars Notify",
+		Timestamp: time.Now().Unix(),
+	}
+
+	message := SlackMessage{
+		Text:        fmt.Sprintf(":tada: *Milestone reached: %s/%s hit %s!*", owner, repo, formatMilestone(milestone)),
+		Username:    "GitHub Stars Notify",
+		IconEmoji:   ":trophy:",
+		Attachments: []SlackAttachment{attachment},
+	}
+
+	if s.channel != "" {
+		message.Channel = s.channel
+	}
+
+	return message
+}
+
+// createRenamedMessage creates a Slack message for a renamed or transferred repository
+func (s *SlackNotifier) createRenamedMessage(webURL, owner, repo, renamedTo string) SlackMessage {
+	newURL := webURL + "/" + renamedTo
+
+	attachment := SlackAttachment{
+		Color:     "#0099ff",
+		Title:     "🔀 " + describeRename(owner, repo, renamedTo),
+		TitleLink: newURL,
+		Text:      fmt.Sprintf("Stars are now tracked for <%s|%s>. Update the configuration to use the new name.", newURL, renamedTo),
+		Footer:    "GitHub Stars Notify",
+		Timestamp: time.Now().Unix(),
+	}
+
+	return s.newMessage(":twisted_rightwards_arrows:", attachment)
+}
+
+// createSuspendedMessage creates a Slack message for a repository that can no longer be found
+func (s *SlackNotifier) createSuspendedMessage(owner, repo string) SlackMessage {
+	attachment := SlackAttachment{
+		Color:     "#808080",
+		Title:     fmt.Sprintf("🚫 %s/%s is no longer accessible", owner, repo),
+		Text:      "The repository was deleted or made private. Checks are suspended until it is accessible again.",
+		Footer:    "GitHub Stars Notify",
+		Timestamp: time.Now().Unix(),
+	}
+
+	return s.newMessage(":no_entry_sign:", attachment)
+}
+
+// newMessage creates a Slack message with a single attachment
+func (s *SlackNotifier) newMessage(iconEmoji string, attachment SlackAttachment) SlackMessage {
+	message := SlackMessage{
+		Username:    "GitHub Stars Notify",
+		IconEmoji:   iconEmoji,
+		Attachments: []SlackAttachment{attachment},
+	}
+
+	if s.channel != "" {
+		message.Channel = s.channel
+	}
+
+	return message
+}
+
 // sendMessage sends a message to the Slack webhook with context support
 func (s *SlackNotifier) sendMessage(ctx context.Context, message SlackMessage) error {
 	jsonData, err := json.Marshal(message)
@@ -2,6 +2,7 @@ package notify
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,7 +28,7 @@ func TestSlackNotifier(t *testing.T) {
 	stargazers := []github.Stargazer{
 		{Login: "testuser", ID: 123},
 	}
-	message := notifier.createMessage("facebook", "react", stargazers)
+	message := notifier.createMessage(DefaultWebURL, "facebook", "react", stargazers)
 
 	if message.Username != "GitHub Stars Notify" {
 		t.Errorf("Expected username 'GitHub Stars Notify', got %s", message.Username)
@@ -60,3 +61,123 @@ func TestSlackNotifier(t *testing.T) {
 		t.Errorf("NotifyNewStars with empty stargazers failed: %v", err)
 	}
 }
+
+func TestSlackLostStarsMessage(t *testing.T) {
+	notifier := NewSlackNotifier("https://hooks.slack.com/test", "")
+
+	lost := []github.Stargazer{
+		{Login: "alice", ID: 1},
+		{Login: "bob", ID: 2},
+	}
+	message := notifier.createLostStarsMessage(DefaultWebURL, "org", "repo", lost)
+
+	if len(message.Attachments) != 1 {
+		t.Fatalf("Expected 1 attachment, got %d", len(message.Attachments))
+	}
+	if message.Attachments[0].Title != "💔 -2 stars on org/repo" {
+		t.Errorf("Unexpected title: %s", message.Attachments[0].Title)
+	}
+	if message.Attachments[0].Text != "alice, bob" {
+		t.Errorf("Unexpected text: %s", message.Attachments[0].Text)
+	}
+}
+
+func TestSlackForksMessage(t *testing.T) {
+	notifier := NewSlackNotifier("https://hooks.slack.com/test", "")
+
+	forks := []github.Repository{
+		{ID: 1, FullName: "alice/repo", HTMLURL: "https://github.com/alice/repo"},
+		{ID: 2, FullName: "bob/repo"},
+	}
+	message := notifier.createForksMessage(DefaultWebURL, "org", "repo", forks)
+
+	attachment := message.Attachments[0]
+	if attachment.Title != "🍴 2 new forks of org/repo" {
+		t.Errorf("Unexpected title: %s", attachment.Title)
+	}
+	if len(attachment.Fields) != 2 {
+		t.Fatalf("Expected 2 fields, got %d", len(attachment.Fields))
+	}
+	if attachment.Fields[1].Value != "<https://github.com/bob/repo|View Fork>" {
+		t.Errorf("Expected fork link, got %s", attachment.Fields[1].Value)
+	}
+}
+
+func TestSlackReleasesMessage(t *testing.T) {
+	notifier := NewSlackNotifier("https://hooks.slack.com/test", "")
+
+	releases := []github.Release{{
+		ID:         1,
+		TagName:    "v2.0.0",
+		Name:       "Big Bang",
+		Body:       strings.Repeat("a", 600),
+		Prerelease: true,
+		Assets: []github.ReleaseAsset{
+			{Name: "app.tar.gz", DownloadCount: 1500},
+			{Name: "app.zip", DownloadCount: 1},
+		},
+	}}
+	message := notifier.createReleasesMessage(DefaultWebURL, "org", "repo", releases)
+
+	if len(message.Attachments) != 1 {
+		t.Fatalf("Expected 1 attachment, got %d", len(message.Attachments))
+	}
+	attachment := message.Attachments[0]
+	if attachment.Title != "📦 org/repo v2.0.0 (Big Bang)" {
+		t.Errorf("Unexpected title: %s", attachment.Title)
+	}
+	if attachment.TitleLink != "https://github.com/org/repo/releases/tag/v2.0.0" {
+		t.Errorf("Unexpected release link: %s", attachment.TitleLink)
+	}
+	if len([]rune(attachment.Text)) != 500 || !strings.HasSuffix(attachment.Text, "…") {
+		t.Errorf("Expected body to be truncated to 500 characters, got %d", len([]rune(attachment.Text)))
+	}
+	if len(attachment.Fields) != 2 || attachment.Fields[1].Value != "app.tar.gz: 1.5k downloads\napp.zip: 1 download" {
+		t.Errorf("Unexpected fields: %+v", attachment.Fields)
+	}
+}
+
+func TestSlackFollowersMessage(t *testing.T) {
+	notifier := NewSlackNotifier("https://hooks.slack.com/test", "")
+
+	followers := []github.Stargazer{{Login: "alice", ID: 1}}
+	if err := notifier.Notify(context.Background(), FollowersEvent("my-org", nil)); err != nil {
+		t.Errorf("Expected empty follower event to be skipped, got %v", err)
+	}
+
+	message := notifier.createFollowersMessage(DefaultWebURL, "my-org", followers)
+	attachment := message.Attachments[0]
+	if attachment.Title != "👥 1 new follower for my-org" || attachment.TitleLink != "https://github.com/my-org" {
+		t.Errorf("Unexpected title: %s (%s)", attachment.Title, attachment.TitleLink)
+	}
+	if len(attachment.Fields) != 1 || attachment.Fields[0].Value != "<https://github.com/alice|View Profile>" {
+		t.Errorf("Unexpected fields: %+v", attachment.Fields)
+	}
+}
+
+func TestSlackEnterpriseLinks(t *testing.T) {
+	notifier := NewSlackNotifier("https://hooks.slack.com/test", "")
+
+	stargazers := []github.Stargazer{
+		{Login: "alice", ID: 1},
+		{Login: "bob", ID: 2, HTMLURL: "https://ghe.example.com/bob"},
+	}
+
+	ctx := WithWebURL(context.Background(), "https://ghe.example.com/")
+	message := notifier.createMessage(webURLFromContext(ctx), "org", "repo", stargazers)
+
+	attachment := message.Attachments[0]
+	if attachment.TitleLink != "https://ghe.example.com/org/repo" {
+		t.Errorf("Expected enterprise repository link, got %s", attachment.TitleLink)
+	}
+	if attachment.Fields[0].Value != "<https://ghe.example.com/alice|View Profile>" {
+		t.Errorf("Expected profile link derived from the web URL, got %s", attachment.Fields[0].Value)
+	}
+	if attachment.Fields[1].Value != "<https://ghe.example.com/bob|View Profile>" {
+		t.Errorf("Expected profile link reported by the API, got %s", attachment.Fields[1].Value)
+	}
+
+	if webURLFromContext(context.Background()) != DefaultWebURL {
+		t.Errorf("Expected %s without a web URL on the context", DefaultWebURL)
+	}
+}
@@ -0,0 +1,85 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	invalid := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * foo *",
+		"@every",
+	}
+
+	for _, expr := range invalid {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, expected an error", expr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// Friday
+	from := time.Date(2024, time.March, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.March, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.March, 15, 10, 15, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2024, time.March, 15, 10, 25, 0, 0, time.UTC)},
+		{"0 9-17 * * *", time.Date(2024, time.March, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2024, time.March, 16, 2, 30, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.March, 15, 11, 0, 0, 0, time.UTC)},
+		{"0 0 * * mon", time.Date(2024, time.March, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, time.March, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: the 1st of the month or any Sunday
+		{"0 0 1 * sun", time.Date(2024, time.March, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 feb *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		cron, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCron(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if got := cron.Next(from); !got.Equal(tt.expected) {
+			t.Errorf("%q: Next() = %v, expected %v", tt.expr, got, tt.expected)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	schedule, err := Parse(EverySpec(5 * time.Minute))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if schedule != Every(5*time.Minute) {
+		t.Errorf("expected a 5 minute interval, got %v", schedule)
+	}
+
+	if _, err := Parse("@every 30s"); err == nil {
+		t.Error("expected an error for an interval below one minute")
+	}
+	if _, err := Parse("@every soon"); err == nil {
+		t.Error("expected an error for an invalid interval")
+	}
+	if _, err := Parse("0 */6 * * *"); err != nil {
+		t.Errorf("Parse of a cron expression failed: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+// Package schedule decides when each repository is checked, from fixed
+// intervals or cron expressions.
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Schedule returns the run following a given time
+type Schedule interface {
+	Next(after time.Time) time.Time
+}
+
+// Every runs at a fixed interval
+type Every time.Duration
+
+// Next returns after plus the interval
+func (e Every) Next(after time.Time) time.Time {
+	return after.Add(time.Duration(e))
+}
+
+// everyPrefix introduces an interval spec, such as "@every 5m"
+const everyPrefix = "@every "
+
+// EverySpec returns the spec of a fixed interval, as accepted by Parse
+func EverySpec(interval time.Duration) string {
+	return everyPrefix + interval.String()
+}
+
+// Parse parses a schedule spec: "@every <duration>" for a fixed interval,
+// otherwise a cron expression as accepted by ParseCron
+func Parse(spec string) (Schedule, error) {
+	if value, ok := strings.CutPrefix(strings.TrimSpace(spec), everyPrefix); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval %q: %w", value, err)
+		}
+		if interval < time.Minute {
+			return nil, fmt.Errorf("invalid interval %q: must be at least one minute", value)
+		}
+		return Every(interval), nil
+	}
+
+	return ParseCron(spec)
+}
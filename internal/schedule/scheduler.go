@@ -0,0 +1,158 @@
+package schedule
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MaxJitter bounds the random delay added to each run
+const MaxJitter = 5 * time.Minute
+
+// jitterDivisor sets the jitter window to a tenth of the time between two runs
+const jitterDivisor = 10
+
+// Job is a recurring check identified by its key
+type Job struct {
+	Key  string
+	Spec string // Schedule spec, see Parse
+}
+
+// entry is the schedule of a job and its next run
+type entry struct {
+	spec     string
+	schedule Schedule
+	base     time.Time // Next run before jitter, following runs are computed from it
+	next     time.Time // Next run, the zero time if the schedule never runs again
+}
+
+// Scheduler tracks when each job runs next. Runs are delayed by a random
+// jitter of up to a tenth of the time between two runs, at most MaxJitter, so
+// jobs sharing a schedule do not all run at once.
+type Scheduler struct {
+	entries map[string]*entry
+	random  func(n int64) int64 // Returns a random number in [0, n)
+	mutex   sync.Mutex
+}
+
+// NewScheduler creates a scheduler without jobs
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		entries: make(map[string]*entry),
+		random:  rand.Int63n,
+	}
+}
+
+// Update replaces the jobs of the scheduler. Jobs whose spec did not change
+// keep their next run. New and changed interval jobs first run at now plus
+// jitter rather than a full interval later, new cron jobs at their next
+// matching time. Jobs with an invalid spec are left out and reported in the
+// returned error.
+func (s *Scheduler) Update(now time.Time, jobs []Job) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var invalid []string
+	entries := make(map[string]*entry, len(jobs))
+	for _, job := range jobs {
+		if current, ok := s.entries[job.Key]; ok && current.spec == job.Spec {
+			entries[job.Key] = current
+			continue
+		}
+
+		schedule, err := Parse(job.Spec)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %v", job.Key, err))
+			continue
+		}
+
+		// The first run of an interval job is due now, its jitter is still
+		// drawn from the window of a whole interval
+		e := &entry{spec: job.Spec, schedule: schedule, base: now}
+		if _, isInterval := schedule.(Every); !isInterval {
+			e.base = schedule.Next(now)
+		}
+		s.setNext(e)
+		entries[job.Key] = e
+	}
+	s.entries = entries
+
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid schedules: %v", invalid)
+	}
+	return nil
+}
+
+// Due returns the keys of the jobs due at now, in order. They stay due until
+// Ran reports that they ran.
+func (s *Scheduler) Due(now time.Time) []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var due []string
+	for key, e := range s.entries {
+		if isDue(e, now) {
+			due = append(due, key)
+		}
+	}
+
+	sort.Strings(due)
+	return due
+}
+
+// Ran schedules the next run of a due job that ran at now. Runs missed while
+// the job was late are skipped. Unknown and not yet due jobs are left alone.
+func (s *Scheduler) Ran(key string, now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || !isDue(e, now) {
+		return
+	}
+
+	e.base = e.schedule.Next(e.base)
+	if !e.base.IsZero() && !e.base.After(now) {
+		e.base = e.schedule.Next(now)
+	}
+	s.setNext(e)
+}
+
+// isDue reports whether the next run of an entry is due at now
+func isDue(e *entry, now time.Time) bool {
+	return !e.next.IsZero() && !e.next.After(now)
+}
+
+// NextRun returns the time of the earliest run, or the zero time without jobs
+func (s *Scheduler) NextRun() time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var next time.Time
+	for _, e := range s.entries {
+		if !e.next.IsZero() && (next.IsZero() || e.next.Before(next)) {
+			next = e.next
+		}
+	}
+	return next
+}
+
+// setNext sets the next run of an entry from its base time plus jitter
+func (s *Scheduler) setNext(e *entry) {
+	if e.base.IsZero() {
+		e.next = time.Time{}
+		return
+	}
+
+	window := e.schedule.Next(e.base).Sub(e.base) / jitterDivisor
+	if window > MaxJitter {
+		window = MaxJitter
+	}
+
+	e.next = e.base
+	if window > 0 {
+		e.next = e.next.Add(time.Duration(s.random(int64(window))))
+	}
+}
@@ -0,0 +1,152 @@
+package schedule
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// runDue returns the jobs due at now and reports them as run
+func runDue(scheduler *Scheduler, now time.Time) []string {
+	due := scheduler.Due(now)
+	for _, key := range due {
+		scheduler.Ran(key, now)
+	}
+	return due
+}
+
+func TestSchedulerDue(t *testing.T) {
+	scheduler := NewScheduler()
+	scheduler.random = func(n int64) int64 { return 0 }
+
+	now := time.Date(2024, time.March, 15, 10, 7, 0, 0, time.UTC)
+	err := scheduler.Update(now, []Job{
+		{Key: "fast", Spec: EverySpec(5 * time.Minute)},
+		{Key: "daily", Spec: "0 3 * * *"},
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	// New interval jobs run right away without jitter, cron jobs at their next time
+	if due := runDue(scheduler, now); !reflect.DeepEqual(due, []string{"fast"}) {
+		t.Errorf("expected fast to be due, got %v", due)
+	}
+	if due := runDue(scheduler, now.Add(4*time.Minute)); len(due) != 0 {
+		t.Errorf("expected nothing due, got %v", due)
+	}
+	if next := scheduler.NextRun(); !next.Equal(now.Add(5 * time.Minute)) {
+		t.Errorf("expected next run at %v, got %v", now.Add(5*time.Minute), next)
+	}
+
+	// Missed runs are skipped rather than run back to back
+	late := time.Date(2024, time.March, 16, 3, 1, 0, 0, time.UTC)
+	if due := runDue(scheduler, late); !reflect.DeepEqual(due, []string{"daily", "fast"}) {
+		t.Errorf("expected both jobs due, got %v", due)
+	}
+	if due := runDue(scheduler, late.Add(time.Minute)); len(due) != 0 {
+		t.Errorf("expected nothing due after catching up, got %v", due)
+	}
+}
+
+func TestSchedulerRan(t *testing.T) {
+	scheduler := NewScheduler()
+	scheduler.random = func(n int64) int64 { return 0 }
+
+	now := time.Date(2024, time.March, 15, 10, 0, 0, 0, time.UTC)
+	if err := scheduler.Update(now, []Job{{Key: "a", Spec: EverySpec(time.Hour)}, {Key: "b", Spec: EverySpec(time.Hour)}}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	// A due job that did not run stays due
+	scheduler.Due(now)
+	scheduler.Ran("a", now)
+	scheduler.Ran("unknown", now)
+	if due := scheduler.Due(now.Add(time.Minute)); !reflect.DeepEqual(due, []string{"b"}) {
+		t.Errorf("expected the job that did not run to stay due, got %v", due)
+	}
+
+	// Reporting a run before the job is due does not skip its run
+	scheduler.Ran("a", now.Add(time.Minute))
+	if next := scheduler.NextRun(); !next.Equal(now) {
+		t.Errorf("expected b to be due at %v, got %v", now, next)
+	}
+	if due := runDue(scheduler, now.Add(time.Hour)); !reflect.DeepEqual(due, []string{"a", "b"}) {
+		t.Errorf("expected both jobs due after an hour, got %v", due)
+	}
+}
+
+func TestSchedulerUpdate(t *testing.T) {
+	scheduler := NewScheduler()
+	scheduler.random = func(n int64) int64 { return 0 }
+
+	now := time.Date(2024, time.March, 15, 10, 0, 0, 0, time.UTC)
+	jobs := []Job{
+		{Key: "a", Spec: EverySpec(time.Hour)},
+		{Key: "b", Spec: EverySpec(time.Hour)},
+	}
+	if err := scheduler.Update(now, jobs); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	runDue(scheduler, now)
+
+	// Unchanged jobs keep their next run, changed and removed ones do not
+	later := now.Add(10 * time.Minute)
+	err := scheduler.Update(later, []Job{
+		{Key: "a", Spec: EverySpec(time.Hour)},
+		{Key: "b", Spec: EverySpec(5 * time.Minute)},
+		{Key: "c", Spec: "not a schedule"},
+	})
+	if err == nil {
+		t.Error("expected an error for the invalid schedule")
+	}
+
+	if due := runDue(scheduler, later); !reflect.DeepEqual(due, []string{"b"}) {
+		t.Errorf("expected only the changed job due, got %v", due)
+	}
+	if due := runDue(scheduler, now.Add(time.Hour)); !reflect.DeepEqual(due, []string{"a", "b"}) {
+		t.Errorf("expected both jobs due after an hour, got %v", due)
+	}
+
+	if err := scheduler.Update(later, nil); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if next := scheduler.NextRun(); !next.IsZero() {
+		t.Errorf("expected no next run without jobs, got %v", next)
+	}
+}
+
+func TestSchedulerJitter(t *testing.T) {
+	scheduler := NewScheduler()
+	var windows []int64
+	scheduler.random = func(n int64) int64 {
+		windows = append(windows, n)
+		return n - 1
+	}
+
+	now := time.Date(2024, time.March, 15, 10, 0, 0, 0, time.UTC)
+	err := scheduler.Update(now, []Job{
+		{Key: "fast", Spec: EverySpec(10 * time.Minute)},
+		{Key: "daily", Spec: EverySpec(24 * time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	for _, window := range windows {
+		if window != int64(time.Minute) && window != int64(MaxJitter) {
+			t.Errorf("unexpected jitter window %v", time.Duration(window))
+		}
+	}
+
+	// New jobs first run within their jitter window, not a full interval later
+	if next := scheduler.NextRun(); !next.Before(now.Add(time.Minute)) {
+		t.Errorf("expected the first run within a minute, got %v", next)
+	}
+	if due := runDue(scheduler, now.Add(time.Minute)); !reflect.DeepEqual(due, []string{"fast"}) {
+		t.Errorf("expected fast to be due within its jitter window, got %v", due)
+	}
+	if due := runDue(scheduler, now.Add(MaxJitter)); !reflect.DeepEqual(due, []string{"daily"}) {
+		t.Errorf("expected daily to be due within the maximum jitter, got %v", due)
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github-stars-notify/internal/config"
+	"github-stars-notify/internal/schedule"
+)
+
+// checkTask is a single check of a cycle, run by the worker pool
+type checkTask struct {
+	name  string // Repository or account, for logging
+	job   string // Scheduler key of the check
+	spec  string // Schedule of the check, see schedule.Parse
+	check func(ctx context.Context)
+}
+
+// repositoryTasks creates the check tasks of the watched repositories and accounts
+func (s *Service) repositoryTasks(cfg *config.Config, repositories []config.Repository) []checkTask {
+	tasks := make([]checkTask, 0, len(repositories)+len(cfg.Followers))
+
+	for _, repo := range repositories {
+		tasks = append(tasks, checkTask{
+			name: repo.Owner + "/" + repo.Repo,
+			job:  "repository:" + storageOwner(repo) + "/" + repo.Repo,
+			spec: cfg.GetSchedule(repo),
+			check: func(ctx context.Context) {
+				if err := s.checkRepository(ctx, repo); err != nil {
+					s.logger.Error("repository check failed",
+						"repo", repo.Owner+"/"+repo.Repo,
+						"error", err)
+					s.metrics.RecordCheck(storageOwner(repo), repo.Repo, "error")
+					s.metrics.RecordCheckError(storageOwner(repo), repo.Repo, "general_error")
+				}
+			},
+		})
+	}
+
+	for _, follower := range cfg.Followers {
+		tasks = append(tasks, checkTask{
+			name: follower.Login,
+			job:  "followers:" + followerKey(follower),
+			spec: cfg.GetFollowerSchedule(follower),
+			check: func(ctx context.Context) {
+				if err := s.checkFollowers(ctx, follower); err != nil {
+					s.logger.Error("follower check failed",
+						"login", follower.Login,
+						"error", err)
+				}
+			},
+		})
+	}
+
+	return tasks
+}
+
+// dueTasks updates the scheduler with the schedule of every task and returns
+// the tasks due at now. Their next run is scheduled by runTask, tasks skipped
+// at the cycle deadline stay due.
+func (s *Service) dueTasks(tasks []checkTask, now time.Time) []checkTask {
+	jobs := make([]schedule.Job, 0, len(tasks))
+	for _, task := range tasks {
+		jobs = append(jobs, schedule.Job{Key: task.job, Spec: task.spec})
+	}
+	if err := s.scheduler.Update(now, jobs); err != nil {
+		s.logger.Warn("some checks could not be scheduled", "error", err)
+	}
+
+	due := make(map[string]bool)
+	for _, job := range s.scheduler.Due(now) {
+		due[job] = true
+	}
+
+	var dueTasks []checkTask
+	for _, task := range tasks {
+		if due[task.job] {
+			dueTasks = append(dueTasks, task)
+		}
+	}
+	return dueTasks
+}
+
+// untilNextCheck returns how long to wait for the next scheduled check. It
+// never exceeds the check interval so newly discovered repositories get
+// scheduled.
+func (s *Service) untilNextCheck() time.Duration {
+	wait := s.configReloader.GetConfig().GetCheckInterval()
+	if next := s.scheduler.NextRun(); !next.IsZero() {
+		wait = min(wait, time.Until(next))
+	}
+	return max(wait, 0)
+}
+
+// runTasks runs the tasks through a pool of workers, each task under its own
+// timeout. Tasks not started before ctx is done are skipped, their number is
+// returned.
+func (s *Service) runTasks(ctx context.Context, tasks []checkTask, workers int, timeout time.Duration) int {
+	queue := make(chan checkTask)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range queue {
+				s.runTask(ctx, task, timeout)
+			}
+		}()
+	}
+
+	skipped := 0
+dispatch:
+	for i, task := range tasks {
+		// Checked first, select picks randomly when a worker is also ready
+		if ctx.Err() != nil {
+			skipped = len(tasks) - i
+			break
+		}
+
+		select {
+		case queue <- task:
+		case <-ctx.Done():
+			skipped = len(tasks) - i
+			break dispatch
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	return skipped
+}
+
+// runTask runs a single task with its own timeout, on the clients current when
+// it starts, and schedules its next run
+func (s *Service) runTask(ctx context.Context, task checkTask, timeout time.Duration) {
+	s.scheduler.Ran(task.job, time.Now())
+
+	taskCtx, cancel := context.WithTimeout(s.withClients(ctx), timeout)
+	defer cancel()
+
+	s.metrics.RecordCheckStarted()
+	defer s.metrics.RecordCheckFinished()
+
+	s.logger.Debug("processing check", "name", task.name)
+	task.check(taskCtx)
+}
+
+// cycleConcurrency returns the number of workers for a cycle. The configured
+// concurrency is scaled down with the lowest share of rate limit left on any
+// GitHub instance: full concurrency above half of the quota, a single worker
+// when it is nearly exhausted.
+func (s *Service) cycleConcurrency(ctx context.Context, cfg *config.Config) int {
+	workers := cfg.Settings.Concurrency
+	for _, instance := range cfg.GetGitHubInstances() {
+		client, _, err := s.githubClient(ctx, instance.Name)
+		if err != nil {
+			continue
+		}
+
+		// The rate limit endpoint itself does not count against the quota
+		rateLimit, err := client.GetRateLimit(ctx)
+		if err != nil {
+			s.logger.Debug("rate limit unknown, keeping configured concurrency",
+				"instance", instance.Name,
+				"error", err)
+			continue
+		}
+
+		if scaled := adaptiveConcurrency(cfg.Settings.Concurrency, rateLimit.Remaining, rateLimit.Limit); scaled < workers {
+			workers = scaled
+		}
+	}
+	return workers
+}
+
+// adaptiveConcurrency scales max workers by the share of the rate limit left,
+// reaching max at half of the quota and never going below one worker
+func adaptiveConcurrency(max, remaining, limit int) int {
+	if max <= 1 || limit <= 0 {
+		return max
+	}
+
+	workers := max * 2 * remaining / limit
+	if workers > max {
+		return max
+	}
+	if workers < 1 {
+		return 1
+	}
+	return workers
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github-stars-notify/internal/config"
+)
+
+func TestAdaptiveConcurrency(t *testing.T) {
+	tests := []struct {
+		max, remaining, limit int
+		expected              int
+	}{
+		{8, 5000, 5000, 8},
+		{8, 2500, 5000, 8},
+		{8, 1250, 5000, 4},
+		{8, 100, 5000, 1},
+		{8, 0, 5000, 1},
+		{8, 100, 0, 8}, // Unknown limit
+		{1, 5000, 5000, 1},
+	}
+
+	for _, tt := range tests {
+		if got := adaptiveConcurrency(tt.max, tt.remaining, tt.limit); got != tt.expected {
+			t.Errorf("adaptiveConcurrency(%d, %d, %d) = %d, expected %d", tt.max, tt.remaining, tt.limit, got, tt.expected)
+		}
+	}
+}
+
+func TestRunTasks(t *testing.T) {
+	cfg := &config.Config{
+		Settings: config.Settings{CheckIntervalMinutes: 10},
+		Storage:  config.StorageConfig{Type: "file", Path: "./test_data"},
+		Logging:  config.LoggingConfig{Level: "info", Format: "text"},
+	}
+
+	service, err := NewForTest(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	var running, maxRunning, completed, timedOut int32
+	task := checkTask{
+		name: "slow",
+		check: func(ctx context.Context) {
+			current := atomic.AddInt32(&running, 1)
+			for {
+				seen := atomic.LoadInt32(&maxRunning)
+				if current <= seen || atomic.CompareAndSwapInt32(&maxRunning, seen, current) {
+					break
+				}
+			}
+			select {
+			case <-time.After(20 * time.Millisecond):
+				atomic.AddInt32(&completed, 1)
+			case <-ctx.Done():
+				atomic.AddInt32(&timedOut, 1)
+			}
+			atomic.AddInt32(&running, -1)
+		},
+	}
+
+	tasks := []checkTask{task, task, task, task, task, task}
+	if skipped := service.runTasks(context.Background(), tasks, 3, time.Second); skipped != 0 {
+		t.Errorf("Expected no skipped task, got %d", skipped)
+	}
+	if completed != 6 || maxRunning != 3 {
+		t.Errorf("Expected 6 tasks on 3 workers, got %d completed with %d in parallel", completed, maxRunning)
+	}
+
+	// Each task is cut by its own timeout
+	completed = 0
+	service.runTasks(context.Background(), tasks[:2], 2, time.Millisecond)
+	if timedOut != 2 || completed != 0 {
+		t.Errorf("Expected both tasks to time out, got %d timed out and %d completed", timedOut, completed)
+	}
+
+	// Tasks not started before the cycle deadline are skipped
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if skipped := service.runTasks(ctx, tasks, 1, time.Second); skipped == 0 || skipped == len(tasks) {
+		t.Errorf("Expected some tasks to be skipped at the deadline, got %d", skipped)
+	}
+}
+
+func TestDueTasks(t *testing.T) {
+	cfg := &config.Config{
+		Repositories: []config.Repository{
+			{Owner: "owner", Repo: "flagship", IntervalMinutes: 5},
+			{Owner: "owner", Repo: "archived", Schedule: "0 3 * * *"},
+		},
+		Followers: []config.Follower{{Login: "owner"}, {Login: "nightly", Schedule: "0 3 * * *"}},
+		Settings:  config.Settings{CheckIntervalMinutes: 60},
+		Storage:   config.StorageConfig{Type: "file", Path: "./test_data"},
+		Logging:   config.LoggingConfig{Level: "info", Format: "text"},
+	}
+
+	service, err := NewForTest(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	tasks := service.repositoryTasks(cfg, cfg.Repositories)
+	now := time.Date(2024, time.March, 15, 10, 0, 0, 0, time.UTC)
+
+	// New interval checks run within their jitter window, cron checks at their time
+	seen := make(map[string]int)
+	for _, at := range []time.Time{now, now.Add(6 * time.Minute)} {
+		for _, task := range service.dueTasks(tasks, at) {
+			seen[task.name]++
+			service.scheduler.Ran(task.job, at)
+		}
+	}
+
+	if seen["owner/flagship"] == 0 || seen["owner"] != 1 {
+		t.Errorf("Expected the flagship and follower checks to run, got %v", seen)
+	}
+	if seen["owner/archived"] != 0 || seen["nightly"] != 0 {
+		t.Errorf("Expected the archived repository and nightly follower to wait for their schedule, got %v", seen)
+	}
+
+	// Checks skipped at the cycle deadline stay due
+	night := time.Date(2024, time.March, 16, 3, 5, 0, 0, time.UTC)
+	if due := service.dueTasks(tasks, night); len(due) != 4 {
+		t.Errorf("Expected every check due the next night, got %d", len(due))
+	}
+	if due := service.dueTasks(tasks, night.Add(time.Minute)); len(due) != 4 {
+		t.Errorf("Expected the checks that did not run to stay due, got %d", len(due))
+	}
+}
+
+func TestRepositoryTaskErrorMetrics(t *testing.T) {
+	cfg := &config.Config{
+		Repositories: []config.Repository{{Owner: "acme", Repo: "tool", Instance: "enterprise"}},
+		Settings:     config.Settings{CheckIntervalMinutes: 10},
+		Storage:      config.StorageConfig{Type: "file", Path: "./test_data"},
+		Logging:      config.LoggingConfig{Level: "info", Format: "text"},
+	}
+
+	service, err := NewForTest(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	// The instance is not configured, so the check fails before reaching GitHub
+	tasks := service.repositoryTasks(cfg, cfg.Repositories)
+	tasks[0].check(context.Background())
+
+	// Failures are reported under the same owner as the other metrics of the repository
+	if got := testutil.ToFloat64(service.metrics.ChecksTotal.WithLabelValues("enterprise_acme", "tool", "error")); got != 1 {
+		t.Errorf("Expected a failed check under the instance key, got %v", got)
+	}
+	if got := testutil.ToFloat64(service.metrics.CheckErrors.WithLabelValues("enterprise_acme", "tool", "general_error")); got != 1 {
+		t.Errorf("Expected a check error under the instance key, got %v", got)
+	}
+}
+
+func TestConfigReloadDuringCycle(t *testing.T) {
+	cfg := &config.Config{
+		GitHub:   config.GitHubConfig{Token: "old-token"},
+		Settings: config.Settings{CheckIntervalMinutes: 10},
+		Storage:  config.StorageConfig{Type: "file", Path: "./test_data"},
+		Logging:  config.LoggingConfig{Level: "info", Format: "text"},
+		Notifications: config.Notifications{
+			Discord: config.DiscordConfig{WebhookURL: "https://discord.com/api/webhooks/123/abc", Enabled: true},
+		},
+	}
+
+	service, err := NewForTest(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	// Every reload replaces the GitHub client and drops the notifier
+	reloaded := *cfg
+	reloaded.GitHub.Token = "new-token"
+	reloaded.Notifications.Discord.Enabled = false
+
+	var changed int32
+	task := checkTask{
+		name: "reader",
+		check: func(ctx context.Context) {
+			clients := service.clientsFor(ctx)
+			for i := 0; i < 100; i++ {
+				client, _, err := service.githubClient(ctx, "")
+				if err != nil || client != clients.github || len(service.clientsFor(ctx).notifiers) != len(clients.notifiers) {
+					atomic.AddInt32(&changed, 1)
+				}
+			}
+		},
+	}
+	tasks := []checkTask{task, task, task, task, task, task, task, task}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			if err := service.handleConfigReload(cfg, &reloaded); err != nil {
+				t.Errorf("handleConfigReload failed: %v", err)
+			}
+		}
+	}()
+	service.runTasks(context.Background(), tasks, 4, time.Second)
+	<-done
+
+	if changed != 0 {
+		t.Errorf("Expected every check to keep its clients, %d lookups saw a reload", changed)
+	}
+	if clients := service.clients.Load(); len(clients.notifiers) != 0 {
+		t.Errorf("Expected the reloaded notifiers, got %d", len(clients.notifiers))
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github-stars-notify/internal/config"
+	"github-stars-notify/internal/github"
+)
+
+// discoveredRepositories caches the repositories of an owner listed for
+// wildcard repository entries
+type discoveredRepositories struct {
+	repositories []github.Repository
+	refreshedAt  time.Time
+}
+
+// discoveryKey identifies the repository listing a wildcard entry expands:
+// its owner on its GitHub instance. Entries of the same owner share the
+// listing and apply their own filters to it.
+func discoveryKey(entry config.Repository) string {
+	return entry.Instance + "/" + strings.ToLower(entry.Owner)
+}
+
+// resolveRepositories expands wildcard entries into concrete repositories.
+// Explicit entries take precedence over discovered ones with the same name.
+func (s *Service) resolveRepositories(ctx context.Context, cfg *config.Config) []config.Repository {
+	var resolved []config.Repository
+	seen := make(map[string]bool)
+
+	for _, repo := range cfg.Repositories {
+		if !repo.IsWildcard() {
+			resolved = append(resolved, repo)
+			seen[storageOwner(repo)+"/"+repo.Repo] = true
+		}
+	}
+
+	for _, entry := range cfg.Repositories {
+		if !entry.IsWildcard() {
+			continue
+		}
+
+		for _, repo := range s.discoverRepositories(ctx, cfg, entry) {
+			key := storageOwner(repo) + "/" + repo.Repo
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			resolved = append(resolved, repo)
+		}
+	}
+
+	return resolved
+}
+
+// discoverRepositories returns the expansion of a wildcard entry, refreshing
+// the repositories of its owner through the GitHub API when the cached listing
+// is older than the refresh interval. The filters and settings of the entry
+// are applied on every call, so configuration changes take effect right away.
+func (s *Service) discoverRepositories(ctx context.Context, cfg *config.Config, entry config.Repository) []config.Repository {
+	key := discoveryKey(entry)
+
+	s.discoveryMu.Lock()
+	cached, ok := s.discovered[key]
+	s.discoveryMu.Unlock()
+
+	if ok && time.Since(cached.refreshedAt) < cfg.GetDiscoveryRefreshInterval() {
+		return expandRepositories(entry, cached.repositories)
+	}
+
+	client, _, err := s.githubClient(ctx, entry.Instance)
+	if err != nil {
+		s.logger.Error("repository discovery failed",
+			"owner", entry.Owner,
+			"error", err)
+		return nil
+	}
+
+	repos, err := client.ListRepositoriesWithRetry(ctx, entry.Owner)
+	if err != nil {
+		s.metrics.RecordGitHubAPIRequest("repositories", "error")
+		s.logger.Error("repository discovery failed",
+			"owner", entry.Owner,
+			"error", err)
+		// Keep watching the previous expansion until discovery succeeds again
+		return expandRepositories(entry, cached.repositories)
+	}
+	s.metrics.RecordGitHubAPIRequest("repositories", "success")
+
+	expanded := expandRepositories(entry, repos)
+
+	s.discoveryMu.Lock()
+	s.discovered[key] = discoveredRepositories{
+		repositories: repos,
+		refreshedAt:  time.Now(),
+	}
+	s.discoveryMu.Unlock()
+
+	s.logger.Info("repository discovery completed",
+		"owner", entry.Owner,
+		"found", len(repos),
+		"watched", len(expanded))
+
+	return expanded
+}
+
+// expandRepositories turns the repositories of an owner into concrete entries
+// that pass the wildcard entry's filters
+func expandRepositories(entry config.Repository, repos []github.Repository) []config.Repository {
+	var expanded []config.Repository
+	for _, repo := range repos {
+		if !entry.Matches(repo.Name, repo.Topics, repo.Archived, repo.Fork) {
+			continue
+		}
+
+		expanded = append(expanded, config.Repository{
+			Owner:      entry.Owner,
+			Repo:       repo.Name,
+			Baseline:   entry.Baseline,
+			Instance:   entry.Instance,
+			Track:      entry.Track,
+			Releases:   entry.Releases,
+			Milestones: entry.Milestones,
+
+			IntervalMinutes: entry.IntervalMinutes,
+			Schedule:        entry.Schedule,
+		})
+	}
+	return expanded
+}
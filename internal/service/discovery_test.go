@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github-stars-notify/internal/config"
+	"github-stars-notify/internal/github"
+)
+
+func TestDiscoverRepositories(t *testing.T) {
+	cfg := &config.Config{
+		Repositories: []config.Repository{
+			{Owner: "Acme", Repo: config.WildcardRepo, Topics: []string{"cli"}},
+			{Owner: "acme", Repo: config.WildcardRepo, SkipArchived: true, Baseline: "silent"},
+		},
+		Settings: config.Settings{CheckIntervalMinutes: 10, DiscoveryRefreshMinutes: 60},
+		Storage:  config.StorageConfig{Type: "file", Path: "./test_data"},
+		Logging:  config.LoggingConfig{Level: "info", Format: "text"},
+	}
+
+	service, err := NewForTest(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	// Entries of the same owner share its listing, whatever their filters
+	if discoveryKey(cfg.Repositories[0]) != discoveryKey(cfg.Repositories[1]) {
+		t.Errorf("Expected entries of the same owner to share a key, got %q and %q",
+			discoveryKey(cfg.Repositories[0]), discoveryKey(cfg.Repositories[1]))
+	}
+	if other := (config.Repository{Owner: "acme", Instance: "enterprise"}); discoveryKey(other) == discoveryKey(cfg.Repositories[0]) {
+		t.Error("Expected the instance to be part of the key")
+	}
+
+	service.discovered[discoveryKey(cfg.Repositories[0])] = discoveredRepositories{
+		repositories: []github.Repository{
+			{Name: "tool", Topics: []string{"cli"}},
+			{Name: "old", Archived: true},
+		},
+		refreshedAt: time.Now(),
+	}
+
+	// Each entry applies its own filters and settings to the cached listing
+	ctx := context.Background()
+	if repos := service.discoverRepositories(ctx, cfg, cfg.Repositories[0]); len(repos) != 1 || repos[0].Repo != "tool" {
+		t.Errorf("Expected only the cli repository, got %+v", repos)
+	}
+	repos := service.discoverRepositories(ctx, cfg, cfg.Repositories[1])
+	if len(repos) != 1 || repos[0].Repo != "tool" || repos[0].Baseline != "silent" {
+		t.Errorf("Expected the repository not archived with the entry's baseline, got %+v", repos)
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+
+	"github-stars-notify/internal/github"
+	"github-stars-notify/internal/logger"
+)
+
+// enrichStargazers attaches user profiles to the stargazers about to be announced.
+// Cached profiles are always used, lookups stop once the remaining API quota
+// would drop below the configured reserve. The input slice is not modified.
+func (s *Service) enrichStargazers(ctx context.Context, repoLogger *logger.Logger, client *github.RetryableClient, instance string, stargazers []github.Stargazer) []github.Stargazer {
+	cfg := s.configReloader.GetConfig()
+	enrichment := cfg.Notifications.Enrichment
+	if !enrichment.Enabled || len(stargazers) == 0 {
+		return stargazers
+	}
+
+	ttl := cfg.GetProfileCacheTTL()
+
+	// Profiles are fetched from the REST API whatever the backend, the rate
+	// limit endpoint itself does not count against the quota
+	budget := 0
+	if rateLimit, err := client.GetCoreRateLimit(ctx); err != nil {
+		repoLogger.Debug("rate limit unknown, only using cached profiles", "error", err)
+	} else {
+		budget = rateLimit.Remaining - enrichment.MinRateLimitRemaining
+	}
+
+	enriched := make([]github.Stargazer, len(stargazers))
+	copy(enriched, stargazers)
+
+	var fetched, cached, skipped int
+	for i := range enriched {
+		if i >= enrichment.MaxProfiles {
+			break
+		}
+
+		key := profileKey(instance, enriched[i].Login)
+		profile, ok, err := s.storage.GetUserProfile(ctx, key, ttl)
+		if err != nil {
+			repoLogger.Warn("failed to load cached profile", "login", enriched[i].Login, "error", err)
+		}
+		if ok {
+			s.metrics.RecordGitHubCacheHit("user_profile")
+			enriched[i].Profile = profile
+			cached++
+			continue
+		}
+
+		if budget <= 0 {
+			skipped++
+			continue
+		}
+		budget--
+
+		profile, err = client.GetUser(ctx, enriched[i].Login)
+		if err != nil {
+			s.metrics.RecordGitHubAPIRequest("users", "error")
+			repoLogger.Debug("profile lookup failed",
+				"login", enriched[i].Login,
+				"error", err)
+			continue
+		}
+		s.metrics.RecordGitHubAPIRequest("users", "success")
+		s.metrics.RecordGitHubCacheMiss("user_profile")
+
+		if err := s.storage.SaveUserProfile(ctx, key, profile, ttl); err != nil {
+			repoLogger.Warn("failed to cache profile", "login", profile.Login, "error", err)
+		}
+		enriched[i].Profile = profile
+		fetched++
+	}
+
+	repoLogger.Debug("enriched stargazer profiles",
+		"fetched", fetched,
+		"cached", cached,
+		"skipped", skipped)
+
+	return enriched
+}
+
+// profileKey returns the profile cache key of a user on a GitHub instance
+func profileKey(instance, login string) string {
+	if instance == "" {
+		return login
+	}
+	return instance + "/" + login
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github-stars-notify/internal/config"
+	"github-stars-notify/internal/errors"
+	"github-stars-notify/internal/github"
+	"github-stars-notify/internal/notify"
+)
+
+// checkFollowers checks a user or organization for new followers
+func (s *Service) checkFollowers(ctx context.Context, follower config.Follower) error {
+	login := follower.Login
+	accountLogger := s.logger.WithContext("login", login)
+	if follower.Instance != "" {
+		accountLogger = accountLogger.WithContext("instance", follower.Instance)
+	}
+
+	accountLogger.Debug("checking followers")
+
+	client, instance, err := s.githubClient(ctx, follower.Instance)
+	if err != nil {
+		return err
+	}
+	ctx = notify.WithWebURL(ctx, instance.WebURL)
+
+	key := followerKey(follower)
+
+	// Load previously stored followers
+	previous, err := s.storage.LoadFollowers(ctx, key)
+	if err != nil {
+		return errors.NewServiceError("storage", "failed to load followers data", err)
+	}
+
+	followers, err := client.GetFollowersWithRetry(ctx, login)
+	if err != nil {
+		s.metrics.RecordGitHubAPIRequest("followers", "error")
+		return errors.NewServiceError("github", "failed to fetch followers", err)
+	}
+	s.metrics.RecordGitHubAPIRequest("followers", "success")
+	s.metrics.RecordFollowers(key, len(followers))
+
+	accountLogger.Info("follower check completed", "total_followers", len(followers))
+
+	diff, err := s.storage.DiffFollowers(ctx, key, followers)
+	if err != nil {
+		return errors.NewServiceError("storage", "failed to get new followers", err)
+	}
+
+	// Follow events carry no timestamp, so the summary baseline announces none of
+	// the existing followers of a newly watched account
+	added := diff.Added
+	if previous.LastCheck.IsZero() && len(previous.Followers) == 0 {
+		cfg := s.configReloader.GetConfig()
+		added = baseline(cfg, cfg.Settings.Baseline, added, func(github.Stargazer) time.Time { return time.Time{} })
+
+		accountLogger.Info("recorded follower baseline for new account",
+			"mode", cfg.Settings.Baseline,
+			"followers", len(diff.Added),
+			"announced", len(added))
+	}
+
+	if len(added) > 0 {
+		accountLogger.Info("new followers detected", "count", len(added))
+		s.metrics.RecordNewFollowers(key, len(added))
+
+		announced := s.enrichStargazers(ctx, accountLogger, client, follower.Instance, added)
+		s.sendNotifications(ctx, accountLogger, notify.FollowersEvent(login, announced))
+	} else {
+		accountLogger.Debug("no new followers found")
+	}
+
+	if err := s.storage.SaveFollowers(ctx, key, followers); err != nil {
+		return errors.NewServiceError("storage", "failed to save followers data", err)
+	}
+
+	return nil
+}
+
+// followerKey returns the key under which the followers of an account are stored and reported
+func followerKey(follower config.Follower) string {
+	if follower.Instance == "" {
+		return follower.Login
+	}
+	return follower.Instance + "_" + follower.Login
+}
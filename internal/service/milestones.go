@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+
+	"github-stars-notify/internal/config"
+	"github-stars-notify/internal/errors"
+	"github-stars-notify/internal/logger"
+	"github-stars-notify/internal/notify"
+	"github-stars-notify/internal/storage"
+)
+
+// checkMilestones celebrates the star milestones a repository crossed since the
+// previous check. Reached milestones are persisted so a restart or a brief dip
+// below a threshold never announces it twice.
+func (s *Service) checkMilestones(ctx context.Context, repoLogger *logger.Logger, repository config.Repository, key string, previous *storage.RepoSummary, stars int) error {
+	milestones := s.configReloader.GetConfig().GetMilestones(repository)
+	if len(milestones) == 0 {
+		return nil
+	}
+
+	// Milestones a newly watched repository already passed are recorded silently
+	previousStars := previous.StargazerCount
+	if previous.LastCheck.IsZero() && previousStars == 0 {
+		previousStars = stars
+	}
+
+	reached, announced := detectMilestones(milestones, previous.Milestones, previousStars, stars)
+	if len(reached) == 0 {
+		return nil
+	}
+
+	owner, repo := repository.Owner, repository.Repo
+	if announced > 0 {
+		repoLogger.Info("star milestone reached", "milestone", announced, "stars", stars)
+
+		s.sendNotifications(ctx, repoLogger, notify.MilestoneEvent(owner, repo, announced, stars))
+	}
+
+	if err := s.storage.RecordMilestones(ctx, key, repo, reached); err != nil {
+		s.metrics.RecordCheckError(key, repo, "storage_save_error")
+		return errors.NewServiceError("storage", "failed to record milestones", err)
+	}
+
+	return nil
+}
+
+// detectMilestones returns the milestones reached at stars that were not reached
+// before, and the highest of them crossed since previousStars, which is the one
+// to announce (0 when none). Announcing only the highest avoids a burst of
+// messages when a repository jumps over several thresholds at once.
+func detectMilestones(milestones, alreadyReached []int, previousStars, stars int) (reached []int, announced int) {
+	known := make(map[int]bool, len(alreadyReached))
+	for _, milestone := range alreadyReached {
+		known[milestone] = true
+	}
+
+	for _, milestone := range milestones {
+		if milestone > stars || known[milestone] {
+			continue
+		}
+		reached = append(reached, milestone)
+		if milestone > previousStars && milestone > announced {
+			announced = milestone
+		}
+	}
+
+	return reached, announced
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github-stars-notify/internal/config"
+	"github-stars-notify/internal/errors"
+	"github-stars-notify/internal/github"
+	"github-stars-notify/internal/logger"
+	"github-stars-notify/internal/notify"
+	"github-stars-notify/internal/storage"
+)
+
+// checkReleases checks a repository for new releases. key is the owner under
+// which the repository is stored and reported.
+func (s *Service) checkReleases(ctx context.Context, repoLogger *logger.Logger, client *github.RetryableClient, repository config.Repository, key string) error {
+	owner, repo := repository.Owner, repository.Repo
+
+	// Load previously seen releases
+	previous, err := s.storage.LoadReleases(ctx, key, repo)
+	if err != nil {
+		s.metrics.RecordCheckError(key, repo, "storage_error")
+		return errors.NewServiceError("storage", "failed to load releases data", err)
+	}
+
+	fetched, err := client.GetReleasesWithRetry(ctx, owner, repo)
+	if err != nil {
+		s.metrics.RecordCheckError(key, repo, "github_api_error")
+		s.metrics.RecordGitHubAPIRequest("releases", "error")
+		return errors.NewServiceError("github", "failed to fetch releases", err)
+	}
+	s.metrics.RecordGitHubAPIRequest("releases", "success")
+
+	// Releases that are filtered out are not marked as seen, so a draft is
+	// still announced once it is published
+	releases := filterReleases(fetched, repository.Releases)
+
+	downloads := 0
+	for _, release := range releases {
+		downloads += release.DownloadCount()
+	}
+	s.metrics.RecordReleaseDownloads(key, repo, downloads)
+
+	repoLogger.Info("release check completed", "releases", len(releases))
+
+	newReleases, err := s.storage.GetNewReleases(ctx, key, repo, releases)
+	if err != nil {
+		s.metrics.RecordCheckError(key, repo, "storage_error")
+		return errors.NewServiceError("storage", "failed to get new releases", err)
+	}
+
+	// Existing releases of a newly watched repository follow the same baseline as stars
+	filter := storage.ReleaseFilter{Prereleases: repository.Releases.Prereleases, Drafts: repository.Releases.Drafts}
+	if previous.LastCheck.IsZero() && len(previous.ReleaseIDs) == 0 {
+		newReleases = s.applyReleaseBaseline(repoLogger, repository, newReleases)
+	} else if previous.Filter != nil && *previous.Filter != filter {
+		newReleases = applyReleaseFilterChange(repoLogger, *previous.Filter, newReleases)
+	}
+
+	if len(newReleases) > 0 {
+		repoLogger.Info("new releases detected", "count", len(newReleases))
+		s.metrics.RecordNewReleases(key, repo, len(newReleases))
+
+		s.sendNotifications(ctx, repoLogger, notify.ReleasesEvent(owner, repo, newReleases))
+	} else {
+		repoLogger.Debug("no new releases found")
+	}
+
+	if err := s.storage.SaveReleases(ctx, key, repo, releases, filter); err != nil {
+		s.metrics.RecordCheckError(key, repo, "storage_save_error")
+		return errors.NewServiceError("storage", "failed to save releases data", err)
+	}
+
+	return nil
+}
+
+// applyReleaseBaseline filters the releases of a repository seen for the first
+// time according to its baseline mode and returns the ones to announce
+func (s *Service) applyReleaseBaseline(repoLogger *logger.Logger, repository config.Repository, releases []github.Release) []github.Release {
+	cfg := s.configReloader.GetConfig()
+	mode := cfg.GetBaseline(repository)
+	announced := baseline(cfg, mode, releases, releaseTime)
+
+	repoLogger.Info("recorded release baseline for new repository",
+		"mode", mode,
+		"releases", len(releases),
+		"announced", len(announced))
+
+	return announced
+}
+
+// applyReleaseFilterChange drops the new releases that only pass the filter
+// since it was changed from previous. They were published before the change,
+// so they are marked as seen rather than announced.
+func applyReleaseFilterChange(repoLogger *logger.Logger, previous storage.ReleaseFilter, releases []github.Release) []github.Release {
+	options := config.ReleaseOptions{Prereleases: previous.Prereleases, Drafts: previous.Drafts}
+
+	var announced []github.Release
+	for _, release := range releases {
+		if releaseAllowed(release, options) {
+			announced = append(announced, release)
+		}
+	}
+
+	repoLogger.Info("recorded release baseline for changed release filter",
+		"releases", len(releases),
+		"announced", len(announced))
+
+	return announced
+}
+
+// filterReleases drops prereleases and drafts unless the repository opts in to them
+func filterReleases(releases []github.Release, options config.ReleaseOptions) []github.Release {
+	var filtered []github.Release
+	for _, release := range releases {
+		if releaseAllowed(release, options) {
+			filtered = append(filtered, release)
+		}
+	}
+	return filtered
+}
+
+// releaseAllowed reports whether a release passes the prerelease and draft options
+func releaseAllowed(release github.Release, options config.ReleaseOptions) bool {
+	if release.Draft && !options.Drafts {
+		return false
+	}
+	if release.Prerelease && !options.Prereleases {
+		return false
+	}
+	return true
+}
+
+// releaseTime returns when a release was published, or created for drafts
+func releaseTime(release github.Release) time.Time {
+	if release.PublishedAt.IsZero() {
+		return release.CreatedAt
+	}
+	return release.PublishedAt
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github-stars-notify/internal/config"
+	"github-stars-notify/internal/errors"
+	"github-stars-notify/internal/github"
+	"github-stars-notify/internal/logger"
+	"github-stars-notify/internal/notify"
+	"github-stars-notify/internal/storage"
+)
+
+// resolveRepository looks up the current name of a repository before it is
+// checked. Renamed and transferred repositories have their stored data moved to
+// the new name, which is returned along with its storage key and its current
+// GitHub metadata. info is nil when the repository is suspended and must be
+// skipped.
+func (s *Service) resolveRepository(ctx context.Context, repoLogger *logger.Logger, client *github.RetryableClient, repository config.Repository, key string) (resolved config.Repository, resolvedKey string, info *github.Repository, err error) {
+	state, err := s.storage.GetRepositoryState(ctx, key, repository.Repo)
+	if err != nil {
+		s.metrics.RecordCheckError(key, repository.Repo, "storage_error")
+		return repository, key, nil, errors.NewServiceError("storage", "failed to load repository state", err)
+	}
+
+	// Follow a rename detected by an earlier check until the configuration is updated
+	if state.RenamedTo != "" {
+		repoLogger.Warn("repository was renamed, update the configuration to use the new name",
+			"renamed_to", state.RenamedTo)
+
+		repository = renamedRepository(repository, state.RenamedTo)
+		key = storageOwner(repository)
+		if state, err = s.storage.GetRepositoryState(ctx, key, repository.Repo); err != nil {
+			s.metrics.RecordCheckError(key, repository.Repo, "storage_error")
+			return repository, key, nil, errors.NewServiceError("storage", "failed to load repository state", err)
+		}
+	}
+
+	cfg := s.configReloader.GetConfig()
+	if state.IsSuspended() && time.Since(state.SuspendedAt) < cfg.GetSuspendedRecheckInterval() {
+		repoLogger.Debug("skipping suspended repository", "suspended_at", state.SuspendedAt)
+		s.metrics.RecordCheck(key, repository.Repo, "suspended")
+		return repository, key, nil, nil
+	}
+
+	// Prefer the numeric ID once known, it keeps resolving after any number of renames
+	if state.ID != 0 {
+		info, err = client.GetRepositoryByIDWithRetry(ctx, repository.Owner, state.ID)
+	} else {
+		info, err = client.GetRepositoryWithRetry(ctx, repository.Owner, repository.Repo)
+	}
+	if err != nil {
+		s.metrics.RecordGitHubAPIRequest("repository", "error")
+		if gitHubErr, isAPIErr := err.(*errors.GitHubAPIError); isAPIErr && gitHubErr.IsNotFound() {
+			return repository, key, nil, s.recordNotFound(ctx, repoLogger, repository, key, state, err)
+		}
+		s.metrics.RecordCheckError(key, repository.Repo, "github_api_error")
+		return repository, key, nil, errors.NewServiceError("github", "failed to resolve repository", err)
+	}
+	s.metrics.RecordGitHubAPIRequest("repository", "success")
+
+	if state.IsSuspended() {
+		repoLogger.Info("suspended repository is accessible again, resuming checks")
+	}
+	state.ID = info.ID
+	state.NotFoundCount = 0
+	state.SuspendedAt = time.Time{}
+
+	// GitHub names are case-insensitive, only a different name is a rename
+	if info.FullName != "" && !strings.EqualFold(info.FullName, repository.Owner+"/"+repository.Repo) {
+		renamed := renamedRepository(repository, info.FullName)
+		renamedKey := storageOwner(renamed)
+
+		repoLogger.Warn("repository was renamed, update the configuration to use the new name",
+			"renamed_to", info.FullName)
+
+		if err := s.storage.RenameRepository(ctx, key, repository.Repo, renamedKey, renamed.Repo); err != nil {
+			s.metrics.RecordCheckError(key, repository.Repo, "storage_error")
+			return repository, key, nil, errors.NewServiceError("storage", "failed to migrate renamed repository", err)
+		}
+		s.sendNotifications(ctx, repoLogger, notify.RenamedEvent(repository.Owner, repository.Repo, info.FullName))
+
+		repository, key = renamed, renamedKey
+	}
+
+	if err := s.storage.SetRepositoryState(ctx, key, repository.Repo, state); err != nil {
+		s.metrics.RecordCheckError(key, repository.Repo, "storage_save_error")
+		return repository, key, nil, errors.NewServiceError("storage", "failed to save repository state", err)
+	}
+
+	return repository, key, info, nil
+}
+
+// recordNotFound counts a 404 answer for a repository and suspends it once the
+// configured number of consecutive checks failed. It returns the error to
+// report for the check, which is nil once the repository is suspended.
+func (s *Service) recordNotFound(ctx context.Context, repoLogger *logger.Logger, repository config.Repository, key string, state *storage.RepositoryState, cause error) error {
+	cfg := s.configReloader.GetConfig()
+	wasSuspended := state.IsSuspended()
+
+	state.NotFoundCount++
+	if state.NotFoundCount >= cfg.Settings.SuspendAfterNotFound {
+		state.SuspendedAt = time.Now()
+	}
+
+	if err := s.storage.SetRepositoryState(ctx, key, repository.Repo, state); err != nil {
+		s.metrics.RecordCheckError(key, repository.Repo, "storage_save_error")
+		return errors.NewServiceError("storage", "failed to save repository state", err)
+	}
+
+	if !state.IsSuspended() {
+		s.metrics.RecordCheckError(key, repository.Repo, "not_found")
+		return errors.NewServiceError("github", "repository not found, it may have been deleted or made private", cause)
+	}
+
+	s.metrics.RecordCheck(key, repository.Repo, "suspended")
+	if !wasSuspended {
+		repoLogger.Warn("repository not found, suspending checks",
+			"not_found_count", state.NotFoundCount,
+			"recheck_interval", cfg.GetSuspendedRecheckInterval())
+		s.sendNotifications(ctx, repoLogger, notify.SuspendedEvent(repository.Owner, repository.Repo))
+	}
+	return nil
+}
+
+// renamedRepository returns a copy of repository pointing at fullName ("owner/repo")
+func renamedRepository(repository config.Repository, fullName string) config.Repository {
+	if owner, repo, ok := strings.Cut(fullName, "/"); ok {
+		repository.Owner, repository.Repo = owner, repo
+	}
+	return repository
+}
@@ -6,6 +6,9 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github-stars-notify/internal/config"
@@ -14,6 +17,7 @@ import (
 	"github-stars-notify/internal/logger"
 	"github-stars-notify/internal/metrics"
 	"github-stars-notify/internal/notify"
+	"github-stars-notify/internal/schedule"
 	"github-stars-notify/internal/storage"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -22,9 +26,8 @@ import (
 // Service represents the main application service
 type Service struct {
 	configReloader *config.Reloader
-	github         *github.RetryableClient
+	clients        atomic.Pointer[clientSet] // Replaced as a whole on configuration reload
 	storage        storage.Storage
-	notifiers      []notify.Notifier
 	metrics        *metrics.Metrics
 	metricsServer  *http.Server
 	logger         *logger.Logger
@@ -32,7 +35,51 @@ type Service struct {
 	running        bool
 	startTime      time.Time
 	configPath     string
-	tickerUpdate   chan struct{} // Channel to signal ticker updates
+	tickerUpdate   chan struct{} // Channel to signal schedule updates
+	scheduler      *schedule.Scheduler
+
+	// lastFullSync tracks when each repository was last fully reconciled
+	lastFullSync map[string]time.Time
+	syncMu       sync.Mutex
+
+	// discovered caches the repositories listed for wildcard entries, by discoveryKey
+	discovered  map[string]discoveredRepositories
+	discoveryMu sync.Mutex
+
+	// deliveries remembers webhook deliveries already handled
+	deliveries *deliveryCache
+
+	// webhookTasks tracks the star events processed in the background
+	webhookTasks sync.WaitGroup
+
+	// repoLocks holds a *sync.Mutex per repository, see lockRepository
+	repoLocks sync.Map
+}
+
+// clientSet holds the GitHub clients and notifiers, which a configuration
+// reload replaces while checks are running. Checks work on the snapshot taken
+// when they start, see withClients.
+type clientSet struct {
+	github    *github.RetryableClient
+	instances map[string]*github.RetryableClient // Clients of additional GitHub instances by name
+	notifiers []notify.Notifier
+}
+
+// clientSetKey is the context key of the client snapshot of a check
+type clientSetKey struct{}
+
+// withClients returns a copy of ctx carrying a snapshot of the current GitHub
+// clients and notifiers, used by everything run under it
+func (s *Service) withClients(ctx context.Context) context.Context {
+	return context.WithValue(ctx, clientSetKey{}, s.clients.Load())
+}
+
+// clientsFor returns the client snapshot carried by ctx, the current clients if it has none
+func (s *Service) clientsFor(ctx context.Context) *clientSet {
+	if clients, ok := ctx.Value(clientSetKey{}).(*clientSet); ok {
+		return clients
+	}
+	return s.clients.Load()
 }
 
 // Dependencies holds all service dependencies
@@ -44,6 +91,9 @@ type Dependencies struct {
 	Metrics    *metrics.Metrics
 	Notifiers  []notify.Notifier
 	GitHub     *github.RetryableClient
+
+	// GitHubInstances holds clients for additional GitHub instances by name
+	GitHubInstances map[string]*github.RetryableClient
 }
 
 // New creates a new service instance with automatic dependency setup
@@ -63,23 +113,26 @@ func New(configPath string) (*Service, error) {
 
 	// Create storage from config
 	stor, err := storage.NewStorageFromConfig(storage.StorageConfig{
-		Type: cfg.Storage.Type,
-		Path: cfg.Storage.Path,
+		Type:            cfg.Storage.Type,
+		Path:            cfg.Storage.Path,
+		DSN:             cfg.Storage.DSN,
+		MaxOpenConns:    cfg.Storage.MaxOpenConns,
+		MaxIdleConns:    cfg.Storage.MaxIdleConns,
+		ConnMaxLifetime: cfg.GetStorageConnMaxLifetime(),
 	})
 	if err != nil {
 		return nil, errors.NewServiceError("storage", "failed to create storage", err)
 	}
 
-	// Create GitHub client with retry logic
-	baseClient := github.NewClientWithConfig(github.Config{
-		Token:   cfg.GitHub.Token,
-		Timeout: cfg.GetGitHubTimeout(),
-	})
-	githubClient := github.NewRetryableClient(baseClient, 3, time.Second*2)
-
 	// Create metrics
 	met := metrics.NewMetrics()
 
+	// Create GitHub client with retry logic
+	githubClient, instances, err := newGitHubClients(cfg, newResponseCache(cfg, stor), met)
+	if err != nil {
+		return nil, errors.NewServiceError("github", "failed to create GitHub client", err)
+	}
+
 	// Create notifiers
 	notifiers, err := notify.CreateNotifiersWithLogger(cfg, log)
 	if err != nil {
@@ -95,6 +148,8 @@ func New(configPath string) (*Service, error) {
 		Metrics:    met,
 		Notifiers:  notifiers,
 		GitHub:     githubClient,
+
+		GitHubInstances: instances,
 	}
 
 	return NewWithDependencies(deps)
@@ -110,15 +165,22 @@ func NewWithDependencies(deps Dependencies) (*Service, error) {
 
 	service := &Service{
 		configReloader: reloader,
-		github:         deps.GitHub,
 		storage:        deps.Storage,
-		notifiers:      deps.Notifiers,
 		metrics:        deps.Metrics,
 		logger:         deps.Logger.WithComponent("service"),
 		startTime:      time.Now(),
 		configPath:     deps.ConfigPath,
-		tickerUpdate:   make(chan struct{}),
+		tickerUpdate:   make(chan struct{}, 1),
+		scheduler:      schedule.NewScheduler(),
+		lastFullSync:   make(map[string]time.Time),
+		discovered:     make(map[string]discoveredRepositories),
+		deliveries:     newDeliveryCache(),
 	}
+	service.clients.Store(&clientSet{
+		github:    deps.GitHub,
+		instances: deps.GitHubInstances,
+		notifiers: deps.Notifiers,
+	})
 
 	// Register config reload callback
 	reloader.AddCallback(service.handleConfigReload)
@@ -154,6 +216,9 @@ server:
   host: "%s"
   read_timeout_seconds: %d
   write_timeout_seconds: %d
+  webhook:
+    enabled: %t
+    secret: "%s"
 
 storage:
   type: "%s"
@@ -179,6 +244,8 @@ notifications:
 		cfg.Server.Host,
 		cfg.Server.ReadTimeout,
 		cfg.Server.WriteTimeout,
+		cfg.Server.Webhook.Enabled,
+		cfg.Server.Webhook.Secret,
 		cfg.Storage.Type,
 		cfg.Storage.Path,
 		cfg.Logging.Level,
@@ -259,12 +326,12 @@ func (s *Service) Start(ctx context.Context) error {
 	}
 
 	// Start metrics server
-	if err := s.startMetricsServer(); err != nil {
+	if err := s.startMetricsServer(serviceCtx); err != nil {
 		return errors.NewServiceError("metrics", "failed to start metrics server", err)
 	}
 
 	// Test notification connections if enabled
-	for _, notifier := range s.notifiers {
+	for _, notifier := range s.clients.Load().notifiers {
 		provider := notifier.GetProviderName()
 		s.logger.Info("testing notification connection", "provider", provider)
 
@@ -288,13 +355,13 @@ func (s *Service) Start(ctx context.Context) error {
 	config := s.configReloader.GetConfig()
 	s.logger.Info("service started successfully",
 		"repositories", len(config.Repositories),
+		"followers", len(config.Followers),
 		"check_interval", config.GetCheckInterval(),
-		"notifiers", len(s.notifiers))
+		"notifiers", len(s.clients.Load().notifiers))
 
-	// Start the monitoring loop
-	currentInterval := config.GetCheckInterval()
-	ticker := time.NewTicker(currentInterval)
-	defer ticker.Stop()
+	// Start the monitoring loop, the first cycle schedules the checks
+	wake := time.NewTimer(0)
+	defer wake.Stop()
 
 	// Start uptime updater
 	uptimeTicker := time.NewTicker(30 * time.Second)
@@ -304,23 +371,17 @@ func (s *Service) Start(ctx context.Context) error {
 		select {
 		case <-serviceCtx.Done():
 			return nil
-		case <-ticker.C:
+		case <-wake.C:
 			s.runCheck(serviceCtx)
-		case <-s.tickerUpdate:
-			// Handle immediate ticker interval updates from config changes
-			newInterval := s.configReloader.GetConfig().GetCheckInterval()
-			s.logger.Debug("received ticker update signal",
-				"current_interval", currentInterval,
-				"new_interval", newInterval,
-				"needs_update", newInterval != currentInterval)
-			if newInterval != currentInterval {
-				oldInterval := currentInterval
-				ticker.Reset(newInterval)
-				currentInterval = newInterval
-				s.logger.Info("check interval updated immediately",
-					"old_interval", oldInterval,
-					"new_interval", newInterval)
+			wake.Reset(s.untilNextCheck())
+		case _, ok := <-s.tickerUpdate:
+			if !ok {
+				return nil
 			}
+			// Run a cycle right away so changed schedules take effect, it
+			// only checks the repositories that are due
+			s.logger.Debug("received schedule update signal")
+			wake.Reset(0)
 		case <-uptimeTicker.C:
 			s.metrics.UpdateServiceUptime(s.startTime)
 		}
@@ -341,7 +402,7 @@ func (s *Service) Stop() {
 		s.cancel()
 	}
 
-	// Close ticker update channel
+	// Close schedule update channel
 	close(s.tickerUpdate)
 
 	// Stop config reloader
@@ -356,6 +417,9 @@ func (s *Service) Stop() {
 		}
 	}
 
+	// Let star events received by webhook finish with the storage
+	s.webhookTasks.Wait()
+
 	// Close storage
 	if err := s.storage.Close(); err != nil {
 		s.logger.Error("failed to close storage", "error", err)
@@ -364,8 +428,9 @@ func (s *Service) Stop() {
 	s.logger.Info("service stopped successfully")
 }
 
-// startMetricsServer starts the HTTP server for Prometheus metrics
-func (s *Service) startMetricsServer() error {
+// startMetricsServer starts the HTTP server for Prometheus metrics and GitHub
+// webhooks. Webhook deliveries are processed under ctx.
+func (s *Service) startMetricsServer(ctx context.Context) error {
 	config := s.configReloader.GetConfig()
 	addr := config.GetServerAddress()
 	if addr == "" {
@@ -374,6 +439,7 @@ func (s *Service) startMetricsServer() error {
 
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc(config.Server.Webhook.Path, s.handleWebhook(ctx))
 
 	// Add health check endpoint
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -400,113 +466,448 @@ func (s *Service) startMetricsServer() error {
 	return nil
 }
 
-// runCheck performs a single check cycle for all repositories
+// runCheck performs a check cycle for the repositories and follower accounts
+// that are due, running them concurrently until the cycle deadline
 func (s *Service) runCheck(ctx context.Context) {
-	s.logger.Info("starting repository check cycle")
-
+	start := time.Now()
 	config := s.configReloader.GetConfig()
+
+	cycleCtx, cancel := context.WithTimeout(s.withClients(ctx), config.GetCycleTimeout())
+	defer cancel()
+
+	repositories := s.resolveRepositories(cycleCtx, config)
+	tasks := s.dueTasks(s.repositoryTasks(config, repositories), start)
+	if len(tasks) == 0 {
+		s.logger.Debug("no checks due", "next_check", s.scheduler.NextRun())
+		return
+	}
+
+	s.logger.Info("starting repository check cycle", "due", len(tasks))
+
+	workers := s.cycleConcurrency(cycleCtx, config)
+	s.metrics.RecordCheckConcurrency(workers)
+
 	s.logger.Info("current configuration for check cycle",
-		"repository_count", len(config.Repositories),
+		"repository_count", len(repositories),
+		"follower_count", len(config.Followers),
+		"concurrency", workers,
 		"check_interval", config.GetCheckInterval())
 
-	for i, repo := range config.Repositories {
-		s.logger.Info("processing repository",
-			"index", i,
-			"owner", repo.Owner,
-			"repo", repo.Repo)
-
-		if err := s.checkRepository(ctx, repo.Owner, repo.Repo); err != nil {
-			s.logger.Error("repository check failed",
-				"repo", repo.Owner+"/"+repo.Repo,
-				"error", err)
-			s.metrics.RecordCheck(repo.Owner, repo.Repo, "error")
-			s.metrics.RecordCheckError(repo.Owner, repo.Repo, "general_error")
-			continue
-		}
+	skipped := s.runTasks(cycleCtx, tasks, workers, config.GetCheckTimeout())
+	if skipped > 0 {
+		s.logger.Warn("check cycle deadline reached, skipping remaining checks",
+			"skipped", skipped,
+			"cycle_timeout", config.GetCycleTimeout())
+		s.metrics.RecordChecksSkipped("cycle_deadline", skipped)
 	}
 
 	// Update rate limit metrics after each check cycle
-	if err := s.checkRateLimits(ctx); err != nil {
+	if err := s.checkRateLimits(s.withClients(ctx)); err != nil {
 		s.logger.Warn("rate limit check failed after repository cycle", "error", err)
 	}
 
-	s.logger.Info("repository check cycle completed")
+	s.metrics.RecordCycleDuration(time.Since(start))
+	s.logger.Info("repository check cycle completed",
+		"duration", time.Since(start),
+		"skipped", skipped)
 }
 
-// checkRepository checks a single repository for new stars
-func (s *Service) checkRepository(ctx context.Context, owner, repo string) error {
+// checkRepository checks a single repository for the activity it tracks
+func (s *Service) checkRepository(ctx context.Context, repository config.Repository) error {
+	owner, repo := repository.Owner, repository.Repo
 	start := time.Now()
 	repoLogger := s.logger.WithRepository(owner, repo)
+	if repository.Instance != "" {
+		repoLogger = repoLogger.WithContext("instance", repository.Instance)
+	}
 
 	repoLogger.Debug("checking repository")
 
-	// Fetch current stargazers
-	stargazers, err := s.github.GetStargazersWithRetry(ctx, owner, repo)
+	client, instance, err := s.githubClient(ctx, repository.Instance)
 	if err != nil {
-		s.metrics.RecordCheckError(owner, repo, "github_api_error")
-		s.metrics.RecordGitHubAPIRequest("stargazers", "error")
-		return errors.NewServiceError("github", "failed to fetch stargazers", err)
+		return err
+	}
+	ctx = notify.WithWebURL(ctx, instance.WebURL)
+
+	// Repositories of additional instances are stored under their own key so
+	// they cannot collide with a repository of the same name on another instance
+	key := storageOwner(repository)
+
+	// Follow renames and transfers, and skip repositories suspended after repeated 404s
+	resolved, key, info, err := s.resolveRepository(ctx, repoLogger, client, repository, key)
+	if err != nil || info == nil {
+		return err
+	}
+	if resolved.Owner != owner || resolved.Repo != repo {
+		repoLogger = repoLogger.WithContext("renamed_to", resolved.Owner+"/"+resolved.Repo)
+		repository, repo = resolved, resolved.Repo
+	}
+
+	if repository.Tracks(config.TrackStars) {
+		if err := s.checkStars(ctx, repoLogger, client, repository, key, info.StargazersCount); err != nil {
+			return err
+		}
+	}
+
+	if repository.Tracks(config.TrackForks) {
+		if err := s.checkForks(ctx, repoLogger, client, repository, key); err != nil {
+			return err
+		}
+	}
+
+	if repository.Tracks(config.TrackReleases) {
+		if err := s.checkReleases(ctx, repoLogger, client, repository, key); err != nil {
+			return err
+		}
+	}
+
+	// Record successful check
+	s.metrics.RecordCheckDuration(key, repo, time.Since(start))
+	s.metrics.RecordCheck(key, repo, "success")
+	s.metrics.RecordLastCheckTime(key, repo)
+
+	return nil
+}
+
+// checkStars checks a repository for new and removed stars. key is the owner
+// under which the repository is stored and reported.
+func (s *Service) checkStars(ctx context.Context, repoLogger *logger.Logger, client *github.RetryableClient, repository config.Repository, key string, starCount int) error {
+	owner, repo := repository.Owner, repository.Repo
+	start := time.Now()
+
+	// Webhook deliveries must not add stars between the diff and the save
+	unlock := s.lockRepository(key, repo)
+	defer unlock()
+
+	// Only the count and check time of the stored stargazers are needed up front
+	previous, err := s.storage.LoadSummary(ctx, key, repo)
+	if err != nil {
+		s.metrics.RecordCheckError(key, repo, "storage_error")
+		return errors.NewServiceError("storage", "failed to load stargazers data", err)
+	}
+
+	// The star count of the repository is enough to tell nothing changed
+	if s.starsUnchanged(key, repo, previous, starCount) {
+		repoLogger.Debug("star count unchanged, skipping stargazer fetch", "total_stars", starCount)
+		s.metrics.RecordStarPrecheck(key, repo, "skipped")
+		s.metrics.RecordRepositoryStars(key, repo, starCount)
+		return nil
+	}
+	s.metrics.RecordStarPrecheck(key, repo, "fetched")
+
+	// Fetch current stargazers, either fully or only the newest pages
+	fullSync := s.needsFullSync(key, repo, previous.StargazerCount)
+	var stargazers, fetched []github.Stargazer
+	var knownIDs map[int64]bool
+	var stars int
+	if fullSync {
+		stargazers, err = client.GetStargazersWithRetry(ctx, owner, repo)
+		if err != nil {
+			s.metrics.RecordCheckError(key, repo, "github_api_error")
+			s.metrics.RecordGitHubAPIRequest("stargazers", "error")
+			return errors.NewServiceError("github", "failed to fetch stargazers", err)
+		}
+		s.metrics.RecordGitHubAPIRequest("stargazers", "success")
+		fetched = stargazers
+		stars = len(stargazers)
+	} else {
+		knownIDs, err = s.storage.StargazerIDs(ctx, key, repo)
+		if err != nil {
+			s.metrics.RecordCheckError(key, repo, "storage_error")
+			return errors.NewServiceError("storage", "failed to load stargazer IDs", err)
+		}
+		fetched, err = client.GetStargazersIncrementalWithRetry(ctx, owner, repo, knownIDs)
+		if err != nil {
+			s.metrics.RecordCheckError(key, repo, "github_api_error")
+			s.metrics.RecordGitHubAPIRequest("stargazers_incremental", "error")
+			return errors.NewServiceError("github", "failed to fetch new stargazers", err)
+		}
+		s.metrics.RecordGitHubAPIRequest("stargazers_incremental", "success")
+	}
+
+	// Compare with previous data to find new stars, and removed ones after a
+	// full sync. An incremental fetch is compared with the stored IDs alone.
+	var diff *storage.StargazerDiff
+	if fullSync {
+		diff, err = s.storage.DiffStargazers(ctx, key, repo, stargazers)
+		if err != nil {
+			s.metrics.RecordCheckError(key, repo, "storage_error")
+			return errors.NewServiceError("storage", "failed to get new stargazers", err)
+		}
+	} else {
+		diff = &storage.StargazerDiff{}
+		for _, stargazer := range fetched {
+			if !knownIDs[stargazer.ID] {
+				knownIDs[stargazer.ID] = true
+				diff.Added = append(diff.Added, stargazer)
+			}
+		}
+		stars = previous.StargazerCount + len(diff.Added)
 	}
-	s.metrics.RecordGitHubAPIRequest("stargazers", "success")
 
 	// Record metrics
-	s.metrics.RecordRepositoryStars(owner, repo, len(stargazers))
-	s.metrics.RecordCheckDuration(owner, repo, time.Since(start))
+	s.metrics.RecordRepositoryStars(key, repo, stars)
 
 	repoLogger.Info("repository check completed",
-		"total_stars", len(stargazers),
+		"total_stars", stars,
+		"fetched", len(fetched),
+		"full_sync", fullSync,
 		"duration", time.Since(start))
 
-	// Compare with previous data to find new stars
-	newStargazers, err := s.storage.GetNewStargazers(ctx, owner, repo, stargazers)
-	if err != nil {
-		s.metrics.RecordCheckError(owner, repo, "storage_error")
-		return errors.NewServiceError("storage", "failed to get new stargazers", err)
+	// Avoid flooding notifications with every existing stargazer of a newly watched repository
+	if previous.LastCheck.IsZero() && previous.StargazerCount == 0 {
+		diff.Added = s.applyBaseline(repoLogger, repository, diff.Added)
 	}
 
-	if len(newStargazers) > 0 {
-		repoLogger.Info("new stargazers detected", "count", len(newStargazers))
-		s.metrics.RecordNewStars(owner, repo, len(newStargazers))
+	if len(diff.Added) > 0 {
+		repoLogger.Info("new stargazers detected", "count", len(diff.Added))
+		s.metrics.RecordNewStars(key, repo, len(diff.Added))
 
-		// Send notifications
-		for _, notifier := range s.notifiers {
-			provider := notifier.GetProviderName()
-			notificationStart := time.Now()
+		announced := s.enrichStargazers(ctx, repoLogger, client, repository.Instance, diff.Added)
+		s.sendNotifications(ctx, repoLogger, notify.NewStarsEvent(owner, repo, announced))
+	} else {
+		repoLogger.Debug("no new stargazers found")
+	}
 
-			if err := notifier.NotifyNewStars(ctx, owner, repo, newStargazers); err != nil {
-				repoLogger.Error("notification failed",
-					"provider", provider,
-					"error", err)
-				s.metrics.RecordNotificationError(provider, "notification_failed")
-			} else {
-				repoLogger.Info("notification sent successfully",
-					"provider", provider,
-					"stargazers", len(newStargazers))
-				s.metrics.RecordNotificationSent(provider, "success")
-			}
+	if len(diff.Removed) > 0 {
+		repoLogger.Info("lost stargazers detected", "count", len(diff.Removed))
+		s.metrics.RecordLostStars(key, repo, len(diff.Removed))
 
-			s.metrics.RecordNotificationLatency(provider, time.Since(notificationStart))
+		if err := s.storage.RecordRemovedStargazers(ctx, key, repo, diff.Removed); err != nil {
+			s.metrics.RecordCheckError(key, repo, "storage_save_error")
+			return errors.NewServiceError("storage", "failed to record removed stargazers", err)
+		}
+
+		if s.configReloader.GetConfig().Notifications.NotifyLostStars {
+			s.sendNotifications(ctx, repoLogger, notify.LostStarsEvent(owner, repo, diff.Removed))
 		}
-	} else {
-		repoLogger.Debug("no new stargazers found")
 	}
 
-	// Save current stargazers data
-	if err := s.storage.Save(ctx, owner, repo, stargazers); err != nil {
-		s.metrics.RecordCheckError(owner, repo, "storage_save_error")
+	// Save current stargazers data, an incremental fetch only appends the new ones
+	if fullSync {
+		err = s.storage.Save(ctx, key, repo, stargazers)
+	} else {
+		err = s.storage.AppendStargazers(ctx, key, repo, diff.Added)
+	}
+	if err != nil {
+		s.metrics.RecordCheckError(key, repo, "storage_save_error")
 		return errors.NewServiceError("storage", "failed to save stargazers data", err)
 	}
 
-	// Record successful check
-	s.metrics.RecordCheck(owner, repo, "success")
-	s.metrics.RecordLastCheckTime(owner, repo)
+	if fullSync {
+		s.markFullSync(key, repo)
+	}
+
+	return s.checkMilestones(ctx, repoLogger, repository, key, previous, stars)
+}
+
+// checkForks checks a repository for new forks. key is the owner under which
+// the repository is stored and reported.
+func (s *Service) checkForks(ctx context.Context, repoLogger *logger.Logger, client *github.RetryableClient, repository config.Repository, key string) error {
+	owner, repo := repository.Owner, repository.Repo
+
+	// Load previously stored forks
+	previous, err := s.storage.LoadForks(ctx, key, repo)
+	if err != nil {
+		s.metrics.RecordCheckError(key, repo, "storage_error")
+		return errors.NewServiceError("storage", "failed to load forks data", err)
+	}
+
+	forks, err := client.GetForksWithRetry(ctx, owner, repo)
+	if err != nil {
+		s.metrics.RecordCheckError(key, repo, "github_api_error")
+		s.metrics.RecordGitHubAPIRequest("forks", "error")
+		return errors.NewServiceError("github", "failed to fetch forks", err)
+	}
+	s.metrics.RecordGitHubAPIRequest("forks", "success")
+	s.metrics.RecordRepositoryForks(key, repo, len(forks))
+
+	repoLogger.Info("fork check completed", "total_forks", len(forks))
+
+	newForks, err := s.storage.GetNewForks(ctx, key, repo, forks)
+	if err != nil {
+		s.metrics.RecordCheckError(key, repo, "storage_error")
+		return errors.NewServiceError("storage", "failed to get new forks", err)
+	}
+
+	// Existing forks of a newly watched repository follow the same baseline as stars
+	if previous.LastCheck.IsZero() && len(previous.Forks) == 0 {
+		newForks = s.applyForkBaseline(repoLogger, repository, newForks)
+	}
+
+	if len(newForks) > 0 {
+		repoLogger.Info("new forks detected", "count", len(newForks))
+		s.metrics.RecordNewForks(key, repo, len(newForks))
+
+		s.sendNotifications(ctx, repoLogger, notify.NewForksEvent(owner, repo, newForks))
+	} else {
+		repoLogger.Debug("no new forks found")
+	}
+
+	if err := s.storage.SaveForks(ctx, key, repo, forks); err != nil {
+		s.metrics.RecordCheckError(key, repo, "storage_save_error")
+		return errors.NewServiceError("storage", "failed to save forks data", err)
+	}
 
 	return nil
 }
 
-// checkRateLimits checks the GitHub API rate limits
+// githubClient returns the client and settings of a GitHub instance, "" being
+// the default one, from the client snapshot of ctx
+func (s *Service) githubClient(ctx context.Context, name string) (*github.RetryableClient, config.GitHubInstance, error) {
+	instance, ok := s.configReloader.GetConfig().GetGitHubInstance(name)
+	if !ok {
+		return nil, instance, errors.NewServiceError("github", fmt.Sprintf("unknown github instance: %s", name), nil)
+	}
+
+	clients := s.clientsFor(ctx)
+	if name == "" {
+		return clients.github, instance, nil
+	}
+
+	client, ok := clients.instances[name]
+	if !ok {
+		return nil, instance, errors.NewServiceError("github", fmt.Sprintf("no client for github instance: %s", name), nil)
+	}
+	return client, instance, nil
+}
+
+// storageOwner returns the owner under which a repository is stored and reported
+func storageOwner(repository config.Repository) string {
+	if repository.Instance == "" {
+		return repository.Owner
+	}
+	return repository.Instance + "_" + repository.Owner
+}
+
+// applyBaseline filters the stargazers of a repository seen for the first time
+// according to its baseline mode and returns the ones to announce
+func (s *Service) applyBaseline(repoLogger *logger.Logger, repository config.Repository, stargazers []github.Stargazer) []github.Stargazer {
+	cfg := s.configReloader.GetConfig()
+	mode := cfg.GetBaseline(repository)
+	announced := baseline(cfg, mode, stargazers, func(sg github.Stargazer) time.Time { return sg.StarredAt })
+
+	repoLogger.Info("recorded baseline for new repository",
+		"mode", mode,
+		"stargazers", len(stargazers),
+		"announced", len(announced))
+
+	return announced
+}
+
+// applyForkBaseline filters the forks of a repository seen for the first time
+// according to its baseline mode and returns the ones to announce
+func (s *Service) applyForkBaseline(repoLogger *logger.Logger, repository config.Repository, forks []github.Repository) []github.Repository {
+	cfg := s.configReloader.GetConfig()
+	mode := cfg.GetBaseline(repository)
+	announced := baseline(cfg, mode, forks, func(fork github.Repository) time.Time { return fork.CreatedAt })
+
+	repoLogger.Info("recorded fork baseline for new repository",
+		"mode", mode,
+		"forks", len(forks),
+		"announced", len(announced))
+
+	return announced
+}
+
+// baseline returns the items to announce for a baseline mode: all of them, none,
+// or those that happened within the baseline window
+func baseline[T any](cfg *config.Config, mode string, items []T, at func(T) time.Time) []T {
+	var announced []T
+	switch mode {
+	case config.BaselineAll:
+		announced = items
+	case config.BaselineSummary:
+		cutoff := time.Now().Add(-cfg.GetBaselineWindow())
+		for _, item := range items {
+			if at(item).After(cutoff) {
+				announced = append(announced, item)
+			}
+		}
+	}
+	return announced
+}
+
+// sendNotifications sends an event through every notifier of the client
+// snapshot of ctx and records the outcome
+func (s *Service) sendNotifications(ctx context.Context, repoLogger *logger.Logger, event notify.Event) {
+	for _, notifier := range s.clientsFor(ctx).notifiers {
+		provider := notifier.GetProviderName()
+		notificationStart := time.Now()
+
+		if err := notifier.Notify(ctx, event); err != nil {
+			repoLogger.Error("notification failed",
+				"provider", provider,
+				"event", event.Kind,
+				"error", err)
+			s.metrics.RecordNotificationError(provider, "notification_failed")
+		} else {
+			repoLogger.Info("notification sent successfully",
+				"provider", provider,
+				"event", event.Kind,
+				"count", event.Count())
+			s.metrics.RecordNotificationSent(provider, "success")
+		}
+
+		s.metrics.RecordNotificationLatency(provider, time.Since(notificationStart))
+	}
+}
+
+// needsFullSync reports whether a repository must be fully reconciled instead of fetched incrementally
+func (s *Service) needsFullSync(owner, repo string, storedCount int) bool {
+	config := s.configReloader.GetConfig()
+	if !config.Settings.IncrementalFetch || storedCount == 0 {
+		return true
+	}
+
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	lastSync, ok := s.lastFullSync[owner+"/"+repo]
+	return !ok || time.Since(lastSync) >= config.GetFullSyncInterval()
+}
+
+// starsUnchanged reports whether the star count of a repository matches its
+// stored stargazers and no full reconciliation is due. A star and an unstar
+// between two checks leave the count unchanged, the periodic reconciliation
+// catches them.
+func (s *Service) starsUnchanged(owner, repo string, previous *storage.RepoSummary, starCount int) bool {
+	if previous.LastCheck.IsZero() || starCount != previous.StargazerCount {
+		return false
+	}
+
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	lastSync, ok := s.lastFullSync[owner+"/"+repo]
+	return ok && time.Since(lastSync) < s.configReloader.GetConfig().GetFullSyncInterval()
+}
+
+// markFullSync records that a repository has just been fully reconciled
+func (s *Service) markFullSync(owner, repo string) {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	s.lastFullSync[owner+"/"+repo] = time.Now()
+}
+
+// checkRateLimits checks the API rate limits of every GitHub instance
 func (s *Service) checkRateLimits(ctx context.Context) error {
-	rateLimit, err := s.github.GetRateLimitWithRetry(ctx)
+	var firstErr error
+	for _, instance := range s.configReloader.GetConfig().GetGitHubInstances() {
+		client, _, err := s.githubClient(ctx, instance.Name)
+		if err == nil {
+			err = s.checkRateLimit(ctx, instance.Name, client)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// checkRateLimit checks the API rate limit of a single GitHub instance
+func (s *Service) checkRateLimit(ctx context.Context, instance string, client *github.RetryableClient) error {
+	rateLimit, err := client.GetRateLimitWithRetry(ctx)
 	if err != nil {
 		s.metrics.RecordGitHubAPIError("rate_limit", "request_failed")
 		s.metrics.RecordGitHubAPIRequest("rate_limit", "error")
@@ -514,10 +915,15 @@ func (s *Service) checkRateLimits(ctx context.Context) error {
 	}
 	s.metrics.RecordGitHubAPIRequest("rate_limit", "success")
 
-	// Record rate limit metrics
-	s.metrics.RecordGitHubRateLimit("core", rateLimit.Limit, rateLimit.Remaining)
+	// Record rate limit metrics, prefixing the resource of additional instances
+	resource := rateLimit.Resource
+	if instance != "" {
+		resource = instance + ":" + resource
+	}
+	s.metrics.RecordGitHubRateLimit(resource, rateLimit.Limit, rateLimit.Remaining)
 
 	s.logger.Info("rate limit status",
+		"resource", resource,
 		"remaining", rateLimit.Remaining,
 		"limit", rateLimit.Limit,
 		"reset", rateLimit.Reset)
@@ -534,19 +940,25 @@ func (s *Service) checkRateLimits(ctx context.Context) error {
 // GetStatus returns the current service status
 func (s *Service) GetStatus() map[string]interface{} {
 	config := s.configReloader.GetConfig()
+	clients := s.clients.Load()
 	status := map[string]interface{}{
 		"running":        s.running,
 		"repositories":   len(config.Repositories),
-		"notifiers":      len(s.notifiers),
+		"followers":      len(config.Followers),
+		"notifiers":      len(clients.notifiers),
 		"check_interval": config.GetCheckInterval().String(),
 		"uptime":         time.Since(s.startTime).String(),
 	}
 
+	if next := s.scheduler.NextRun(); !next.IsZero() {
+		status["next_check"] = next
+	}
+
 	// Add rate limit info if available
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if rateLimit, err := s.github.GetRateLimit(ctx); err == nil {
+	if rateLimit, err := clients.github.GetRateLimit(ctx); err == nil {
 		status["rate_limit"] = map[string]interface{}{
 			"remaining": rateLimit.Remaining,
 			"limit":     rateLimit.Limit,
@@ -579,15 +991,29 @@ func (s *Service) handleConfigReload(oldConfig, newConfig *config.Config) error
 		}
 	}
 
-	// Recreate GitHub client if token or timeout changed
-	if oldConfig.GitHub.Token != newConfig.GitHub.Token ||
-		oldConfig.GetGitHubTimeout() != newConfig.GetGitHubTimeout() {
-		baseClient := github.NewClientWithConfig(github.Config{
-			Token:   newConfig.GitHub.Token,
-			Timeout: newConfig.GetGitHubTimeout(),
-		})
-		s.github = github.NewRetryableClient(baseClient, 3, time.Second*2)
-		s.logger.Info("recreated GitHub client")
+	// Running checks keep the clients and notifiers they started with, the
+	// changed ones are published as a new set once complete
+	current := s.clients.Load()
+	next := *current
+
+	// Recreate GitHub clients if instances, credentials, timeout, cache or backend changed
+	if !reflect.DeepEqual(oldConfig.GetGitHubInstances(), newConfig.GetGitHubInstances()) ||
+		oldConfig.GetGitHubTimeout() != newConfig.GetGitHubTimeout() ||
+		oldConfig.GitHub.Cache != newConfig.GitHub.Cache ||
+		oldConfig.GitHub.Backend != newConfig.GitHub.Backend {
+		// Keep cached responses unless the cache type itself changed
+		cache := current.github.ResponseCache()
+		if oldConfig.GitHub.Cache != newConfig.GitHub.Cache {
+			cache = newResponseCache(newConfig, s.storage)
+		}
+		githubClient, instances, err := newGitHubClients(newConfig, cache, s.metrics)
+		if err != nil {
+			s.logger.Warn("failed to recreate GitHub client, keeping the previous one", "error", err)
+		} else {
+			next.github = githubClient
+			next.instances = instances
+			s.logger.Info("recreated GitHub client")
+		}
 	}
 
 	// Recreate notifiers if notification config changed
@@ -595,14 +1021,14 @@ func (s *Service) handleConfigReload(oldConfig, newConfig *config.Config) error
 		notifiers, err := notify.CreateNotifiersWithLogger(newConfig, s.logger)
 		if err != nil {
 			s.logger.Warn("failed to recreate notifiers", "error", err)
-			s.notifiers = []notify.Notifier{} // Continue without notifiers
+			next.notifiers = []notify.Notifier{} // Continue without notifiers
 		} else {
-			s.notifiers = notifiers
+			next.notifiers = notifiers
 			s.logger.Info("recreated notifiers")
 		}
 
 		// Test new notification connections
-		for _, notifier := range s.notifiers {
+		for _, notifier := range next.notifiers {
 			provider := notifier.GetProviderName()
 			if err := notifier.TestConnection(context.Background()); err != nil {
 				s.metrics.RecordNotificationError(provider, "connection_test_failed")
@@ -614,6 +1040,8 @@ func (s *Service) handleConfigReload(oldConfig, newConfig *config.Config) error
 		}
 	}
 
+	s.clients.Store(&next)
+
 	// Update logger level if changed
 	if oldConfig.GetLogLevel() != newConfig.GetLogLevel() {
 		// Note: Logger level updating would need to be implemented in the logger package
@@ -622,18 +1050,20 @@ func (s *Service) handleConfigReload(oldConfig, newConfig *config.Config) error
 			"new_level", newConfig.GetLogLevel())
 	}
 
-	// Signal ticker update if check interval changed
-	if oldConfig.GetCheckInterval() != newConfig.GetCheckInterval() {
-		s.logger.Info("check interval changed, signaling ticker update",
+	// Signal a schedule update if the schedule of any check may have changed
+	if oldConfig.GetCheckInterval() != newConfig.GetCheckInterval() ||
+		!reflect.DeepEqual(oldConfig.Repositories, newConfig.Repositories) ||
+		!reflect.DeepEqual(oldConfig.Followers, newConfig.Followers) {
+		s.logger.Info("schedules may have changed, signaling schedule update",
 			"old_interval", oldConfig.GetCheckInterval(),
 			"new_interval", newConfig.GetCheckInterval())
 
-		// Non-blocking send to ticker update channel
+		// Non-blocking send, a pending signal already covers this reload
 		select {
 		case s.tickerUpdate <- struct{}{}:
-			s.logger.Debug("ticker update signal sent successfully")
+			s.logger.Debug("schedule update signal sent successfully")
 		default:
-			s.logger.Debug("ticker update channel full, signal skipped")
+			s.logger.Debug("schedule update already pending, signal skipped")
 		}
 	}
 
@@ -641,6 +1071,98 @@ func (s *Service) handleConfigReload(oldConfig, newConfig *config.Config) error
 	return nil
 }
 
+// newGitHubClients creates the client of the default GitHub instance and the
+// clients of additional instances by name. Responses of all instances share the
+// cache since it is keyed by URL. (helper function)
+func newGitHubClients(cfg *config.Config, cache github.ResponseCache, met *metrics.Metrics) (*github.RetryableClient, map[string]*github.RetryableClient, error) {
+	var defaultClient *github.RetryableClient
+	instances := make(map[string]*github.RetryableClient)
+
+	for _, instance := range cfg.GetGitHubInstances() {
+		client, err := newGitHubClient(cfg, instance, cache, met)
+		if err != nil {
+			if instance.Name != "" {
+				err = fmt.Errorf("github instance %s: %w", instance.Name, err)
+			}
+			return nil, nil, err
+		}
+
+		if instance.Name == "" {
+			defaultClient = client
+		} else {
+			instances[instance.Name] = client
+		}
+	}
+
+	return defaultClient, instances, nil
+}
+
+// newGitHubClient creates a retryable client for a GitHub instance (helper function)
+func newGitHubClient(cfg *config.Config, instance config.GitHubInstance, cache github.ResponseCache, met *metrics.Metrics) (*github.RetryableClient, error) {
+	clientCfg := github.Config{
+		Token:   instance.Token,
+		BaseURL: instance.APIURL,
+		Timeout: cfg.GetGitHubTimeout(),
+		Cache:   cache,
+	}
+	if cache != nil && met != nil {
+		clientCfg.CacheObserver = met
+	}
+
+	// GitHub App installations take precedence over personal tokens
+	tokens := instance.GetTokens()
+	if len(tokens) > 0 {
+		clientCfg.Token = tokens[0]
+	}
+	if instance.App.Enabled() {
+		privateKey, err := instance.App.LoadPrivateKey()
+		if err != nil {
+			return nil, err
+		}
+
+		tokenSource, err := github.NewAppTokenSource(github.AppConfig{
+			AppID:          instance.App.AppID,
+			PrivateKey:     privateKey,
+			InstallationID: instance.App.InstallationID,
+			BaseURL:        instance.APIURL,
+			Timeout:        cfg.GetGitHubTimeout(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		clientCfg.TokenSource = tokenSource
+	} else if len(tokens) > 1 {
+		var observer github.RateLimitObserver
+		if met != nil {
+			observer = met
+		}
+		clientCfg.TokenSource = github.NewTokenPool(tokens, observer)
+	}
+
+	var client github.API
+	switch cfg.GitHub.Backend {
+	case config.GitHubBackendGraphQL:
+		client = github.NewGraphQLClient(clientCfg)
+	default:
+		client = github.NewClientWithConfig(clientCfg)
+	}
+
+	return github.NewRetryableClient(client, 3, time.Second*2), nil
+}
+
+// newResponseCache creates the GitHub response cache selected in configuration,
+// kept in store for the storage cache (helper function)
+func newResponseCache(cfg *config.Config, store storage.Storage) github.ResponseCache {
+	switch cfg.GitHub.Cache {
+	case config.GitHubCacheStorage:
+		return storage.NewResponseCache(store)
+	case config.GitHubCacheNone:
+		return nil
+	default:
+		return github.NewMemoryCache()
+	}
+}
+
 // equalNotifications compares notification configurations (helper function)
 func equalNotifications(a, b config.Notifications) bool {
 	return a.Discord.Enabled == b.Discord.Enabled &&
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github-stars-notify/internal/config"
+	"github-stars-notify/internal/github"
+	"github-stars-notify/internal/storage"
 )
 
 func TestServiceBasic(t *testing.T) {
@@ -57,13 +59,13 @@ func TestServiceBasic(t *testing.T) {
 	if loadedCfg == nil {
 		t.Error("Config not loaded correctly")
 	}
-	if service.github == nil {
+	if service.clients.Load().github == nil {
 		t.Error("GitHub client not initialized")
 	}
 	if service.storage == nil {
 		t.Error("Storage not initialized")
 	}
-	if service.notifiers == nil {
+	if service.clients.Load().notifiers == nil {
 		t.Error("Notifiers not initialized")
 	}
 	if service.logger == nil {
@@ -99,3 +101,181 @@ func TestServiceBasic(t *testing.T) {
 		t.Error("Service should not be running after stop")
 	}
 }
+
+func TestApplyBaseline(t *testing.T) {
+	cfg := &config.Config{
+		Settings: config.Settings{CheckIntervalMinutes: 10},
+		Storage:  config.StorageConfig{Type: "file", Path: "./test_data"},
+		Logging:  config.LoggingConfig{Level: "info", Format: "text"},
+	}
+
+	service, err := NewForTest(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	stargazers := []github.Stargazer{
+		{Login: "old", ID: 1, StarredAt: time.Now().Add(-48 * time.Hour)},
+		{Login: "recent", ID: 2, StarredAt: time.Now().Add(-time.Hour)},
+	}
+
+	tests := []struct {
+		baseline string
+		expected int
+	}{
+		{config.BaselineSilent, 0},
+		{config.BaselineSummary, 1},
+		{config.BaselineAll, 2},
+		{"", 1}, // Falls back to settings.baseline, which defaults to summary
+	}
+
+	for _, test := range tests {
+		repository := config.Repository{Owner: "test", Repo: "test-repo", Baseline: test.baseline}
+		announced := service.applyBaseline(service.logger, repository, stargazers)
+		if len(announced) != test.expected {
+			t.Errorf("baseline %q: expected %d announced stargazers, got %d", test.baseline, test.expected, len(announced))
+		}
+	}
+}
+
+func TestStarsUnchanged(t *testing.T) {
+	cfg := &config.Config{
+		Settings: config.Settings{CheckIntervalMinutes: 10},
+		Storage:  config.StorageConfig{Type: "file", Path: "./test_data"},
+		Logging:  config.LoggingConfig{Level: "info", Format: "text"},
+	}
+
+	service, err := NewForTest(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	previous := &storage.RepoSummary{
+		StargazerCount: 2,
+		LastCheck:      time.Now(),
+	}
+
+	// Never reconciled since startup, the stargazers must be fetched
+	if service.starsUnchanged("test", "repo", previous, 2) {
+		t.Error("Expected a fetch before the first full sync")
+	}
+
+	service.markFullSync("test", "repo")
+	if !service.starsUnchanged("test", "repo", previous, 2) {
+		t.Error("Expected the fetch to be skipped when the star count is unchanged")
+	}
+	if service.starsUnchanged("test", "repo", previous, 3) {
+		t.Error("Expected a fetch when the star count changed")
+	}
+	if service.starsUnchanged("test", "repo", &storage.RepoSummary{}, 0) {
+		t.Error("Expected a fetch for a repository never checked")
+	}
+
+	service.lastFullSync["test/repo"] = time.Now().Add(-48 * time.Hour)
+	if service.starsUnchanged("test", "repo", previous, 2) {
+		t.Error("Expected a fetch once the reconciliation is due")
+	}
+}
+
+func TestDetectMilestones(t *testing.T) {
+	milestones := []int{100, 500, 1000}
+
+	tests := []struct {
+		name           string
+		alreadyReached []int
+		previous       int
+		stars          int
+		reached        int
+		announced      int
+	}{
+		{"below every milestone", nil, 50, 99, 0, 0},
+		{"crossed one", nil, 99, 100, 1, 100},
+		{"crossed several", []int{100}, 450, 1200, 2, 1000},
+		{"dipped and recovered", []int{100}, 99, 101, 0, 0},
+		{"reached before tracking", nil, 600, 600, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached, announced := detectMilestones(milestones, tt.alreadyReached, tt.previous, tt.stars)
+			if len(reached) != tt.reached {
+				t.Errorf("Expected %d reached milestones, got %v", tt.reached, reached)
+			}
+			if announced != tt.announced {
+				t.Errorf("Expected milestone %d to be announced, got %d", tt.announced, announced)
+			}
+		})
+	}
+}
+
+func TestFilterReleases(t *testing.T) {
+	releases := []github.Release{
+		{ID: 1, TagName: "v1.0.0"},
+		{ID: 2, TagName: "v1.1.0-rc.1", Prerelease: true},
+		{ID: 3, TagName: "v1.1.0", Draft: true},
+	}
+
+	tests := []struct {
+		name     string
+		options  config.ReleaseOptions
+		expected int
+	}{
+		{"published only", config.ReleaseOptions{}, 1},
+		{"with prereleases", config.ReleaseOptions{Prereleases: true}, 2},
+		{"with drafts", config.ReleaseOptions{Drafts: true}, 2},
+		{"everything", config.ReleaseOptions{Prereleases: true, Drafts: true}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterReleases(releases, tt.options); len(got) != tt.expected {
+				t.Errorf("Expected %d releases, got %d", tt.expected, len(got))
+			}
+		})
+	}
+}
+
+func TestApplyReleaseFilterChange(t *testing.T) {
+	cfg := &config.Config{
+		Settings: config.Settings{CheckIntervalMinutes: 10},
+		Storage:  config.StorageConfig{Type: "file", Path: "./test_data"},
+		Logging:  config.LoggingConfig{Level: "info", Format: "text"},
+	}
+
+	service, err := NewForTest(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	// Prereleases were just enabled: the old ones are baselined, a new
+	// published release is still announced
+	newReleases := []github.Release{
+		{ID: 2, TagName: "v1.1.0-rc.1", Prerelease: true},
+		{ID: 4, TagName: "v1.2.0"},
+	}
+	announced := applyReleaseFilterChange(service.logger, storage.ReleaseFilter{}, newReleases)
+	if len(announced) != 1 || announced[0].TagName != "v1.2.0" {
+		t.Errorf("Expected only v1.2.0 to be announced, got %+v", announced)
+	}
+
+	// Releases already eligible before the change are announced
+	announced = applyReleaseFilterChange(service.logger, storage.ReleaseFilter{Prereleases: true}, newReleases)
+	if len(announced) != 2 {
+		t.Errorf("Expected both releases to be announced, got %+v", announced)
+	}
+}
+
+func TestRenamedRepository(t *testing.T) {
+	repository := config.Repository{Owner: "org", Repo: "old", Instance: "corp", Track: []string{config.TrackStars}}
+
+	renamed := renamedRepository(repository, "other-org/new")
+	if renamed.Owner != "other-org" || renamed.Repo != "new" || renamed.Instance != "corp" {
+		t.Errorf("Unexpected renamed repository: %+v", renamed)
+	}
+	if storageOwner(renamed) != "corp_other-org" {
+		t.Errorf("Expected the storage key to follow the new owner, got %s", storageOwner(renamed))
+	}
+	if repository.Owner != "org" {
+		t.Error("Expected the original repository to be unchanged")
+	}
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github-stars-notify/internal/config"
+	"github-stars-notify/internal/errors"
+	"github-stars-notify/internal/github"
+	"github-stars-notify/internal/notify"
+)
+
+// maxWebhookPayload bounds the size of a webhook delivery, GitHub caps payloads at 25 MB
+const maxWebhookPayload = 25 << 20
+
+// webhookDeliveryTTL is how long delivery IDs are remembered. Redeliveries keep
+// the X-GitHub-Delivery ID of the original delivery.
+const webhookDeliveryTTL = 24 * time.Hour
+
+// deliveryCache remembers recent webhook delivery IDs to drop repeated deliveries
+type deliveryCache struct {
+	seen  map[string]time.Time
+	mutex sync.Mutex
+}
+
+// newDeliveryCache creates an empty delivery cache
+func newDeliveryCache() *deliveryCache {
+	return &deliveryCache{seen: make(map[string]time.Time)}
+}
+
+// firstSeen records a delivery ID and reports whether it had not been seen
+// within webhookDeliveryTTL, dropping expired IDs. Deliveries that fail are
+// removed with forget so their redelivery is processed.
+func (c *deliveryCache) firstSeen(id string, now time.Time) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for seenID, at := range c.seen {
+		if now.Sub(at) > webhookDeliveryTTL {
+			delete(c.seen, seenID)
+		}
+	}
+
+	if _, ok := c.seen[id]; ok {
+		return false
+	}
+	c.seen[id] = now
+	return true
+}
+
+// forget removes a delivery ID, a redelivery is then processed again
+func (c *deliveryCache) forget(id string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.seen, id)
+}
+
+// handleWebhook returns the handler receiving GitHub webhook deliveries. Star
+// events are verified and deduplicated, then processed in the background under
+// ctx so GitHub gets its answer quickly. Stop waits for them to finish.
+func (s *Service) handleWebhook(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		webhook := s.configReloader.GetConfig().Server.Webhook
+		if !webhook.Enabled {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		event := r.Header.Get("X-GitHub-Event")
+		delivery := r.Header.Get("X-GitHub-Delivery")
+		deliveryLogger := s.logger.WithContext("event", event, "delivery", delivery)
+
+		// The event header is only trusted as a metric label once the
+		// signature is verified, and then only for known events
+		metricEvent := "unverified"
+
+		payload, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookPayload+1))
+		if err != nil || len(payload) > maxWebhookPayload {
+			s.metrics.RecordWebhookDelivery(metricEvent, "invalid_payload")
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+
+		if !github.VerifyWebhookSignature(webhook.Secret, payload, r.Header.Get("X-Hub-Signature-256")) {
+			deliveryLogger.Warn("rejected webhook delivery with an invalid signature")
+			s.metrics.RecordWebhookDelivery(metricEvent, "invalid_signature")
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+		metricEvent = webhookMetricEvent(event)
+
+		if delivery != "" && !s.deliveries.firstSeen(delivery, time.Now()) {
+			deliveryLogger.Debug("ignoring repeated webhook delivery")
+			s.metrics.RecordWebhookDelivery(metricEvent, "duplicate")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		switch event {
+		case github.WebhookEventPing:
+			deliveryLogger.Info("webhook ping received")
+			s.metrics.RecordWebhookDelivery(metricEvent, "ping")
+			w.WriteHeader(http.StatusOK)
+
+		case github.WebhookEventWatch, github.WebhookEventStar:
+			starEvent, err := github.ParseStarEvent(payload)
+			if err != nil {
+				deliveryLogger.Warn("invalid star event payload", "error", err)
+				s.deliveries.forget(delivery)
+				s.metrics.RecordWebhookDelivery(metricEvent, "invalid_payload")
+				http.Error(w, "invalid payload", http.StatusBadRequest)
+				return
+			}
+
+			s.metrics.RecordWebhookDelivery(metricEvent, "accepted")
+			w.WriteHeader(http.StatusAccepted)
+
+			s.webhookTasks.Add(1)
+			go func() {
+				defer s.webhookTasks.Done()
+				if err := s.processStarEvent(s.withClients(ctx), starEvent); err != nil {
+					deliveryLogger.Error("failed to process star event", "error", err)
+					s.deliveries.forget(delivery)
+				}
+			}()
+
+		default:
+			s.metrics.RecordWebhookDelivery(metricEvent, "ignored")
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
+
+// webhookMetricEvent returns the metric label of a verified webhook event:
+// its name for the events handled, "other" for the rest
+func webhookMetricEvent(event string) string {
+	switch event {
+	case github.WebhookEventPing, github.WebhookEventWatch, github.WebhookEventStar:
+		return event
+	}
+	return "other"
+}
+
+// processStarEvent announces a star received by webhook, unless polling or an
+// earlier delivery already recorded it. Removed stars are left to the next
+// full sync, which also catches stars whose delivery was lost.
+func (s *Service) processStarEvent(ctx context.Context, event *github.StarEvent) error {
+	if !event.IsStarred() {
+		return nil
+	}
+
+	repository, ok := s.webhookRepository(ctx, event.Repository)
+	if !ok {
+		s.logger.Debug("ignoring star of an unwatched repository", "repository", event.Repository.FullName)
+		return nil
+	}
+
+	owner, repo := repository.Owner, repository.Repo
+	key := storageOwner(repository)
+	repoLogger := s.logger.WithRepository(owner, repo)
+	if repository.Instance != "" {
+		repoLogger = repoLogger.WithContext("instance", repository.Instance)
+	}
+
+	client, instance, err := s.githubClient(ctx, repository.Instance)
+	if err != nil {
+		return err
+	}
+	ctx = notify.WithWebURL(ctx, instance.WebURL)
+
+	// Polling must not diff the stored stargazers while the star is added
+	unlock := s.lockRepository(key, repo)
+	defer unlock()
+
+	previous, err := s.storage.LoadSummary(ctx, key, repo)
+	if err != nil {
+		return errors.NewServiceError("storage", "failed to load stargazers data", err)
+	}
+
+	// The first check records the baseline, a star stored before it would bypass it
+	if previous.LastCheck.IsZero() {
+		repoLogger.Debug("repository not checked yet, leaving the star to polling")
+		return nil
+	}
+
+	stargazer := event.Stargazer(time.Now())
+	added, err := s.storage.AddStargazer(ctx, key, repo, stargazer)
+	if err != nil {
+		s.metrics.RecordCheckError(key, repo, "storage_save_error")
+		return errors.NewServiceError("storage", "failed to save stargazer", err)
+	}
+	if !added {
+		repoLogger.Debug("star already recorded", "login", stargazer.Login)
+		return nil
+	}
+
+	stars := previous.StargazerCount + 1
+	repoLogger.Info("new stargazer received by webhook", "login", stargazer.Login)
+	s.metrics.RecordNewStars(key, repo, 1)
+	s.metrics.RecordRepositoryStars(key, repo, stars)
+
+	announced := s.enrichStargazers(ctx, repoLogger, client, repository.Instance, []github.Stargazer{stargazer})
+	s.sendNotifications(ctx, repoLogger, notify.NewStarsEvent(owner, repo, announced))
+
+	return s.checkMilestones(ctx, repoLogger, repository, key, previous, stars)
+}
+
+// webhookRepository finds the watched repository a webhook delivery is about,
+// telling instances apart by the web URL of the repository
+func (s *Service) webhookRepository(ctx context.Context, target github.Repository) (config.Repository, bool) {
+	cfg := s.configReloader.GetConfig()
+
+	for _, repository := range s.resolveRepositories(ctx, cfg) {
+		if !repository.Tracks(config.TrackStars) ||
+			!strings.EqualFold(repository.Owner+"/"+repository.Repo, target.FullName) {
+			continue
+		}
+
+		instance, ok := cfg.GetGitHubInstance(repository.Instance)
+		if !ok {
+			continue
+		}
+		if target.HTMLURL == "" && repository.Instance == "" ||
+			strings.HasPrefix(strings.ToLower(target.HTMLURL), strings.ToLower(instance.WebURL)+"/") {
+			return repository, true
+		}
+	}
+
+	return config.Repository{}, false
+}
+
+// lockRepository serializes star updates of a repository between polling and
+// webhook deliveries. It returns the function releasing the lock.
+func (s *Service) lockRepository(key, repo string) func() {
+	value, _ := s.repoLocks.LoadOrStore(key+"/"+repo, &sync.Mutex{})
+	mutex := value.(*sync.Mutex)
+	mutex.Lock()
+	return mutex.Unlock
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github-stars-notify/internal/config"
+)
+
+// signedDelivery creates a webhook request signed with secret
+func signedDelivery(secret, event, delivery, payload string) *http.Request {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(payload))
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-GitHub-Delivery", delivery)
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestHandleWebhook(t *testing.T) {
+	cfg := &config.Config{
+		Settings: config.Settings{CheckIntervalMinutes: 10},
+		Server: config.ServerConfig{
+			Webhook: config.WebhookConfig{Enabled: true, Secret: "s3cret"},
+		},
+		Storage: config.StorageConfig{Type: "file", Path: "./test_data"},
+		Logging: config.LoggingConfig{Level: "info", Format: "text"},
+	}
+
+	service, err := NewForTest(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	handler := service.handleWebhook(context.Background())
+
+	// A star of an unwatched repository is accepted and then ignored
+	star := `{"action":"created","repository":{"id":1,"full_name":"other/repo"},"sender":{"login":"alice","id":7}}`
+
+	tests := []struct {
+		name     string
+		req      *http.Request
+		expected int
+	}{
+		{"ping", signedDelivery("s3cret", "ping", "d-1", `{"zen":"Keep it simple."}`), http.StatusOK},
+		{"invalid signature", signedDelivery("wrong", "star", "d-2", star), http.StatusUnauthorized},
+		{"star", signedDelivery("s3cret", "star", "d-3", star), http.StatusAccepted},
+		{"redelivery", signedDelivery("s3cret", "star", "d-3", star), http.StatusOK},
+		{"invalid payload", signedDelivery("s3cret", "watch", "d-4", `{"action":"started"}`), http.StatusBadRequest},
+		{"redelivery after invalid payload", signedDelivery("s3cret", "star", "d-4", star), http.StatusAccepted},
+		{"other event", signedDelivery("s3cret", "push", "d-5", `{}`), http.StatusNoContent},
+		{"unverified event", signedDelivery("wrong", "made-up", "d-6", `{}`), http.StatusUnauthorized},
+		{"method", httptest.NewRequest(http.MethodGet, "/webhook", nil), http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		handler(recorder, tt.req)
+		if recorder.Code != tt.expected {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.expected, recorder.Code)
+		}
+	}
+	service.webhookTasks.Wait()
+
+	// Event names from the request only label verified deliveries of known events
+	deliveries := service.metrics.WebhookDeliveries
+	if got := testutil.ToFloat64(deliveries.WithLabelValues("unverified", "invalid_signature")); got != 2 {
+		t.Errorf("Expected 2 unverified deliveries, got %v", got)
+	}
+	if got := testutil.ToFloat64(deliveries.WithLabelValues("other", "ignored")); got != 1 {
+		t.Errorf("Expected the push event labelled other, got %v", got)
+	}
+	if got := testutil.CollectAndCount(deliveries); got != 6 {
+		t.Errorf("Expected 6 delivery series, got %d", got)
+	}
+}
+
+func TestDeliveryCache(t *testing.T) {
+	cache := newDeliveryCache()
+	now := time.Now()
+
+	if !cache.firstSeen("abc", now) {
+		t.Error("Expected the first delivery to be new")
+	}
+	if cache.firstSeen("abc", now.Add(time.Hour)) {
+		t.Error("Expected the redelivery to be detected")
+	}
+	if !cache.firstSeen("abc", now.Add(webhookDeliveryTTL+2*time.Hour)) {
+		t.Error("Expected the delivery to be forgotten after the TTL")
+	}
+
+	// A failed delivery is processed again when redelivered
+	cache.forget("abc")
+	if !cache.firstSeen("abc", now.Add(webhookDeliveryTTL+3*time.Hour)) {
+		t.Error("Expected a forgotten delivery to be new")
+	}
+}
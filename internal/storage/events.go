@@ -0,0 +1,263 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github-stars-notify/internal/errors"
+	"github-stars-notify/internal/github"
+)
+
+// Star event kinds of the star event log
+const (
+	StarEventStarred   = "starred"
+	StarEventUnstarred = "unstarred"
+)
+
+// StarEvent is an entry of the append-only star event log of a repository
+type StarEvent struct {
+	Owner      string           `json:"owner"`
+	Repo       string           `json:"repo"`
+	Kind       string           `json:"kind"`        // StarEventStarred or StarEventUnstarred
+	Stargazer  github.Stargazer `json:"stargazer"`   // StarredAt is the star time reported by GitHub
+	RecordedAt time.Time        `json:"recorded_at"` // When the event was detected
+}
+
+// Time returns when the event happened: the star time reported by GitHub for
+// stars, the detection time for unstars and stars without one
+func (e StarEvent) Time() time.Time {
+	if e.Kind == StarEventStarred && !e.Stargazer.StarredAt.IsZero() {
+		return e.Stargazer.StarredAt
+	}
+	return e.RecordedAt
+}
+
+// StarEventQuery selects events from the star event log of a repository.
+// Owner and Repo are required, other zero fields match every event.
+type StarEventQuery struct {
+	Owner string
+	Repo  string
+	Kind  string    // StarEventStarred or StarEventUnstarred
+	Login string    // Compared case-insensitively, like GitHub logins
+	Since time.Time // Events happening at or after Since
+	Until time.Time // Events happening before Until
+	Limit int       // Maximum number of events returned, oldest first
+}
+
+// validate checks that the query names a repository
+func (q StarEventQuery) validate() error {
+	if q.Owner == "" || q.Repo == "" {
+		return errors.NewValidationError("repository", q.Owner+"/"+q.Repo,
+			"star event queries require owner and repo", nil)
+	}
+	return nil
+}
+
+// Matches reports whether an event of the queried repository is selected by the query
+func (q StarEventQuery) Matches(event StarEvent) bool {
+	if q.Kind != "" && event.Kind != q.Kind {
+		return false
+	}
+	if q.Login != "" && !strings.EqualFold(event.Stargazer.Login, q.Login) {
+		return false
+	}
+	at := event.Time()
+	if !q.Since.IsZero() && at.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !at.Before(q.Until) {
+		return false
+	}
+	return true
+}
+
+// SnapshotEvents converts the stored snapshot of a repository into an initial
+// event log: a star for every current stargazer, detected at the oldest check
+// that saw it, and an unstar for every recorded removal. Removed stargazers
+// with a known star time also get their star. Events are ordered by Time.
+func SnapshotEvents(repoData *RepoData) []StarEvent {
+	previous := map[int64]bool{}
+	var previousCheck time.Time
+	if repoData.PreviousData != nil {
+		previous = repoData.PreviousData.StargazerIDs()
+		previousCheck = repoData.PreviousData.LastCheck
+	}
+	current := repoData.StargazerIDs()
+
+	newEvent := func(kind string, stargazer github.Stargazer, recordedAt time.Time) StarEvent {
+		return StarEvent{Owner: repoData.Owner, Repo: repoData.Repo, Kind: kind, Stargazer: stargazer, RecordedAt: recordedAt}
+	}
+
+	var events []StarEvent
+	for _, stargazer := range repoData.Stargazers {
+		recordedAt := repoData.LastCheck
+		if previous[stargazer.ID] && !previousCheck.IsZero() {
+			recordedAt = previousCheck
+		}
+		events = append(events, newEvent(StarEventStarred, stargazer, recordedAt))
+	}
+	for _, removed := range repoData.RemovedStargazers {
+		if !current[removed.ID] && !removed.StarredAt.IsZero() {
+			events = append(events, newEvent(StarEventStarred, removed.Stargazer, removed.StarredAt))
+		}
+		events = append(events, newEvent(StarEventUnstarred, removed.Stargazer, removed.RemovedAt))
+	}
+
+	sortStarEvents(events)
+	return events
+}
+
+// sortStarEvents orders events by Time, keeping the log order of events happening together
+func sortStarEvents(events []StarEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time().Before(events[j].Time())
+	})
+}
+
+// StarEvents returns the events of the star event log of a repository matching
+// query, oldest first. The snapshot of a repository stored before the event
+// log existed is converted into its initial events.
+func (s *FileStorage) StarEvents(ctx context.Context, query StarEventQuery) ([]StarEvent, error) {
+	if err := query.validate(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	events, err := s.loadStarEventsUnsafe(query.Owner, query.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []StarEvent
+	for _, event := range events {
+		if query.Matches(event) {
+			matching = append(matching, event)
+		}
+	}
+	sortStarEvents(matching)
+	if query.Limit > 0 && len(matching) > query.Limit {
+		matching = matching[:query.Limit]
+	}
+	return matching, nil
+}
+
+// getEventsFilename generates the filename of a repository's star event log,
+// one JSON event per line
+func (s *FileStorage) getEventsFilename(owner, repo string) string {
+	return filepath.Join(s.dataDir, "events", fmt.Sprintf("%s_%s.jsonl", owner, repo))
+}
+
+// loadStarEventsUnsafe loads the star event log of a repository without
+// acquiring a lock (for internal use), converting the snapshot if it has no log yet
+func (s *FileStorage) loadStarEventsUnsafe(owner, repo string) ([]StarEvent, error) {
+	filename := s.getEventsFilename(owner, repo)
+
+	file, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		repoData, err := s.loadUnsafe(owner, repo)
+		if err != nil {
+			return nil, err
+		}
+		return SnapshotEvents(repoData), nil
+	}
+	if err != nil {
+		return nil, errors.NewStorageError("load_events", filename,
+			"failed to open event log", err)
+	}
+	defer file.Close()
+
+	var events []StarEvent
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var event StarEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, errors.NewStorageError("load_events", filename,
+				"failed to unmarshal event", err)
+		}
+		// The log moves along when a repository is renamed
+		event.Owner, event.Repo = owner, repo
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.NewStorageError("load_events", filename,
+			"failed to read event log", err)
+	}
+	return events, nil
+}
+
+// appendStarEventsUnsafe appends star events of a kind to the event log of a
+// repository without acquiring a lock (for internal use). It is called before
+// the change is stored, so the stored snapshot becomes the start of the log if
+// there is none yet.
+func (s *FileStorage) appendStarEventsUnsafe(owner, repo, kind string, stargazers []github.Stargazer, recordedAt time.Time) error {
+	filename := s.getEventsFilename(owner, repo)
+
+	var events []StarEvent
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		previous, err := s.loadUnsafe(owner, repo)
+		if err != nil {
+			return err
+		}
+		events = SnapshotEvents(previous)
+	}
+	for _, stargazer := range stargazers {
+		events = append(events, StarEvent{
+			Owner:      owner,
+			Repo:       repo,
+			Kind:       kind,
+			Stargazer:  stargazer,
+			RecordedAt: recordedAt,
+		})
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return errors.NewStorageError("record_star_event", filename,
+				"failed to marshal event", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return errors.NewStorageError("record_star_event", filepath.Dir(filename),
+			"failed to create data directory", err)
+	}
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.NewStorageError("record_star_event", filename,
+			"failed to open event log", err)
+	}
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		file.Close()
+		return errors.NewStorageError("record_star_event", filename,
+			"failed to append to event log", err)
+	}
+	if err := file.Close(); err != nil {
+		return errors.NewStorageError("record_star_event", filename,
+			"failed to close event log", err)
+	}
+	return nil
+}
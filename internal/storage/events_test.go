@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github-stars-notify/internal/github"
+)
+
+// testStarEventLog checks that a storage logs stars and unstars and answers
+// queries by time range and login
+func testStarEventLog(t *testing.T, storage Storage) {
+	ctx := context.Background()
+
+	march := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	june := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	alice := github.Stargazer{Login: "alice", ID: 1, StarredAt: time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)}
+	bob := github.Stargazer{Login: "bob", ID: 2, StarredAt: time.Date(2024, time.April, 5, 0, 0, 0, 0, time.UTC)}
+	carol := github.Stargazer{Login: "carol", ID: 3}
+	dave := github.Stargazer{Login: "dave", ID: 4, StarredAt: time.Date(2024, time.May, 20, 0, 0, 0, 0, time.UTC)}
+
+	if err := storage.Save(ctx, "org", "repo", []github.Stargazer{alice, bob, carol}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := storage.Save(ctx, "org", "repo", []github.Stargazer{alice, carol, dave}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := storage.RecordRemovedStargazers(ctx, "org", "repo", []github.Stargazer{bob}); err != nil {
+		t.Fatalf("RecordRemovedStargazers failed: %v", err)
+	}
+
+	// Stars without a GitHub star time happened when they were detected
+	events, err := storage.StarEvents(ctx, StarEventQuery{Owner: "org", Repo: "repo", Kind: StarEventStarred, Since: march, Until: june})
+	if err != nil {
+		t.Fatalf("StarEvents failed: %v", err)
+	}
+	if logins := eventLogins(events); logins != "alice,bob,dave" {
+		t.Errorf("Expected alice, bob and dave to star between March and May, got %q", logins)
+	}
+
+	events, err = storage.StarEvents(ctx, StarEventQuery{Owner: "org", Repo: "repo", Login: "BOB"})
+	if err != nil {
+		t.Fatalf("StarEvents failed: %v", err)
+	}
+	if len(events) != 2 || events[0].Kind != StarEventStarred || events[1].Kind != StarEventUnstarred ||
+		events[1].RecordedAt.IsZero() || events[1].Owner != "org" || events[1].Repo != "repo" {
+		t.Errorf("Expected bob to star and unstar, got %+v", events)
+	}
+
+	events, err = storage.StarEvents(ctx, StarEventQuery{Owner: "org", Repo: "repo", Limit: 1})
+	if err != nil || eventLogins(events) != "alice" {
+		t.Errorf("Expected the oldest event only, got %+v (%v)", events, err)
+	}
+
+	if _, err := storage.StarEvents(ctx, StarEventQuery{Login: "bob"}); err == nil {
+		t.Error("Expected an error for a query without repository")
+	}
+
+	// The log moves along with a renamed repository
+	if err := storage.RenameRepository(ctx, "org", "repo", "org", "renamed"); err != nil {
+		t.Fatalf("RenameRepository failed: %v", err)
+	}
+	events, err = storage.StarEvents(ctx, StarEventQuery{Owner: "org", Repo: "renamed"})
+	if err != nil || len(events) != 5 || events[0].Repo != "renamed" {
+		t.Errorf("Expected the events under the new name, got %+v (%v)", events, err)
+	}
+}
+
+// eventLogins joins the logins of events
+func eventLogins(events []StarEvent) string {
+	logins := ""
+	for i, event := range events {
+		if i > 0 {
+			logins += ","
+		}
+		logins += event.Stargazer.Login
+	}
+	return logins
+}
+
+func TestFileStarEvents(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	if err := storage.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	testStarEventLog(t, storage)
+}
+
+func TestSnapshotEvents(t *testing.T) {
+	firstCheck := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	lastCheck := firstCheck.Add(24 * time.Hour)
+	removedAt := lastCheck.Add(-time.Hour)
+	starredAt := firstCheck.Add(-time.Hour)
+
+	events := SnapshotEvents(&RepoData{
+		Owner:      "org",
+		Repo:       "repo",
+		LastCheck:  lastCheck,
+		Stargazers: []github.Stargazer{{Login: "old", ID: 1}, {Login: "new", ID: 2}},
+		RemovedStargazers: []RemovedStargazer{
+			{Stargazer: github.Stargazer{Login: "gone", ID: 3, StarredAt: starredAt}, RemovedAt: removedAt},
+		},
+		PreviousData: &RepoData{
+			LastCheck:  firstCheck,
+			Stargazers: []github.Stargazer{{Login: "old", ID: 1}},
+		},
+	})
+
+	expected := []struct {
+		login string
+		kind  string
+		at    time.Time
+	}{
+		{"gone", StarEventStarred, starredAt},
+		{"old", StarEventStarred, firstCheck},
+		{"gone", StarEventUnstarred, removedAt},
+		{"new", StarEventStarred, lastCheck},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %+v", len(expected), events)
+	}
+	for i, want := range expected {
+		event := events[i]
+		if event.Stargazer.Login != want.login || event.Kind != want.kind || !event.Time().Equal(want.at) || event.Owner != "org" {
+			t.Errorf("Event %d: expected %s %s at %v, got %+v", i, want.login, want.kind, want.at, event)
+		}
+	}
+}
+
+func TestFileStarEventsFromSnapshot(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	ctx := context.Background()
+	if err := storage.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	// A snapshot stored before the event log existed
+	if err := storage.Save(ctx, "org", "repo", []github.Stargazer{{Login: "a", ID: 1}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := os.RemoveAll(storage.getEventsFilename("org", "repo")); err != nil {
+		t.Fatalf("Failed to remove event log: %v", err)
+	}
+
+	events, err := storage.StarEvents(ctx, StarEventQuery{Owner: "org", Repo: "repo"})
+	if err != nil || eventLogins(events) != "a" {
+		t.Errorf("Expected the snapshot as initial events, got %+v (%v)", events, err)
+	}
+
+	// The first new event writes the converted snapshot ahead of it
+	if added, err := storage.AddStargazer(ctx, "org", "repo", github.Stargazer{Login: "b", ID: 2}); err != nil || !added {
+		t.Fatalf("AddStargazer failed: %v", err)
+	}
+	events, err = storage.StarEvents(ctx, StarEventQuery{Owner: "org", Repo: "repo"})
+	if err != nil || eventLogins(events) != "a,b" {
+		t.Errorf("Expected the snapshot followed by b, got %+v (%v)", events, err)
+	}
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github-stars-notify/internal/errors"
+	"github-stars-notify/internal/github"
+)
+
+// FollowerData represents the stored followers of a user or organization
+type FollowerData struct {
+	Login     string             `json:"login"`
+	LastCheck time.Time          `json:"last_check"`
+	Followers []github.Stargazer `json:"followers"`
+}
+
+// LoadFollowers loads the stored followers of a user or organization
+func (s *FileStorage) LoadFollowers(ctx context.Context, login string) (*FollowerData, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return s.loadFollowersUnsafe(login)
+}
+
+// SaveFollowers saves the followers of a user or organization
+func (s *FileStorage) SaveFollowers(ctx context.Context, login string, followers []github.Stargazer) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return writeJSONFile("save_followers", s.getFollowersFilename(login), &FollowerData{
+		Login:     login,
+		LastCheck: time.Now(),
+		Followers: followers,
+	})
+}
+
+// DiffFollowers compares current followers with previous data and returns added and removed ones
+func (s *FileStorage) DiffFollowers(ctx context.Context, login string, currentFollowers []github.Stargazer) (*StargazerDiff, error) {
+	followerData, err := s.LoadFollowers(ctx, login)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load follower data: %w", err)
+	}
+
+	return diffStargazers(followerData.Followers, currentFollowers), nil
+}
+
+// getFollowersFilename generates the filename for the followers of an account
+func (s *FileStorage) getFollowersFilename(login string) string {
+	return filepath.Join(s.dataDir, "followers", login+".json")
+}
+
+// loadFollowersUnsafe loads follower data without acquiring a lock (for internal use)
+func (s *FileStorage) loadFollowersUnsafe(login string) (*FollowerData, error) {
+	filename := s.getFollowersFilename(login)
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		// Return empty data if file doesn't exist
+		return &FollowerData{
+			Login:     login,
+			Followers: []github.Stargazer{},
+		}, nil
+	}
+	if err != nil {
+		return nil, errors.NewStorageError("load_followers", filename,
+			"failed to read data file", err)
+	}
+
+	var followerData FollowerData
+	if err := json.Unmarshal(data, &followerData); err != nil {
+		return nil, errors.NewStorageError("load_followers", filename,
+			"failed to unmarshal data", err)
+	}
+
+	return &followerData, nil
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github-stars-notify/internal/errors"
+	"github-stars-notify/internal/github"
+)
+
+// ForkData represents the stored forks of a repository
+type ForkData struct {
+	Owner     string              `json:"owner"`
+	Repo      string              `json:"repo"`
+	LastCheck time.Time           `json:"last_check"`
+	Forks     []github.Repository `json:"forks"`
+}
+
+// LoadForks loads the stored forks of a repository
+func (s *FileStorage) LoadForks(ctx context.Context, owner, repo string) (*ForkData, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return s.loadForksUnsafe(owner, repo)
+}
+
+// SaveForks saves the forks of a repository
+func (s *FileStorage) SaveForks(ctx context.Context, owner, repo string, forks []github.Repository) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	filename := s.getForksFilename(owner, repo)
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return writeJSONFile("save_forks", filename, &ForkData{
+		Owner:     owner,
+		Repo:      repo,
+		LastCheck: time.Now(),
+		Forks:     forks,
+	})
+}
+
+// GetNewForks compares current forks with previous data and returns new ones
+func (s *FileStorage) GetNewForks(ctx context.Context, owner, repo string, currentForks []github.Repository) ([]github.Repository, error) {
+	forkData, err := s.LoadForks(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fork data: %w", err)
+	}
+
+	added, _ := diffByID(forkData.Forks, currentForks, func(fork github.Repository) int64 { return fork.ID })
+	return added, nil
+}
+
+// getForksFilename generates the filename for a repository's forks. Forks live in
+// their own directory so they cannot collide with stargazer data files.
+func (s *FileStorage) getForksFilename(owner, repo string) string {
+	return filepath.Join(s.dataDir, "forks", fmt.Sprintf("%s_%s.json", owner, repo))
+}
+
+// loadForksUnsafe loads fork data without acquiring a lock (for internal use)
+func (s *FileStorage) loadForksUnsafe(owner, repo string) (*ForkData, error) {
+	filename := s.getForksFilename(owner, repo)
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		// Return empty data if file doesn't exist
+		return &ForkData{
+			Owner: owner,
+			Repo:  repo,
+			Forks: []github.Repository{},
+		}, nil
+	}
+	if err != nil {
+		return nil, errors.NewStorageError("load_forks", filename,
+			"failed to read data file", err)
+	}
+
+	var forkData ForkData
+	if err := json.Unmarshal(data, &forkData); err != nil {
+		return nil, errors.NewStorageError("load_forks", filename,
+			"failed to unmarshal data", err)
+	}
+
+	return &forkData, nil
+}
@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github-stars-notify/internal/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// postgresMigrationLock is the advisory lock key serializing migrations of
+// instances starting at the same time
+const postgresMigrationLock = 0x73746172
+
+// postgresMigrations create and evolve the tables of the PostgreSQL storage.
+// Migration n brings the schema to version n+1. Released migrations are never
+// edited, schema changes are appended as new migrations. Times are stored as
+// Unix nanoseconds like in the SQLite storage, NULL for the zero time.
+var postgresMigrations = []string{
+	// 1: initial schema
+	`
+CREATE TABLE repositories (
+	id                  BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	owner               TEXT NOT NULL,
+	repo                TEXT NOT NULL,
+	last_check          BIGINT,
+	forks_checked_at    BIGINT,
+	releases_checked_at BIGINT,
+	github_id           BIGINT NOT NULL DEFAULT 0,
+	renamed_to          TEXT NOT NULL DEFAULT '',
+	not_found_count     INTEGER NOT NULL DEFAULT 0,
+	suspended_at        BIGINT,
+	UNIQUE (owner, repo)
+);
+
+CREATE TABLE stargazers (
+	repository_id BIGINT NOT NULL REFERENCES repositories (id) ON DELETE CASCADE,
+	user_id       BIGINT NOT NULL,
+	position      INTEGER NOT NULL,
+	login         TEXT NOT NULL,
+	node_id       TEXT NOT NULL DEFAULT '',
+	avatar_url    TEXT NOT NULL DEFAULT '',
+	html_url      TEXT NOT NULL DEFAULT '',
+	starred_at    BIGINT,
+	PRIMARY KEY (repository_id, user_id)
+);
+
+CREATE TABLE star_events (
+	id            BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	repository_id BIGINT NOT NULL REFERENCES repositories (id) ON DELETE CASCADE,
+	kind          TEXT NOT NULL,
+	user_id       BIGINT NOT NULL,
+	login         TEXT NOT NULL,
+	node_id       TEXT NOT NULL DEFAULT '',
+	avatar_url    TEXT NOT NULL DEFAULT '',
+	html_url      TEXT NOT NULL DEFAULT '',
+	starred_at    BIGINT,
+	recorded_at   BIGINT NOT NULL
+);
+
+CREATE INDEX star_events_repository ON star_events (repository_id, kind, recorded_at);
+
+CREATE TABLE milestones (
+	repository_id BIGINT NOT NULL REFERENCES repositories (id) ON DELETE CASCADE,
+	stars         INTEGER NOT NULL,
+	PRIMARY KEY (repository_id, stars)
+);
+
+CREATE TABLE forks (
+	repository_id BIGINT NOT NULL REFERENCES repositories (id) ON DELETE CASCADE,
+	fork_id       BIGINT NOT NULL,
+	data          JSONB NOT NULL,
+	PRIMARY KEY (repository_id, fork_id)
+);
+
+CREATE TABLE releases (
+	repository_id BIGINT NOT NULL REFERENCES repositories (id) ON DELETE CASCADE,
+	release_id    BIGINT NOT NULL,
+	PRIMARY KEY (repository_id, release_id)
+);
+
+CREATE TABLE accounts (
+	id         BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+	login      TEXT NOT NULL UNIQUE,
+	last_check BIGINT
+);
+
+CREATE TABLE followers (
+	account_id BIGINT NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+	user_id    BIGINT NOT NULL,
+	position   INTEGER NOT NULL,
+	login      TEXT NOT NULL,
+	node_id    TEXT NOT NULL DEFAULT '',
+	avatar_url TEXT NOT NULL DEFAULT '',
+	html_url   TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (account_id, user_id)
+);
+
+CREATE TABLE metadata (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+`,
+	// 2: star event log, queried by login and starting with the stored stargazers
+	`
+CREATE INDEX star_events_login ON star_events (repository_id, lower(login));
+
+INSERT INTO star_events (repository_id, kind, user_id, login, node_id, avatar_url, html_url, starred_at, recorded_at)
+SELECT s.repository_id, 'starred', s.user_id, s.login, s.node_id, s.avatar_url, s.html_url, s.starred_at,
+	COALESCE(r.last_check, s.starred_at, 0)
+FROM stargazers s JOIN repositories r ON r.id = s.repository_id
+WHERE NOT EXISTS (
+	SELECT 1 FROM star_events e
+	WHERE e.repository_id = s.repository_id AND e.user_id = s.user_id AND e.kind = 'starred'
+)
+ORDER BY s.repository_id, s.position;
+`,
+	// 3: user profiles cached for enrichment
+	`
+CREATE TABLE user_profiles (
+	key        TEXT PRIMARY KEY,
+	profile    TEXT NOT NULL,
+	fetched_at BIGINT NOT NULL
+);
+`,
+	// 4: release filters, to spot releases made eligible by a filter change
+	`
+CREATE TABLE release_filters (
+	repository_id BIGINT PRIMARY KEY REFERENCES repositories (id) ON DELETE CASCADE,
+	prereleases   BOOLEAN NOT NULL,
+	drafts        BOOLEAN NOT NULL
+);
+`,
+	// 5: GitHub responses cached for conditional requests
+	`
+CREATE TABLE http_responses (
+	key           TEXT PRIMARY KEY,
+	etag          TEXT NOT NULL DEFAULT '',
+	last_modified TEXT NOT NULL DEFAULT '',
+	link          TEXT NOT NULL DEFAULT '',
+	body          BYTEA NOT NULL,
+	used_at       BIGINT NOT NULL
+);
+
+CREATE INDEX http_responses_used_at ON http_responses (used_at);
+`,
+}
+
+// PostgresConfig configures the PostgreSQL storage and its connection pool.
+// Zero pool settings keep the defaults of database/sql.
+type PostgresConfig struct {
+	DSN             string        // Connection string, URL or key=value form
+	MaxOpenConns    int           // Maximum number of open connections
+	MaxIdleConns    int           // Maximum number of idle connections kept open
+	ConnMaxLifetime time.Duration // Connections are closed after this age
+}
+
+// PostgresStorage implements Storage interface using a PostgreSQL database,
+// which several instances can share
+type PostgresStorage struct {
+	sqlStorage
+	config PostgresConfig
+}
+
+// NewPostgresStorage creates a new PostgreSQL storage instance. The database
+// is connected to by Initialize.
+func NewPostgresStorage(cfg PostgresConfig) *PostgresStorage {
+	return &PostgresStorage{
+		// Batches save round trips, well below the parameter limit of a statement
+		sqlStorage: sqlStorage{path: "postgres", batchRows: 500, lockRow: " FOR UPDATE", cachedResponses: MaxCachedResponses},
+		config:     cfg,
+	}
+}
+
+// Initialize connects to the database and applies pending schema migrations
+func (s *PostgresStorage) Initialize(ctx context.Context) error {
+	connConfig, err := pgx.ParseConfig(s.config.DSN)
+	if err != nil {
+		return errors.NewStorageError("initialize", s.path,
+			"failed to parse connection string", err)
+	}
+	// Errors name the server and database, never the password
+	s.path = fmt.Sprintf("postgres://%s:%d/%s", connConfig.Host, connConfig.Port, connConfig.Database)
+
+	db := stdlib.OpenDB(*connConfig)
+	if s.config.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(s.config.MaxOpenConns)
+	}
+	if s.config.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(s.config.MaxIdleConns)
+	}
+	if s.config.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(s.config.ConnMaxLifetime)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return errors.NewStorageError("initialize", s.path,
+			"failed to connect to database", err)
+	}
+	s.db = db
+
+	if err := s.migrate(ctx); err != nil {
+		db.Close()
+		s.db = nil
+		return err
+	}
+	return nil
+}
+
+// migrate applies the migrations newer than the schema version of the
+// database, all in one transaction
+func (s *PostgresStorage) migrate(ctx context.Context) error {
+	return s.withTx(ctx, "migrate", func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, postgresMigrationLock); err != nil {
+			return errors.NewStorageError("migrate", s.path, "failed to lock schema", err)
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS schema_migrations (
+				version    INTEGER PRIMARY KEY,
+				applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+			)`)
+		if err != nil {
+			return errors.NewStorageError("migrate", s.path, "failed to create migrations table", err)
+		}
+
+		var version int
+		if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+			return errors.NewStorageError("migrate", s.path, "failed to load schema version", err)
+		}
+		if version > len(postgresMigrations) {
+			return errors.NewStorageError("migrate", s.path,
+				fmt.Sprintf("schema version %d is newer than the supported version %d", version, len(postgresMigrations)), nil)
+		}
+
+		for ; version < len(postgresMigrations); version++ {
+			if _, err := tx.ExecContext(ctx, postgresMigrations[version]); err != nil {
+				return errors.NewStorageError("migrate", s.path,
+					fmt.Sprintf("failed to apply migration %d", version+1), err)
+			}
+			if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version+1); err != nil {
+				return errors.NewStorageError("migrate", s.path, "failed to record migration", err)
+			}
+		}
+		return nil
+	})
+}
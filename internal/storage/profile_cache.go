@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github-stars-notify/internal/github"
+)
+
+// profileCacheEntry represents a cached profile and when it was fetched
+type profileCacheEntry struct {
+	Profile   *github.UserProfile `json:"profile"`
+	FetchedAt time.Time           `json:"fetched_at"`
+}
+
+// GetUserProfile returns the cached profile of a user if it was fetched within
+// ttl. The key identifies the user, typically its login, and is case-insensitive.
+func (s *FileStorage) GetUserProfile(ctx context.Context, key string, ttl time.Duration) (*github.UserProfile, bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return nil, false, ctx.Err()
+	}
+
+	entry, ok := s.loadUserProfilesUnsafe()[strings.ToLower(key)]
+	if !ok || time.Since(entry.FetchedAt) > ttl {
+		return nil, false, nil
+	}
+	return entry.Profile, true, nil
+}
+
+// SaveUserProfile caches the profile of a user, dropping the profiles fetched
+// more than ttl ago
+func (s *FileStorage) SaveUserProfile(ctx context.Context, key string, profile *github.UserProfile, ttl time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	entries := s.loadUserProfilesUnsafe()
+	now := time.Now()
+	entries[strings.ToLower(key)] = profileCacheEntry{
+		Profile:   profile,
+		FetchedAt: now,
+	}
+
+	for cachedKey, entry := range entries {
+		if now.Sub(entry.FetchedAt) > ttl {
+			delete(entries, cachedKey)
+		}
+	}
+
+	return writeJSONFile("profile_cache", s.getUserProfilesFilename(), entries)
+}
+
+// getUserProfilesFilename returns the file caching user profiles
+func (s *FileStorage) getUserProfilesFilename() string {
+	return filepath.Join(s.dataDir, "user_profiles.json")
+}
+
+// loadUserProfilesUnsafe loads the cached profiles without acquiring a lock
+// (for internal use). A missing or corrupt cache only costs extra lookups.
+func (s *FileStorage) loadUserProfilesUnsafe() map[string]profileCacheEntry {
+	entries := make(map[string]profileCacheEntry)
+
+	data, err := os.ReadFile(s.getUserProfilesFilename())
+	if err != nil {
+		return entries
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return make(map[string]profileCacheEntry)
+	}
+	return entries
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github-stars-notify/internal/errors"
+	"github-stars-notify/internal/github"
+)
+
+// ReleaseData represents the releases already seen for a repository
+type ReleaseData struct {
+	Owner      string         `json:"owner"`
+	Repo       string         `json:"repo"`
+	LastCheck  time.Time      `json:"last_check"`
+	ReleaseIDs []int64        `json:"release_ids"`
+	Filter     *ReleaseFilter `json:"filter,omitempty"` // Filter of the last save, nil if saved before filters were recorded
+}
+
+// ReleaseFilter records which kinds of releases were eligible when releases
+// were marked as seen, so releases made eligible by a later change are known
+type ReleaseFilter struct {
+	Prereleases bool `json:"prereleases"`
+	Drafts      bool `json:"drafts"`
+}
+
+// LoadReleases loads the release IDs already seen for a repository
+func (s *FileStorage) LoadReleases(ctx context.Context, owner, repo string) (*ReleaseData, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return s.loadReleasesUnsafe(owner, repo)
+}
+
+// SaveReleases marks releases of a repository as seen under the given filter.
+// Previously seen IDs are kept, so a release dropping out of the most recent
+// page is never announced again.
+func (s *FileStorage) SaveReleases(ctx context.Context, owner, repo string, releases []github.Release, filter ReleaseFilter) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	filename := s.getReleasesFilename(owner, repo)
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	releaseData, err := s.loadReleasesUnsafe(owner, repo)
+	if err != nil {
+		return errors.NewStorageError("save_releases", filename,
+			"failed to load existing data", err)
+	}
+
+	seen := releaseData.releaseIDs()
+	for _, release := range releases {
+		if !seen[release.ID] {
+			seen[release.ID] = true
+			releaseData.ReleaseIDs = append(releaseData.ReleaseIDs, release.ID)
+		}
+	}
+	releaseData.LastCheck = time.Now()
+	releaseData.Filter = &filter
+
+	return writeJSONFile("save_releases", filename, releaseData)
+}
+
+// GetNewReleases returns the releases that have not been seen before
+func (s *FileStorage) GetNewReleases(ctx context.Context, owner, repo string, currentReleases []github.Release) ([]github.Release, error) {
+	releaseData, err := s.LoadReleases(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load release data: %w", err)
+	}
+
+	seen := releaseData.releaseIDs()
+	var newReleases []github.Release
+	for _, release := range currentReleases {
+		if !seen[release.ID] {
+			newReleases = append(newReleases, release)
+		}
+	}
+	return newReleases, nil
+}
+
+// releaseIDs returns the set of seen release IDs for fast lookup
+func (d *ReleaseData) releaseIDs() map[int64]bool {
+	ids := make(map[int64]bool, len(d.ReleaseIDs))
+	for _, id := range d.ReleaseIDs {
+		ids[id] = true
+	}
+	return ids
+}
+
+// getReleasesFilename generates the filename for a repository's releases
+func (s *FileStorage) getReleasesFilename(owner, repo string) string {
+	return filepath.Join(s.dataDir, "releases", fmt.Sprintf("%s_%s.json", owner, repo))
+}
+
+// loadReleasesUnsafe loads release data without acquiring a lock (for internal use)
+func (s *FileStorage) loadReleasesUnsafe(owner, repo string) (*ReleaseData, error) {
+	filename := s.getReleasesFilename(owner, repo)
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		// Return empty data if file doesn't exist
+		return &ReleaseData{
+			Owner:      owner,
+			Repo:       repo,
+			ReleaseIDs: []int64{},
+		}, nil
+	}
+	if err != nil {
+		return nil, errors.NewStorageError("load_releases", filename,
+			"failed to read data file", err)
+	}
+
+	var releaseData ReleaseData
+	if err := json.Unmarshal(data, &releaseData); err != nil {
+		return nil, errors.NewStorageError("load_releases", filename,
+			"failed to unmarshal data", err)
+	}
+
+	return &releaseData, nil
+}
@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github-stars-notify/internal/errors"
+)
+
+// RepositoryState tracks the identity and availability of a watched repository
+type RepositoryState struct {
+	ID            int64     `json:"id,omitempty"`              // Numeric GitHub ID, stable across renames
+	RenamedTo     string    `json:"renamed_to,omitempty"`      // "owner/repo" the repository was renamed or transferred to
+	NotFoundCount int       `json:"not_found_count,omitempty"` // Consecutive checks answered with 404
+	SuspendedAt   time.Time `json:"suspended_at,omitempty"`    // When checks were suspended, zero if active
+}
+
+// IsSuspended reports whether checks of the repository are suspended
+func (r *RepositoryState) IsSuspended() bool {
+	return !r.SuspendedAt.IsZero()
+}
+
+// GetRepositoryState returns the stored state of a repository, or an empty
+// state if none was recorded yet
+func (s *FileStorage) GetRepositoryState(ctx context.Context, owner, repo string) (*RepositoryState, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	states, err := s.loadRepositoryStatesUnsafe()
+	if err != nil {
+		return nil, err
+	}
+
+	state := states[repositoryKey(owner, repo)]
+	return &state, nil
+}
+
+// SetRepositoryState stores the state of a repository
+func (s *FileStorage) SetRepositoryState(ctx context.Context, owner, repo string, state *RepositoryState) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	states, err := s.loadRepositoryStatesUnsafe()
+	if err != nil {
+		return err
+	}
+
+	states[repositoryKey(owner, repo)] = *state
+	return writeJSONFile("set_repository_state", s.getRepositoryStatesFilename(), states)
+}
+
+// RenameRepository moves the stored data of a repository to its new name and
+// records the rename, so later checks under the old name can follow it. The
+// files move together or not at all: if the new name already has data files,
+// they are kept and the files of the old name are left in place.
+func (s *FileStorage) RenameRepository(ctx context.Context, owner, repo, newOwner, newRepo string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	moves := [][2]string{
+		{s.getFilename(owner, repo), s.getFilename(newOwner, newRepo)},
+		{s.getForksFilename(owner, repo), s.getForksFilename(newOwner, newRepo)},
+		{s.getReleasesFilename(owner, repo), s.getReleasesFilename(newOwner, newRepo)},
+		{s.getEventsFilename(owner, repo), s.getEventsFilename(newOwner, newRepo)},
+		{s.getStargazerIDsFilename(owner, repo), s.getStargazerIDsFilename(newOwner, newRepo)},
+		{s.getStargazerRecordsFilename(owner, repo), s.getStargazerRecordsFilename(newOwner, newRepo)},
+	}
+	if err := moveFiles(moves); err != nil {
+		return err
+	}
+
+	states, err := s.loadRepositoryStatesUnsafe()
+	if err != nil {
+		return err
+	}
+
+	oldKey, newKey := repositoryKey(owner, repo), repositoryKey(newOwner, newRepo)
+	state := states[oldKey]
+	state.RenamedTo = ""
+	states[newKey] = state
+	states[oldKey] = RepositoryState{ID: state.ID, RenamedTo: newOwner + "/" + newRepo}
+
+	return writeJSONFile("rename_repository", s.getRepositoryStatesFilename(), states)
+}
+
+// moveFiles renames each existing source file of moves to its target, none of
+// them if a target already exists. Files already renamed are moved back when a
+// rename fails, so data files never end up split across two names.
+func moveFiles(moves [][2]string) error {
+	var pending [][2]string
+	for _, move := range moves {
+		if _, err := os.Stat(move[1]); err == nil {
+			return nil
+		}
+		if _, err := os.Stat(move[0]); err == nil {
+			pending = append(pending, move)
+		}
+	}
+
+	for i, move := range pending {
+		if err := os.MkdirAll(filepath.Dir(move[1]), 0755); err != nil {
+			restoreFiles(pending[:i])
+			return errors.NewStorageError("rename_repository", filepath.Dir(move[1]),
+				"failed to create data directory", err)
+		}
+		if err := os.Rename(move[0], move[1]); err != nil {
+			restoreFiles(pending[:i])
+			return errors.NewStorageError("rename_repository", move[0],
+				"failed to move data file", err)
+		}
+	}
+	return nil
+}
+
+// restoreFiles moves renamed files back to their source, in reverse order
+func restoreFiles(moves [][2]string) {
+	for i := len(moves) - 1; i >= 0; i-- {
+		os.Rename(moves[i][1], moves[i][0])
+	}
+}
+
+// repositoryKey identifies a repository in the state file
+func repositoryKey(owner, repo string) string {
+	return owner + "/" + repo
+}
+
+// getRepositoryStatesFilename returns the filename of the repository state file
+func (s *FileStorage) getRepositoryStatesFilename() string {
+	return filepath.Join(s.dataDir, "repositories.json")
+}
+
+// loadRepositoryStatesUnsafe loads all repository states without acquiring a lock (for internal use)
+func (s *FileStorage) loadRepositoryStatesUnsafe() (map[string]RepositoryState, error) {
+	filename := s.getRepositoryStatesFilename()
+
+	states := make(map[string]RepositoryState)
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, errors.NewStorageError("load_repository_state", filename,
+			"failed to read data file", err)
+	}
+
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, errors.NewStorageError("load_repository_state", filename,
+			"failed to unmarshal data", err)
+	}
+
+	return states, nil
+}
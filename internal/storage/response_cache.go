@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github-stars-notify/internal/errors"
+	"github-stars-notify/internal/github"
+)
+
+// MaxCachedResponses is the number of GitHub responses a storage keeps for
+// conditional requests. The least recently used ones are dropped beyond it.
+const MaxCachedResponses = 5000
+
+// ResponseCache implements github.ResponseCache on a storage backend so that
+// ETags and cached bodies survive restarts
+type ResponseCache struct {
+	storage Storage
+}
+
+// NewResponseCache creates a response cache kept in storage
+func NewResponseCache(storage Storage) *ResponseCache {
+	return &ResponseCache{storage: storage}
+}
+
+// Get returns the cached entry for a URL. Storage errors count as a miss.
+func (c *ResponseCache) Get(key string) (*github.CacheEntry, bool) {
+	entry, ok, err := c.storage.GetCachedResponse(context.Background(), key)
+	if err != nil {
+		return nil, false
+	}
+	return entry, ok
+}
+
+// Set stores the entry for a URL
+func (c *ResponseCache) Set(key string, entry *github.CacheEntry) error {
+	return c.storage.SaveCachedResponse(context.Background(), key, entry)
+}
+
+// GetCachedResponse returns the cached GitHub response for a URL and marks it
+// as recently used
+func (s *FileStorage) GetCachedResponse(ctx context.Context, key string) (*github.CacheEntry, bool, error) {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return nil, false, ctx.Err()
+	}
+
+	filename := s.getCachedResponseFilename(key)
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	var entry github.CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		// A corrupt entry only costs a full download
+		return nil, false, nil
+	}
+
+	// The modification time orders entries by use for eviction
+	now := time.Now()
+	os.Chtimes(filename, now, now)
+
+	return &entry, true, nil
+}
+
+// SaveCachedResponse caches the GitHub response for a URL, dropping the least
+// recently used responses beyond its size, MaxCachedResponses by default
+func (s *FileStorage) SaveCachedResponse(ctx context.Context, key string, entry *github.CacheEntry) error {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if err := writeJSONFile("cache", s.getCachedResponseFilename(key), entry); err != nil {
+		return err
+	}
+	return s.evictCachedResponsesUnsafe()
+}
+
+// evictCachedResponsesUnsafe removes the least recently used cached responses
+// beyond the cache size (for internal use, under cacheMutex)
+func (s *FileStorage) evictCachedResponsesUnsafe() error {
+	cacheDir := filepath.Join(s.dataDir, "http_cache")
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return errors.NewStorageError("cache", cacheDir,
+			"failed to list cached responses", err)
+	}
+	if len(entries) <= s.cachedResponses {
+		return nil
+	}
+
+	type cachedFile struct {
+		name   string
+		usedAt time.Time
+	}
+	files := make([]cachedFile, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cachedFile{name: entry.Name(), usedAt: info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].usedAt.Before(files[j].usedAt)
+	})
+
+	for _, file := range files[:max(len(files)-s.cachedResponses, 0)] {
+		os.Remove(filepath.Join(cacheDir, file.name))
+	}
+	return nil
+}
+
+// getCachedResponseFilename generates the filename for a cached URL
+func (s *FileStorage) getCachedResponseFilename(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dataDir, "http_cache", hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,1272 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github-stars-notify/internal/errors"
+	"github-stars-notify/internal/github"
+)
+
+// sqlQuerier is implemented by *sql.DB and *sql.Tx
+type sqlQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// sqlStorage implements the Storage methods shared by the SQLite and
+// PostgreSQL storages on top of database/sql. Queries use $n placeholders,
+// which both drivers accept. Every save runs in a transaction, stargazers are
+// looked up through their primary key.
+type sqlStorage struct {
+	path      string // Names the database in errors
+	db        *sql.DB
+	batchRows int    // Rows written per statement
+	lockRow   string // Clause locking the rows of a SELECT until the transaction ends
+
+	cachedResponses int // Responses kept by the response cache, see MaxCachedResponses
+}
+
+// Load loads the stored data for a repository
+func (s *sqlStorage) Load(ctx context.Context, owner, repo string) (*RepoData, error) {
+	repoData := &RepoData{
+		Owner:      owner,
+		Repo:       repo,
+		Stargazers: []github.Stargazer{},
+	}
+
+	id, found, err := s.findRepository(ctx, s.db, owner, repo)
+	if err != nil || !found {
+		return repoData, err
+	}
+
+	var lastCheck sql.NullInt64
+	if err := s.db.QueryRowContext(ctx, `SELECT last_check FROM repositories WHERE id = $1`, id).Scan(&lastCheck); err != nil {
+		return nil, errors.NewStorageError("load", s.path, "failed to load repository", err)
+	}
+	repoData.LastCheck = fromUnixNano(lastCheck)
+
+	if repoData.Stargazers, err = s.loadStargazers(ctx, s.db, id); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id, login, node_id, avatar_url, html_url, starred_at, recorded_at FROM (
+			SELECT id, user_id, login, node_id, avatar_url, html_url, starred_at, recorded_at
+			FROM star_events WHERE repository_id = $1 AND kind = $2 ORDER BY id DESC LIMIT $3
+		) AS recent ORDER BY id`, id, StarEventUnstarred, MaxRemovedStargazers)
+	if err != nil {
+		return nil, errors.NewStorageError("load", s.path, "failed to load removed stargazers", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var removed RemovedStargazer
+		var starredAt sql.NullInt64
+		var removedAt int64
+		if err := rows.Scan(&removed.ID, &removed.Login, &removed.NodeID, &removed.AvatarURL, &removed.HTMLURL, &starredAt, &removedAt); err != nil {
+			return nil, errors.NewStorageError("load", s.path, "failed to read removed stargazer", err)
+		}
+		removed.StarredAt = fromUnixNano(starredAt)
+		removed.RemovedAt = time.Unix(0, removedAt)
+		repoData.RemovedStargazers = append(repoData.RemovedStargazers, removed)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewStorageError("load", s.path, "failed to load removed stargazers", err)
+	}
+
+	milestones, err := s.loadIDs(ctx, "load", `SELECT stars FROM milestones WHERE repository_id = $1 ORDER BY stars`, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, milestone := range milestones {
+		repoData.Milestones = append(repoData.Milestones, int(milestone))
+	}
+
+	return repoData, nil
+}
+
+// LoadSummary loads the last check time, stargazer count and milestones of a repository
+func (s *sqlStorage) LoadSummary(ctx context.Context, owner, repo string) (*RepoSummary, error) {
+	summary := &RepoSummary{
+		Owner: owner,
+		Repo:  repo,
+	}
+
+	id, found, err := s.findRepository(ctx, s.db, owner, repo)
+	if err != nil || !found {
+		return summary, err
+	}
+
+	var lastCheck sql.NullInt64
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT last_check, (SELECT COUNT(*) FROM stargazers WHERE repository_id = $1)
+		FROM repositories WHERE id = $1`, id).Scan(&lastCheck, &summary.StargazerCount); err != nil {
+		return nil, errors.NewStorageError("load", s.path, "failed to load repository", err)
+	}
+	summary.LastCheck = fromUnixNano(lastCheck)
+
+	milestones, err := s.loadIDs(ctx, "load", `SELECT stars FROM milestones WHERE repository_id = $1 ORDER BY stars`, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, milestone := range milestones {
+		summary.Milestones = append(summary.Milestones, int(milestone))
+	}
+
+	return summary, nil
+}
+
+// StargazerIDs returns the set of stored stargazer IDs of a repository
+func (s *sqlStorage) StargazerIDs(ctx context.Context, owner, repo string) (map[int64]bool, error) {
+	id, found, err := s.findRepository(ctx, s.db, owner, repo)
+	if err != nil || !found {
+		return map[int64]bool{}, err
+	}
+
+	ids, err := s.loadIDs(ctx, "load", `SELECT user_id FROM stargazers WHERE repository_id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[int64]bool, len(ids))
+	for _, userID := range ids {
+		set[userID] = true
+	}
+	return set, nil
+}
+
+// Save replaces the stargazers of a repository. Stargazers that were not known
+// before are recorded as star events.
+func (s *sqlStorage) Save(ctx context.Context, owner, repo string, stargazers []github.Stargazer) error {
+	return s.withTx(ctx, "save", func(tx *sql.Tx) error {
+		id, err := s.ensureRepository(ctx, tx, owner, repo)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := s.replaceStargazers(ctx, tx, id, stargazers, true, now); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `UPDATE repositories SET last_check = $1 WHERE id = $2`, unixNano(now), id); err != nil {
+			return errors.NewStorageError("save", s.path, "failed to update last check", err)
+		}
+		return nil
+	})
+}
+
+// AddStargazer appends a stargazer to the stored data of a repository, for
+// stars received outside of a full check. It reports false if the stargazer
+// was already known. The last check time is left untouched.
+func (s *sqlStorage) AddStargazer(ctx context.Context, owner, repo string, stargazer github.Stargazer) (bool, error) {
+	added := false
+	err := s.withTx(ctx, "add_stargazer", func(tx *sql.Tx) error {
+		id, err := s.ensureRepository(ctx, tx, owner, repo)
+		if err != nil {
+			return err
+		}
+
+		var position int64
+		if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(position) + 1, 0) FROM stargazers WHERE repository_id = $1`, id).Scan(&position); err != nil {
+			return errors.NewStorageError("add_stargazer", s.path, "failed to load stargazers", err)
+		}
+
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO stargazers (repository_id, user_id, position, login, node_id, avatar_url, html_url, starred_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT DO NOTHING`,
+			id, stargazer.ID, position, stargazer.Login, stargazer.NodeID, stargazer.AvatarURL, stargazer.HTMLURL, unixNano(stargazer.StarredAt))
+		if err != nil {
+			return errors.NewStorageError("add_stargazer", s.path, "failed to insert stargazer", err)
+		}
+		if inserted, _ := result.RowsAffected(); inserted == 0 {
+			return nil
+		}
+
+		added = true
+		return s.insertStarEvents(ctx, tx, id, StarEventStarred, []github.Stargazer{stargazer}, time.Now())
+	})
+	return added, err
+}
+
+// AppendStargazers records the new stargazers found by an incremental check,
+// skipping the known ones, and updates the last check time
+func (s *sqlStorage) AppendStargazers(ctx context.Context, owner, repo string, stargazers []github.Stargazer) error {
+	return s.withTx(ctx, "append_stargazers", func(tx *sql.Tx) error {
+		id, err := s.ensureRepository(ctx, tx, owner, repo)
+		if err != nil {
+			return err
+		}
+
+		known, err := s.knownStargazerIDs(ctx, tx, "append_stargazers", id, stargazers)
+		if err != nil {
+			return err
+		}
+
+		var position int64
+		if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(position) + 1, 0) FROM stargazers WHERE repository_id = $1`, id).Scan(&position); err != nil {
+			return errors.NewStorageError("append_stargazers", s.path, "failed to load stargazers", err)
+		}
+
+		var added []github.Stargazer
+		var rows [][]any
+		for _, stargazer := range stargazers {
+			if known[stargazer.ID] {
+				continue
+			}
+			known[stargazer.ID] = true
+			added = append(added, stargazer)
+
+			rows = append(rows, []any{id, stargazer.ID, position, stargazer.Login, stargazer.NodeID,
+				stargazer.AvatarURL, stargazer.HTMLURL, unixNano(stargazer.StarredAt)})
+			position++
+		}
+
+		err = s.insertRows(ctx, tx, "append_stargazers", "failed to insert stargazers",
+			`INSERT INTO stargazers (repository_id, user_id, position, login, node_id, avatar_url, html_url, starred_at)`,
+			`ON CONFLICT DO NOTHING`, rows)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := s.insertStarEvents(ctx, tx, id, StarEventStarred, added, now); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `UPDATE repositories SET last_check = $1 WHERE id = $2`, unixNano(now), id); err != nil {
+			return errors.NewStorageError("append_stargazers", s.path, "failed to update last check", err)
+		}
+		return nil
+	})
+}
+
+// RecordRemovedStargazers records stargazers that removed their star
+func (s *sqlStorage) RecordRemovedStargazers(ctx context.Context, owner, repo string, removed []github.Stargazer) error {
+	if len(removed) == 0 {
+		return nil
+	}
+
+	return s.withTx(ctx, "record_removed", func(tx *sql.Tx) error {
+		id, err := s.ensureRepository(ctx, tx, owner, repo)
+		if err != nil {
+			return err
+		}
+		return s.insertStarEvents(ctx, tx, id, StarEventUnstarred, removed, time.Now())
+	})
+}
+
+// RecordMilestones records star milestones a repository has reached so they are
+// never announced twice
+func (s *sqlStorage) RecordMilestones(ctx context.Context, owner, repo string, milestones []int) error {
+	if len(milestones) == 0 {
+		return nil
+	}
+
+	return s.withTx(ctx, "record_milestones", func(tx *sql.Tx) error {
+		id, err := s.ensureRepository(ctx, tx, owner, repo)
+		if err != nil {
+			return err
+		}
+		return s.insertMilestones(ctx, tx, id, milestones)
+	})
+}
+
+// GetLastCheckTime returns the last check time for a repository
+func (s *sqlStorage) GetLastCheckTime(ctx context.Context, owner, repo string) (time.Time, error) {
+	var lastCheck sql.NullInt64
+	err := s.db.QueryRowContext(ctx, `SELECT last_check FROM repositories WHERE owner = $1 AND repo = $2`, owner, repo).Scan(&lastCheck)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, errors.NewStorageError("last_check", s.path, "failed to load repository", err)
+	}
+	return fromUnixNano(lastCheck), nil
+}
+
+// GetNewStargazers returns the current stargazers that are not stored yet,
+// reading the stored stargazer IDs in a single query
+func (s *sqlStorage) GetNewStargazers(ctx context.Context, owner, repo string, currentStargazers []github.Stargazer) ([]github.Stargazer, error) {
+	id, found, err := s.findRepository(ctx, s.db, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return currentStargazers, nil
+	}
+
+	// Only the current stargazers are looked up, not every stored one
+	stored, err := s.knownStargazerIDs(ctx, s.db, "get_new_stargazers", id, currentStargazers)
+	if err != nil {
+		return nil, err
+	}
+
+	var newStargazers []github.Stargazer
+	for _, stargazer := range currentStargazers {
+		if !stored[stargazer.ID] {
+			newStargazers = append(newStargazers, stargazer)
+		}
+	}
+	return newStargazers, nil
+}
+
+// DiffStargazers compares current stargazers with previous data and returns added and removed ones.
+// currentStargazers must be the complete list for removals to be meaningful.
+// Only the stored IDs are loaded, the rows of removed stargazers are read back
+// for their profiles.
+func (s *sqlStorage) DiffStargazers(ctx context.Context, owner, repo string, currentStargazers []github.Stargazer) (*StargazerDiff, error) {
+	id, found, err := s.findRepository(ctx, s.db, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return &StargazerDiff{Added: currentStargazers}, nil
+	}
+
+	ids, err := s.loadIDs(ctx, "diff", `SELECT user_id FROM stargazers WHERE repository_id = $1 ORDER BY position`, id)
+	if err != nil {
+		return nil, err
+	}
+	stored := make(map[int64]bool, len(ids))
+	for _, userID := range ids {
+		stored[userID] = true
+	}
+	current := make(map[int64]bool, len(currentStargazers))
+	diff := &StargazerDiff{}
+	for _, stargazer := range currentStargazers {
+		current[stargazer.ID] = true
+		if !stored[stargazer.ID] {
+			diff.Added = append(diff.Added, stargazer)
+		}
+	}
+
+	var removed []any
+	for _, userID := range ids {
+		if !current[userID] {
+			removed = append(removed, userID)
+		}
+	}
+	for len(removed) > 0 {
+		batch := removed[:min(len(removed), s.batchRows)]
+		removed = removed[len(batch):]
+
+		stargazers, err := s.queryStargazers(ctx, s.db, "diff", `
+			SELECT user_id, login, node_id, avatar_url, html_url, starred_at
+			FROM stargazers WHERE repository_id = $1 AND user_id IN (`+placeholders(2, len(batch))+`) ORDER BY position`,
+			append([]any{id}, batch...)...)
+		if err != nil {
+			return nil, err
+		}
+		diff.Removed = append(diff.Removed, stargazers...)
+	}
+	return diff, nil
+}
+
+// LoadForks loads the stored forks of a repository
+func (s *sqlStorage) LoadForks(ctx context.Context, owner, repo string) (*ForkData, error) {
+	forkData := &ForkData{
+		Owner: owner,
+		Repo:  repo,
+		Forks: []github.Repository{},
+	}
+
+	id, found, err := s.findRepository(ctx, s.db, owner, repo)
+	if err != nil || !found {
+		return forkData, err
+	}
+
+	var checkedAt sql.NullInt64
+	if err := s.db.QueryRowContext(ctx, `SELECT forks_checked_at FROM repositories WHERE id = $1`, id).Scan(&checkedAt); err != nil {
+		return nil, errors.NewStorageError("load_forks", s.path, "failed to load repository", err)
+	}
+	forkData.LastCheck = fromUnixNano(checkedAt)
+
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM forks WHERE repository_id = $1 ORDER BY fork_id`, id)
+	if err != nil {
+		return nil, errors.NewStorageError("load_forks", s.path, "failed to load forks", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data []byte
+		var fork github.Repository
+		if err := rows.Scan(&data); err != nil {
+			return nil, errors.NewStorageError("load_forks", s.path, "failed to read fork", err)
+		}
+		if err := json.Unmarshal(data, &fork); err != nil {
+			return nil, errors.NewStorageError("load_forks", s.path, "failed to unmarshal fork", err)
+		}
+		forkData.Forks = append(forkData.Forks, fork)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewStorageError("load_forks", s.path, "failed to load forks", err)
+	}
+
+	return forkData, nil
+}
+
+// SaveForks saves the forks of a repository
+func (s *sqlStorage) SaveForks(ctx context.Context, owner, repo string, forks []github.Repository) error {
+	return s.withTx(ctx, "save_forks", func(tx *sql.Tx) error {
+		id, err := s.ensureRepository(ctx, tx, owner, repo)
+		if err != nil {
+			return err
+		}
+		return s.replaceForks(ctx, tx, id, forks, time.Now())
+	})
+}
+
+// GetNewForks compares current forks with previous data and returns new ones
+func (s *sqlStorage) GetNewForks(ctx context.Context, owner, repo string, currentForks []github.Repository) ([]github.Repository, error) {
+	forkData, err := s.LoadForks(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fork data: %w", err)
+	}
+
+	added, _ := diffByID(forkData.Forks, currentForks, func(fork github.Repository) int64 { return fork.ID })
+	return added, nil
+}
+
+// LoadReleases loads the release IDs already seen for a repository
+func (s *sqlStorage) LoadReleases(ctx context.Context, owner, repo string) (*ReleaseData, error) {
+	releaseData := &ReleaseData{
+		Owner:      owner,
+		Repo:       repo,
+		ReleaseIDs: []int64{},
+	}
+
+	id, found, err := s.findRepository(ctx, s.db, owner, repo)
+	if err != nil || !found {
+		return releaseData, err
+	}
+
+	var checkedAt sql.NullInt64
+	if err := s.db.QueryRowContext(ctx, `SELECT releases_checked_at FROM repositories WHERE id = $1`, id).Scan(&checkedAt); err != nil {
+		return nil, errors.NewStorageError("load_releases", s.path, "failed to load repository", err)
+	}
+	releaseData.LastCheck = fromUnixNano(checkedAt)
+
+	ids, err := s.loadIDs(ctx, "load_releases", `SELECT release_id FROM releases WHERE repository_id = $1 ORDER BY release_id`, id)
+	if err != nil {
+		return nil, err
+	}
+	releaseData.ReleaseIDs = append(releaseData.ReleaseIDs, ids...)
+
+	var filter ReleaseFilter
+	err = s.db.QueryRowContext(ctx, `SELECT prereleases, drafts FROM release_filters WHERE repository_id = $1`, id).
+		Scan(&filter.Prereleases, &filter.Drafts)
+	if err == nil {
+		releaseData.Filter = &filter
+	} else if err != sql.ErrNoRows {
+		return nil, errors.NewStorageError("load_releases", s.path, "failed to load release filter", err)
+	}
+
+	return releaseData, nil
+}
+
+// SaveReleases marks releases of a repository as seen under the given filter.
+// Previously seen IDs are kept, so a release dropping out of the most recent
+// page is never announced again.
+func (s *sqlStorage) SaveReleases(ctx context.Context, owner, repo string, releases []github.Release, filter ReleaseFilter) error {
+	ids := make([]int64, 0, len(releases))
+	for _, release := range releases {
+		ids = append(ids, release.ID)
+	}
+
+	return s.withTx(ctx, "save_releases", func(tx *sql.Tx) error {
+		id, err := s.ensureRepository(ctx, tx, owner, repo)
+		if err != nil {
+			return err
+		}
+		return s.insertReleases(ctx, tx, id, ids, &filter, time.Now())
+	})
+}
+
+// GetNewReleases returns the releases that have not been seen before
+func (s *sqlStorage) GetNewReleases(ctx context.Context, owner, repo string, currentReleases []github.Release) ([]github.Release, error) {
+	releaseData, err := s.LoadReleases(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load release data: %w", err)
+	}
+
+	seen := releaseData.releaseIDs()
+	var newReleases []github.Release
+	for _, release := range currentReleases {
+		if !seen[release.ID] {
+			newReleases = append(newReleases, release)
+		}
+	}
+	return newReleases, nil
+}
+
+// LoadFollowers loads the stored followers of a user or organization
+func (s *sqlStorage) LoadFollowers(ctx context.Context, login string) (*FollowerData, error) {
+	followerData := &FollowerData{
+		Login:     login,
+		Followers: []github.Stargazer{},
+	}
+
+	var id int64
+	var lastCheck sql.NullInt64
+	err := s.db.QueryRowContext(ctx, `SELECT id, last_check FROM accounts WHERE login = $1`, login).Scan(&id, &lastCheck)
+	if err == sql.ErrNoRows {
+		return followerData, nil
+	}
+	if err != nil {
+		return nil, errors.NewStorageError("load_followers", s.path, "failed to load account", err)
+	}
+	followerData.LastCheck = fromUnixNano(lastCheck)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id, login, node_id, avatar_url, html_url
+		FROM followers WHERE account_id = $1 ORDER BY position`, id)
+	if err != nil {
+		return nil, errors.NewStorageError("load_followers", s.path, "failed to load followers", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var follower github.Stargazer
+		if err := rows.Scan(&follower.ID, &follower.Login, &follower.NodeID, &follower.AvatarURL, &follower.HTMLURL); err != nil {
+			return nil, errors.NewStorageError("load_followers", s.path, "failed to read follower", err)
+		}
+		followerData.Followers = append(followerData.Followers, follower)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewStorageError("load_followers", s.path, "failed to load followers", err)
+	}
+
+	return followerData, nil
+}
+
+// SaveFollowers saves the followers of a user or organization
+func (s *sqlStorage) SaveFollowers(ctx context.Context, login string, followers []github.Stargazer) error {
+	return s.withTx(ctx, "save_followers", func(tx *sql.Tx) error {
+		return s.replaceFollowers(ctx, tx, login, followers, time.Now())
+	})
+}
+
+// DiffFollowers compares current followers with previous data and returns added and removed ones
+func (s *sqlStorage) DiffFollowers(ctx context.Context, login string, currentFollowers []github.Stargazer) (*StargazerDiff, error) {
+	followerData, err := s.LoadFollowers(ctx, login)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load follower data: %w", err)
+	}
+
+	return diffStargazers(followerData.Followers, currentFollowers), nil
+}
+
+// GetRepositoryState returns the stored state of a repository, or an empty
+// state if none was recorded yet
+func (s *sqlStorage) GetRepositoryState(ctx context.Context, owner, repo string) (*RepositoryState, error) {
+	var state RepositoryState
+	var suspendedAt sql.NullInt64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT github_id, renamed_to, not_found_count, suspended_at
+		FROM repositories WHERE owner = $1 AND repo = $2`, owner, repo).
+		Scan(&state.ID, &state.RenamedTo, &state.NotFoundCount, &suspendedAt)
+	if err == sql.ErrNoRows {
+		return &state, nil
+	}
+	if err != nil {
+		return nil, errors.NewStorageError("load_repository_state", s.path, "failed to load repository", err)
+	}
+	state.SuspendedAt = fromUnixNano(suspendedAt)
+	return &state, nil
+}
+
+// SetRepositoryState stores the state of a repository
+func (s *sqlStorage) SetRepositoryState(ctx context.Context, owner, repo string, state *RepositoryState) error {
+	return s.withTx(ctx, "set_repository_state", func(tx *sql.Tx) error {
+		id, err := s.ensureRepository(ctx, tx, owner, repo)
+		if err != nil {
+			return err
+		}
+		return s.updateRepositoryState(ctx, tx, id, state)
+	})
+}
+
+// RenameRepository moves the stored data of a repository to its new name and
+// records the rename, so later checks under the old name can follow it. If the
+// new name already has data, the rows of the old name are merged into it.
+func (s *sqlStorage) RenameRepository(ctx context.Context, owner, repo, newOwner, newRepo string) error {
+	return s.withTx(ctx, "rename_repository", func(tx *sql.Tx) error {
+		oldID, err := s.ensureRepository(ctx, tx, owner, repo)
+		if err != nil {
+			return err
+		}
+
+		var state RepositoryState
+		var suspendedAt sql.NullInt64
+		err = tx.QueryRowContext(ctx, `
+			SELECT github_id, not_found_count, suspended_at FROM repositories WHERE id = $1`, oldID).
+			Scan(&state.ID, &state.NotFoundCount, &suspendedAt)
+		if err != nil {
+			return errors.NewStorageError("rename_repository", s.path, "failed to load repository", err)
+		}
+		state.SuspendedAt = fromUnixNano(suspendedAt)
+
+		newID, found, err := s.findRepository(ctx, tx, newOwner, newRepo)
+		if err != nil {
+			return err
+		}
+		if found {
+			// The rows of the old name join the data of the new name
+			if err := s.mergeRepository(ctx, tx, oldID, newID); err != nil {
+				return err
+			}
+			if err := s.updateRepositoryState(ctx, tx, newID, &state); err != nil {
+				return err
+			}
+		} else {
+			// The row and everything referencing it move to the new name
+			if _, err := tx.ExecContext(ctx, `UPDATE repositories SET owner = $1, repo = $2 WHERE id = $3`, newOwner, newRepo, oldID); err != nil {
+				return errors.NewStorageError("rename_repository", s.path, "failed to rename repository", err)
+			}
+			if oldID, err = s.ensureRepository(ctx, tx, owner, repo); err != nil {
+				return err
+			}
+		}
+
+		return s.updateRepositoryState(ctx, tx, oldID, &RepositoryState{ID: state.ID, RenamedTo: newOwner + "/" + newRepo})
+	})
+}
+
+// mergeRepository moves the rows of repository from to repository to, which
+// already has data of its own. Rows to already holds for the same stargazer,
+// milestone, fork, release or release filter are kept, moved stargazers are
+// placed after its own and check times it lacks are taken from from.
+func (s *sqlStorage) mergeRepository(ctx context.Context, tx *sql.Tx, from, to int64) error {
+	var position int64
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(position) + 1, 0) FROM stargazers WHERE repository_id = $1`, to).Scan(&position); err != nil {
+		return errors.NewStorageError("rename_repository", s.path, "failed to load stargazers", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE stargazers SET repository_id = $1, position = position + $3
+		WHERE repository_id = $2 AND user_id NOT IN (SELECT user_id FROM stargazers WHERE repository_id = $1)`,
+		to, from, position); err != nil {
+		return errors.NewStorageError("rename_repository", s.path, "failed to move stargazers", err)
+	}
+
+	moves := []struct{ table, query string }{
+		{"star_events", `UPDATE star_events SET repository_id = $1 WHERE repository_id = $2`},
+		{"milestones", `UPDATE milestones SET repository_id = $1
+			WHERE repository_id = $2 AND stars NOT IN (SELECT stars FROM milestones WHERE repository_id = $1)`},
+		{"forks", `UPDATE forks SET repository_id = $1
+			WHERE repository_id = $2 AND fork_id NOT IN (SELECT fork_id FROM forks WHERE repository_id = $1)`},
+		{"releases", `UPDATE releases SET repository_id = $1
+			WHERE repository_id = $2 AND release_id NOT IN (SELECT release_id FROM releases WHERE repository_id = $1)`},
+		{"release_filters", `UPDATE release_filters SET repository_id = $1
+			WHERE repository_id = $2 AND NOT EXISTS (SELECT 1 FROM release_filters WHERE repository_id = $1)`},
+		{"repositories", `UPDATE repositories SET
+			last_check = COALESCE(last_check, (SELECT last_check FROM repositories WHERE id = $2)),
+			forks_checked_at = COALESCE(forks_checked_at, (SELECT forks_checked_at FROM repositories WHERE id = $2)),
+			releases_checked_at = COALESCE(releases_checked_at, (SELECT releases_checked_at FROM repositories WHERE id = $2))
+			WHERE id = $1`},
+	}
+	for _, move := range moves {
+		if _, err := tx.ExecContext(ctx, move.query, to, from); err != nil {
+			return errors.NewStorageError("rename_repository", s.path, "failed to move "+move.table, err)
+		}
+	}
+
+	// Whatever the new name already held is dropped from the old one
+	for _, table := range []string{"stargazers", "milestones", "forks", "releases", "release_filters"} {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE repository_id = $1`, from); err != nil {
+			return errors.NewStorageError("rename_repository", s.path, "failed to delete "+table, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE repositories SET last_check = NULL, forks_checked_at = NULL, releases_checked_at = NULL
+		WHERE id = $1`, from); err != nil {
+		return errors.NewStorageError("rename_repository", s.path, "failed to reset repository", err)
+	}
+	return nil
+}
+
+// starEventTime is the SQL expression of StarEvent.Time
+const starEventTime = `CASE WHEN e.kind = 'starred' AND e.starred_at IS NOT NULL THEN e.starred_at ELSE e.recorded_at END`
+
+// GetUserProfile returns the cached profile of a user if it was fetched within
+// ttl. The key identifies the user, typically its login, and is case-insensitive.
+func (s *sqlStorage) GetUserProfile(ctx context.Context, key string, ttl time.Duration) (*github.UserProfile, bool, error) {
+	var data []byte
+	var fetchedAt int64
+	err := s.db.QueryRowContext(ctx, `SELECT profile, fetched_at FROM user_profiles WHERE key = $1`, strings.ToLower(key)).Scan(&data, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.NewStorageError("profile_cache", s.path, "failed to load profile", err)
+	}
+	if time.Since(time.Unix(0, fetchedAt)) > ttl {
+		return nil, false, nil
+	}
+
+	var profile github.UserProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		// A corrupt entry only costs an extra lookup
+		return nil, false, nil
+	}
+	return &profile, true, nil
+}
+
+// SaveUserProfile caches the profile of a user, dropping the profiles fetched
+// more than ttl ago
+func (s *sqlStorage) SaveUserProfile(ctx context.Context, key string, profile *github.UserProfile, ttl time.Duration) error {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return errors.NewStorageError("profile_cache", s.path, "failed to marshal profile", err)
+	}
+
+	now := time.Now()
+	return s.withTx(ctx, "profile_cache", func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO user_profiles (key, profile, fetched_at) VALUES ($1, $2, $3)
+			ON CONFLICT (key) DO UPDATE SET profile = excluded.profile, fetched_at = excluded.fetched_at`,
+			strings.ToLower(key), string(data), now.UnixNano())
+		if err != nil {
+			return errors.NewStorageError("profile_cache", s.path, "failed to save profile", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_profiles WHERE fetched_at < $1`, now.Add(-ttl).UnixNano()); err != nil {
+			return errors.NewStorageError("profile_cache", s.path, "failed to drop expired profiles", err)
+		}
+		return nil
+	})
+}
+
+// GetCachedResponse returns the cached GitHub response for a URL and marks it
+// as recently used
+func (s *sqlStorage) GetCachedResponse(ctx context.Context, key string) (*github.CacheEntry, bool, error) {
+	var entry github.CacheEntry
+	err := s.db.QueryRowContext(ctx, `SELECT etag, last_modified, link, body FROM http_responses WHERE key = $1`, key).
+		Scan(&entry.ETag, &entry.LastModified, &entry.Link, &entry.Body)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.NewStorageError("cache", s.path, "failed to load cached response", err)
+	}
+
+	if _, err := s.db.ExecContext(ctx, `UPDATE http_responses SET used_at = $1 WHERE key = $2`, time.Now().UnixNano(), key); err != nil {
+		return nil, false, errors.NewStorageError("cache", s.path, "failed to update cached response", err)
+	}
+	return &entry, true, nil
+}
+
+// SaveCachedResponse caches the GitHub response for a URL, dropping the least
+// recently used responses beyond its size, MaxCachedResponses by default
+func (s *sqlStorage) SaveCachedResponse(ctx context.Context, key string, entry *github.CacheEntry) error {
+	return s.withTx(ctx, "cache", func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO http_responses (key, etag, last_modified, link, body, used_at) VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (key) DO UPDATE SET etag = excluded.etag, last_modified = excluded.last_modified,
+				link = excluded.link, body = excluded.body, used_at = excluded.used_at`,
+			key, entry.ETag, entry.LastModified, entry.Link, entry.Body, time.Now().UnixNano())
+		if err != nil {
+			return errors.NewStorageError("cache", s.path, "failed to save cached response", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM http_responses WHERE used_at < (
+				SELECT used_at FROM http_responses ORDER BY used_at DESC LIMIT 1 OFFSET $1
+			)`, s.cachedResponses-1)
+		if err != nil {
+			return errors.NewStorageError("cache", s.path, "failed to drop cached responses", err)
+		}
+		return nil
+	})
+}
+
+// StarEvents returns the events of the star event log of a repository matching
+// query, oldest first
+func (s *sqlStorage) StarEvents(ctx context.Context, query StarEventQuery) ([]StarEvent, error) {
+	if err := query.validate(); err != nil {
+		return nil, err
+	}
+
+	conditions := []string{`r.owner = $1`, `r.repo = $2`}
+	args := []any{query.Owner, query.Repo}
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if query.Kind != "" {
+		addCondition(`e.kind = $%d`, query.Kind)
+	}
+	if query.Login != "" {
+		addCondition(`lower(e.login) = lower($%d)`, query.Login)
+	}
+	if !query.Since.IsZero() {
+		addCondition(starEventTime+` >= $%d`, query.Since.UnixNano())
+	}
+	if !query.Until.IsZero() {
+		addCondition(starEventTime+` < $%d`, query.Until.UnixNano())
+	}
+
+	statement := `
+		SELECT e.kind, e.user_id, e.login, e.node_id, e.avatar_url, e.html_url, e.starred_at, e.recorded_at
+		FROM star_events e JOIN repositories r ON r.id = e.repository_id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + starEventTime + `, e.id`
+	if query.Limit > 0 {
+		args = append(args, query.Limit)
+		statement += fmt.Sprintf(` LIMIT $%d`, len(args))
+	}
+
+	rows, err := s.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, errors.NewStorageError("load_events", s.path, "failed to load star events", err)
+	}
+	defer rows.Close()
+
+	var events []StarEvent
+	for rows.Next() {
+		event := StarEvent{Owner: query.Owner, Repo: query.Repo}
+		var starredAt sql.NullInt64
+		var recordedAt int64
+		if err := rows.Scan(&event.Kind, &event.Stargazer.ID, &event.Stargazer.Login, &event.Stargazer.NodeID,
+			&event.Stargazer.AvatarURL, &event.Stargazer.HTMLURL, &starredAt, &recordedAt); err != nil {
+			return nil, errors.NewStorageError("load_events", s.path, "failed to read star event", err)
+		}
+		event.Stargazer.StarredAt = fromUnixNano(starredAt)
+		event.RecordedAt = time.Unix(0, recordedAt)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewStorageError("load_events", s.path, "failed to load star events", err)
+	}
+	return events, nil
+}
+
+// Close closes the database
+func (s *sqlStorage) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// seedStarEventsSQL records a star for every stargazer without one, converting
+// the stargazers stored before every new stargazer was logged into the start
+// of the star event log. They count as detected at the last check.
+const seedStarEventsSQL = `
+INSERT INTO star_events (repository_id, kind, user_id, login, node_id, avatar_url, html_url, starred_at, recorded_at)
+SELECT s.repository_id, 'starred', s.user_id, s.login, s.node_id, s.avatar_url, s.html_url, s.starred_at,
+	COALESCE(r.last_check, s.starred_at, 0)
+FROM stargazers s JOIN repositories r ON r.id = s.repository_id
+WHERE NOT EXISTS (
+	SELECT 1 FROM star_events e
+	WHERE e.repository_id = s.repository_id AND e.user_id = s.user_id AND e.kind = 'starred'
+)
+ORDER BY s.repository_id, s.position`
+
+// runOnce runs fn in a transaction unless it ran before, which is recorded
+// under key in the metadata table. op names the storage operation in errors.
+func (s *sqlStorage) runOnce(ctx context.Context, op, key string, fn func(tx *sql.Tx) error) error {
+	var doneAt string
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM metadata WHERE key = $1`, key).Scan(&doneAt)
+	if err == nil {
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		return errors.NewStorageError(op, s.path, "failed to load metadata", err)
+	}
+
+	return s.withTx(ctx, op, func(tx *sql.Tx) error {
+		if err := fn(tx); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, `INSERT INTO metadata (key, value) VALUES ($1, $2)`,
+			key, time.Now().UTC().Format(time.RFC3339))
+		if err != nil {
+			return errors.NewStorageError(op, s.path, "failed to save metadata", err)
+		}
+		return nil
+	})
+}
+
+// withTx runs fn in a transaction, committed if fn succeeds. op names the
+// storage operation in errors.
+func (s *sqlStorage) withTx(ctx context.Context, op string, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.NewStorageError(op, s.path, "failed to begin transaction", err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewStorageError(op, s.path, "failed to commit transaction", err)
+	}
+	return nil
+}
+
+// findRepository returns the row ID of a repository, found is false if it has no row
+func (s *sqlStorage) findRepository(ctx context.Context, q sqlQuerier, owner, repo string) (id int64, found bool, err error) {
+	err = q.QueryRowContext(ctx, `SELECT id FROM repositories WHERE owner = $1 AND repo = $2`, owner, repo).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, errors.NewStorageError("find_repository", s.path, "failed to load repository", err)
+	}
+	return id, true, nil
+}
+
+// ensureRepository returns the row ID of a repository, creating the row if
+// needed. The row stays locked until tx ends, so concurrent writes of a
+// repository do not both record the same star events or stargazer positions.
+func (s *sqlStorage) ensureRepository(ctx context.Context, tx *sql.Tx, owner, repo string) (int64, error) {
+	if _, err := tx.ExecContext(ctx, `INSERT INTO repositories (owner, repo) VALUES ($1, $2) ON CONFLICT DO NOTHING`, owner, repo); err != nil {
+		return 0, errors.NewStorageError("create_repository", s.path, "failed to create repository", err)
+	}
+
+	var id int64
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM repositories WHERE owner = $1 AND repo = $2`+s.lockRow, owner, repo).Scan(&id); err != nil {
+		return 0, errors.NewStorageError("create_repository", s.path, "failed to lock repository", err)
+	}
+	return id, nil
+}
+
+// loadStargazers loads the stargazers of a repository in the order they were saved
+func (s *sqlStorage) loadStargazers(ctx context.Context, q sqlQuerier, id int64) ([]github.Stargazer, error) {
+	return s.queryStargazers(ctx, q, "load", `
+		SELECT user_id, login, node_id, avatar_url, html_url, starred_at
+		FROM stargazers WHERE repository_id = $1 ORDER BY position`, id)
+}
+
+// queryStargazers runs a query selecting the columns of stargazer rows
+func (s *sqlStorage) queryStargazers(ctx context.Context, q sqlQuerier, op, query string, args ...any) ([]github.Stargazer, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.NewStorageError(op, s.path, "failed to load stargazers", err)
+	}
+	defer rows.Close()
+
+	stargazers := []github.Stargazer{}
+	for rows.Next() {
+		var stargazer github.Stargazer
+		var starredAt sql.NullInt64
+		if err := rows.Scan(&stargazer.ID, &stargazer.Login, &stargazer.NodeID, &stargazer.AvatarURL, &stargazer.HTMLURL, &starredAt); err != nil {
+			return nil, errors.NewStorageError(op, s.path, "failed to read stargazer", err)
+		}
+		stargazer.StarredAt = fromUnixNano(starredAt)
+		stargazers = append(stargazers, stargazer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewStorageError(op, s.path, "failed to load stargazers", err)
+	}
+	return stargazers, nil
+}
+
+// knownStargazerIDs returns the IDs of stargazers that are stored for a
+// repository, querying only their IDs with one statement per batchRows of them
+func (s *sqlStorage) knownStargazerIDs(ctx context.Context, q sqlQuerier, op string, id int64, stargazers []github.Stargazer) (map[int64]bool, error) {
+	candidates := make([]any, len(stargazers))
+	for i, stargazer := range stargazers {
+		candidates[i] = stargazer.ID
+	}
+
+	known := make(map[int64]bool)
+	for len(candidates) > 0 {
+		batch := candidates[:min(len(candidates), s.batchRows)]
+		candidates = candidates[len(batch):]
+
+		query := `SELECT user_id FROM stargazers WHERE repository_id = $1 AND user_id IN (` + placeholders(2, len(batch)) + `)`
+		rows, err := q.QueryContext(ctx, query, append([]any{id}, batch...)...)
+		if err != nil {
+			return nil, errors.NewStorageError(op, s.path, "failed to query stargazers", err)
+		}
+		for rows.Next() {
+			var userID int64
+			if err := rows.Scan(&userID); err != nil {
+				rows.Close()
+				return nil, errors.NewStorageError(op, s.path, "failed to read stargazer", err)
+			}
+			known[userID] = true
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, errors.NewStorageError(op, s.path, "failed to query stargazers", err)
+		}
+	}
+	return known, nil
+}
+
+// replaceStargazers makes the stored stargazers of a repository match
+// stargazers, only writing the rows that changed. Stored stargazers keep their
+// position, so a removal only deletes its row and new stargazers are appended
+// after the others. New stargazers are recorded as star events when
+// recordEvents is set.
+func (s *sqlStorage) replaceStargazers(ctx context.Context, tx *sql.Tx, id int64, stargazers []github.Stargazer, recordEvents bool, now time.Time) error {
+	existing, err := s.loadStargazers(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	stored := make(map[int64]github.Stargazer, len(existing))
+	for _, stargazer := range existing {
+		stored[stargazer.ID] = stargazer
+	}
+
+	var position int64
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(position) + 1, 0) FROM stargazers WHERE repository_id = $1`, id).Scan(&position); err != nil {
+		return errors.NewStorageError("save", s.path, "failed to load stargazers", err)
+	}
+
+	current := make(map[int64]bool, len(stargazers))
+	var added []github.Stargazer
+	var rows [][]any
+	for _, stargazer := range stargazers {
+		if current[stargazer.ID] {
+			continue
+		}
+		current[stargazer.ID] = true
+
+		previous, known := stored[stargazer.ID]
+		if known && sameStargazer(previous, stargazer) {
+			continue
+		}
+		if !known {
+			added = append(added, stargazer)
+		}
+
+		// The position only applies to new rows, updates keep the stored one
+		rows = append(rows, []any{id, stargazer.ID, position, stargazer.Login, stargazer.NodeID,
+			stargazer.AvatarURL, stargazer.HTMLURL, unixNano(stargazer.StarredAt)})
+		position++
+	}
+
+	err = s.insertRows(ctx, tx, "save", "failed to save stargazers",
+		`INSERT INTO stargazers (repository_id, user_id, position, login, node_id, avatar_url, html_url, starred_at)`,
+		`ON CONFLICT (repository_id, user_id) DO UPDATE SET
+			login = excluded.login, node_id = excluded.node_id, avatar_url = excluded.avatar_url,
+			html_url = excluded.html_url, starred_at = excluded.starred_at`,
+		rows)
+	if err != nil {
+		return err
+	}
+
+	var removed []any
+	for _, stargazer := range existing {
+		if !current[stargazer.ID] {
+			removed = append(removed, stargazer.ID)
+		}
+	}
+	for len(removed) > 0 {
+		batch := removed[:min(len(removed), s.batchRows)]
+		removed = removed[len(batch):]
+
+		query := `DELETE FROM stargazers WHERE repository_id = $1 AND user_id IN (` + placeholders(2, len(batch)) + `)`
+		if _, err := tx.ExecContext(ctx, query, append([]any{id}, batch...)...); err != nil {
+			return errors.NewStorageError("save", s.path, "failed to delete stargazers", err)
+		}
+	}
+
+	if !recordEvents {
+		return nil
+	}
+	return s.insertStarEvents(ctx, tx, id, StarEventStarred, added, now)
+}
+
+// sameStargazer reports whether the stored fields of two stargazers are equal
+func sameStargazer(a, b github.Stargazer) bool {
+	return a.Login == b.Login && a.NodeID == b.NodeID && a.AvatarURL == b.AvatarURL &&
+		a.HTMLURL == b.HTMLURL && a.StarredAt.Equal(b.StarredAt)
+}
+
+// insertStarEvents records a star event of the given kind for each stargazer
+func (s *sqlStorage) insertStarEvents(ctx context.Context, tx *sql.Tx, id int64, kind string, stargazers []github.Stargazer, at time.Time) error {
+	rows := make([][]any, 0, len(stargazers))
+	for _, stargazer := range stargazers {
+		rows = append(rows, []any{id, kind, stargazer.ID, stargazer.Login, stargazer.NodeID, stargazer.AvatarURL,
+			stargazer.HTMLURL, unixNano(stargazer.StarredAt), at.UnixNano()})
+	}
+	return s.insertRows(ctx, tx, "record_star_event", "failed to record star events",
+		`INSERT INTO star_events (repository_id, kind, user_id, login, node_id, avatar_url, html_url, starred_at, recorded_at)`,
+		"", rows)
+}
+
+// insertMilestones records milestones of a repository, ignoring known ones
+func (s *sqlStorage) insertMilestones(ctx context.Context, tx *sql.Tx, id int64, milestones []int) error {
+	for _, milestone := range milestones {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO milestones (repository_id, stars) VALUES ($1, $2) ON CONFLICT DO NOTHING`, id, milestone); err != nil {
+			return errors.NewStorageError("record_milestones", s.path, "failed to record milestone", err)
+		}
+	}
+	return nil
+}
+
+// replaceForks replaces the stored forks of a repository
+func (s *sqlStorage) replaceForks(ctx context.Context, tx *sql.Tx, id int64, forks []github.Repository, checkedAt time.Time) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM forks WHERE repository_id = $1`, id); err != nil {
+		return errors.NewStorageError("save_forks", s.path, "failed to delete forks", err)
+	}
+
+	for _, fork := range forks {
+		data, err := json.Marshal(fork)
+		if err != nil {
+			return errors.NewStorageError("save_forks", s.path, "failed to marshal fork", err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO forks (repository_id, fork_id, data) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`, id, fork.ID, string(data)); err != nil {
+			return errors.NewStorageError("save_forks", s.path, "failed to save fork", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE repositories SET forks_checked_at = $1 WHERE id = $2`, unixNano(checkedAt), id); err != nil {
+		return errors.NewStorageError("save_forks", s.path, "failed to update last check", err)
+	}
+	return nil
+}
+
+// insertReleases marks release IDs of a repository as seen and records the
+// filter they were selected with, unless nil
+func (s *sqlStorage) insertReleases(ctx context.Context, tx *sql.Tx, id int64, releaseIDs []int64, filter *ReleaseFilter, checkedAt time.Time) error {
+	for _, releaseID := range releaseIDs {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO releases (repository_id, release_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, id, releaseID); err != nil {
+			return errors.NewStorageError("save_releases", s.path, "failed to save release", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE repositories SET releases_checked_at = $1 WHERE id = $2`, unixNano(checkedAt), id); err != nil {
+		return errors.NewStorageError("save_releases", s.path, "failed to update last check", err)
+	}
+
+	if filter != nil {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO release_filters (repository_id, prereleases, drafts) VALUES ($1, $2, $3)
+			ON CONFLICT (repository_id) DO UPDATE SET prereleases = excluded.prereleases, drafts = excluded.drafts`,
+			id, filter.Prereleases, filter.Drafts)
+		if err != nil {
+			return errors.NewStorageError("save_releases", s.path, "failed to save release filter", err)
+		}
+	}
+	return nil
+}
+
+// replaceFollowers replaces the stored followers of an account
+func (s *sqlStorage) replaceFollowers(ctx context.Context, tx *sql.Tx, login string, followers []github.Stargazer, checkedAt time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO accounts (login, last_check) VALUES ($1, $2)
+		ON CONFLICT (login) DO UPDATE SET last_check = excluded.last_check`, login, unixNano(checkedAt))
+	if err != nil {
+		return errors.NewStorageError("save_followers", s.path, "failed to save account", err)
+	}
+
+	var id int64
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM accounts WHERE login = $1`, login).Scan(&id); err != nil {
+		return errors.NewStorageError("save_followers", s.path, "failed to load account", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM followers WHERE account_id = $1`, id); err != nil {
+		return errors.NewStorageError("save_followers", s.path, "failed to delete followers", err)
+	}
+	for position, follower := range followers {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO followers (account_id, user_id, position, login, node_id, avatar_url, html_url)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT DO NOTHING`,
+			id, follower.ID, position, follower.Login, follower.NodeID, follower.AvatarURL, follower.HTMLURL)
+		if err != nil {
+			return errors.NewStorageError("save_followers", s.path, "failed to save follower", err)
+		}
+	}
+	return nil
+}
+
+// updateRepositoryState stores the state columns of a repository
+func (s *sqlStorage) updateRepositoryState(ctx context.Context, tx *sql.Tx, id int64, state *RepositoryState) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE repositories SET github_id = $1, renamed_to = $2, not_found_count = $3, suspended_at = $4
+		WHERE id = $5`, state.ID, state.RenamedTo, state.NotFoundCount, unixNano(state.SuspendedAt), id)
+	if err != nil {
+		return errors.NewStorageError("set_repository_state", s.path, "failed to save repository state", err)
+	}
+	return nil
+}
+
+// loadIDs runs a query selecting a single integer column for a repository
+func (s *sqlStorage) loadIDs(ctx context.Context, op, query string, id int64) ([]int64, error) {
+	rows, err := s.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, errors.NewStorageError(op, s.path, "failed to query database", err)
+	}
+	defer rows.Close()
+
+	var values []int64
+	for rows.Next() {
+		var value int64
+		if err := rows.Scan(&value); err != nil {
+			return nil, errors.NewStorageError(op, s.path, "failed to read row", err)
+		}
+		values = append(values, value)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewStorageError(op, s.path, "failed to query database", err)
+	}
+	return values, nil
+}
+
+// insertRows runs an INSERT of rows with one statement per batchRows rows.
+// query is the statement up to VALUES, suffix the clause following the value
+// lists. msg describes a failure in errors.
+func (s *sqlStorage) insertRows(ctx context.Context, tx *sql.Tx, op, msg, query, suffix string, rows [][]any) error {
+	for len(rows) > 0 {
+		batch := rows[:min(len(rows), s.batchRows)]
+		rows = rows[len(batch):]
+
+		var statement strings.Builder
+		statement.WriteString(query)
+		statement.WriteString(" VALUES ")
+		args := make([]any, 0, len(batch)*len(batch[0]))
+		for i, row := range batch {
+			if i > 0 {
+				statement.WriteString(", ")
+			}
+			statement.WriteString("(" + placeholders(len(args)+1, len(row)) + ")")
+			args = append(args, row...)
+		}
+		statement.WriteString(" " + suffix)
+
+		if _, err := tx.ExecContext(ctx, statement.String(), args...); err != nil {
+			return errors.NewStorageError(op, s.path, msg, err)
+		}
+	}
+	return nil
+}
+
+// placeholders returns count comma-separated placeholders starting at $first
+func placeholders(first, count int) string {
+	var list strings.Builder
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			list.WriteString(", ")
+		}
+		fmt.Fprintf(&list, "$%d", first+i)
+	}
+	return list.String()
+}
+
+// unixNano converts a time to its stored form, NULL for the zero time
+func unixNano(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UnixNano()
+}
+
+// fromUnixNano converts a stored time back, the zero time for NULL
+func fromUnixNano(value sql.NullInt64) time.Time {
+	if !value.Valid {
+		return time.Time{}
+	}
+	return time.Unix(0, value.Int64)
+}
@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github-stars-notify/internal/errors"
+	"github-stars-notify/internal/github"
+)
+
+// filesImportedKey marks in the metadata table that the file storage was imported
+const filesImportedKey = "files_imported_at"
+
+// importFilesOnce imports the file storage of the data directory unless it
+// was imported before. The import runs in a single transaction, an interrupted
+// import is retried on the next start.
+func (s *SQLiteStorage) importFilesOnce(ctx context.Context) error {
+	return s.runOnce(ctx, "import_files", filesImportedKey, func(tx *sql.Tx) error {
+		_, err := s.importFiles(ctx, tx, s.dataDir)
+		return err
+	})
+}
+
+// ImportFiles imports the repositories, star event logs, forks, releases,
+// followers and repository states of a file storage directory, overwriting
+// data stored under the same names. It returns the number of repositories imported.
+func (s *sqlStorage) ImportFiles(ctx context.Context, dataDir string) (int, error) {
+	imported := 0
+	err := s.withTx(ctx, "import_files", func(tx *sql.Tx) error {
+		var err error
+		imported, err = s.importFiles(ctx, tx, dataDir)
+		return err
+	})
+	return imported, err
+}
+
+// importFiles imports a file storage directory within tx
+func (s *sqlStorage) importFiles(ctx context.Context, tx *sql.Tx, dataDir string) (int, error) {
+	files := NewFileStorage(dataDir)
+	imported := 0
+	err := readJSONFiles(dataDir, func(filename string, data []byte) error {
+		// Data files of repositories share the directory with other JSON files
+		var metadata RepoData
+		if json.Unmarshal(data, &metadata) != nil || metadata.Owner == "" || metadata.Repo == "" {
+			return nil
+		}
+		repoData, err := files.loadUnsafe(metadata.Owner, metadata.Repo)
+		if err != nil {
+			return err
+		}
+
+		id, err := s.ensureRepository(ctx, tx, repoData.Owner, repoData.Repo)
+		if err != nil {
+			return err
+		}
+		if err := s.replaceStargazers(ctx, tx, id, repoData.Stargazers, false, time.Now()); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE repositories SET last_check = $1 WHERE id = $2`, unixNano(repoData.LastCheck), id); err != nil {
+			return errors.NewStorageError("import_files", filename, "failed to import last check", err)
+		}
+
+		// The star event log, converted from the snapshot if the files have none
+		events, err := files.loadStarEventsUnsafe(repoData.Owner, repoData.Repo)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM star_events WHERE repository_id = $1`, id); err != nil {
+			return errors.NewStorageError("import_files", filename, "failed to replace star events", err)
+		}
+		for _, event := range events {
+			if err := s.insertStarEvents(ctx, tx, id, event.Kind, []github.Stargazer{event.Stargazer}, event.RecordedAt); err != nil {
+				return err
+			}
+		}
+		if err := s.insertMilestones(ctx, tx, id, repoData.Milestones); err != nil {
+			return err
+		}
+
+		imported++
+		return nil
+	})
+	if err != nil {
+		return imported, err
+	}
+
+	err = readJSONFiles(filepath.Join(dataDir, "forks"), func(filename string, data []byte) error {
+		var forkData ForkData
+		if err := json.Unmarshal(data, &forkData); err != nil {
+			return errors.NewStorageError("import_files", filename, "failed to unmarshal data", err)
+		}
+
+		id, err := s.ensureRepository(ctx, tx, forkData.Owner, forkData.Repo)
+		if err != nil {
+			return err
+		}
+		return s.replaceForks(ctx, tx, id, forkData.Forks, forkData.LastCheck)
+	})
+	if err != nil {
+		return imported, err
+	}
+
+	err = readJSONFiles(filepath.Join(dataDir, "releases"), func(filename string, data []byte) error {
+		var releaseData ReleaseData
+		if err := json.Unmarshal(data, &releaseData); err != nil {
+			return errors.NewStorageError("import_files", filename, "failed to unmarshal data", err)
+		}
+
+		id, err := s.ensureRepository(ctx, tx, releaseData.Owner, releaseData.Repo)
+		if err != nil {
+			return err
+		}
+		return s.insertReleases(ctx, tx, id, releaseData.ReleaseIDs, releaseData.Filter, releaseData.LastCheck)
+	})
+	if err != nil {
+		return imported, err
+	}
+
+	err = readJSONFiles(filepath.Join(dataDir, "followers"), func(filename string, data []byte) error {
+		var followerData FollowerData
+		if err := json.Unmarshal(data, &followerData); err != nil {
+			return errors.NewStorageError("import_files", filename, "failed to unmarshal data", err)
+		}
+		return s.replaceFollowers(ctx, tx, followerData.Login, followerData.Followers, followerData.LastCheck)
+	})
+	if err != nil {
+		return imported, err
+	}
+
+	states, err := files.loadRepositoryStatesUnsafe()
+	if err != nil {
+		return imported, err
+	}
+	for key, state := range states {
+		owner, repo := splitRepositoryKey(key)
+		id, err := s.ensureRepository(ctx, tx, owner, repo)
+		if err != nil {
+			return imported, err
+		}
+		if err := s.updateRepositoryState(ctx, tx, id, &state); err != nil {
+			return imported, err
+		}
+	}
+
+	return imported, nil
+}
+
+// readJSONFiles calls fn with the contents of every JSON file of dir, a
+// missing directory has no files
+func readJSONFiles(dir string, fn func(filename string, data []byte) error) error {
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return errors.NewStorageError("import_files", dir, "failed to list data files", err)
+	}
+
+	for _, filename := range filenames {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return errors.NewStorageError("import_files", filename, "failed to read data file", err)
+		}
+		if err := fn(filename, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitRepositoryKey splits a key built by repositoryKey
+func splitRepositoryKey(key string) (owner, repo string) {
+	owner, repo, _ = strings.Cut(key, "/")
+	return owner, repo
+}
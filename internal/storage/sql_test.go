@@ -0,0 +1,365 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github-stars-notify/internal/github"
+)
+
+// testSQLStargazers checks that a SQL storage stores stargazers, star events and removals
+func testSQLStargazers(t *testing.T, storage *sqlStorage) {
+	ctx := context.Background()
+
+	starredAt := time.Date(2024, time.March, 15, 10, 0, 0, 0, time.UTC)
+	stargazers := []github.Stargazer{
+		{Login: "b", ID: 2, StarredAt: starredAt},
+		{Login: "a", ID: 1, StarredAt: starredAt.Add(time.Hour), AvatarURL: "https://example.com/a.png"},
+	}
+
+	empty, err := storage.Load(ctx, "org", "repo")
+	if err != nil || !empty.LastCheck.IsZero() || len(empty.Stargazers) != 0 {
+		t.Fatalf("Expected empty data for an unknown repository, got %+v (%v)", empty, err)
+	}
+
+	if err := storage.Save(ctx, "org", "repo", stargazers); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	repoData, err := storage.Load(ctx, "org", "repo")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(repoData.Stargazers) != 2 || repoData.Stargazers[0].Login != "b" || repoData.Stargazers[1].AvatarURL != stargazers[1].AvatarURL {
+		t.Errorf("Expected stargazers in saved order, got %+v", repoData.Stargazers)
+	}
+	if !repoData.Stargazers[0].StarredAt.Equal(starredAt) {
+		t.Errorf("Expected starred at %v, got %v", starredAt, repoData.Stargazers[0].StarredAt)
+	}
+	if lastCheck, _ := storage.GetLastCheckTime(ctx, "org", "repo"); lastCheck.IsZero() {
+		t.Error("Expected the last check time to be set")
+	}
+
+	current := []github.Stargazer{stargazers[1], {Login: "c", ID: 3}}
+	newStargazers, err := storage.GetNewStargazers(ctx, "org", "repo", current)
+	if err != nil || len(newStargazers) != 1 || newStargazers[0].Login != "c" {
+		t.Errorf("Expected c to be new, got %v (%v)", newStargazers, err)
+	}
+
+	diff, err := storage.DiffStargazers(ctx, "org", "repo", current)
+	if err != nil || len(diff.Added) != 1 || len(diff.Removed) != 1 || diff.Removed[0].Login != "b" {
+		t.Errorf("Expected c added and b removed, got %+v (%v)", diff, err)
+	}
+
+	if err := storage.RecordRemovedStargazers(ctx, "org", "repo", diff.Removed); err != nil {
+		t.Fatalf("RecordRemovedStargazers failed: %v", err)
+	}
+	if err := storage.Save(ctx, "org", "repo", current); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	added, err := storage.AddStargazer(ctx, "org", "repo", github.Stargazer{Login: "d", ID: 4})
+	if err != nil || !added {
+		t.Errorf("Expected d to be added, got %v (%v)", added, err)
+	}
+	if added, _ := storage.AddStargazer(ctx, "org", "repo", github.Stargazer{Login: "d", ID: 4}); added {
+		t.Error("Expected a known stargazer not to be added again")
+	}
+
+	repoData, err = storage.Load(ctx, "org", "repo")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	logins := ""
+	for _, stargazer := range repoData.Stargazers {
+		logins += stargazer.Login
+	}
+	if logins != "acd" {
+		t.Errorf("Expected stargazers a, c and d, got %q", logins)
+	}
+	if len(repoData.RemovedStargazers) != 1 || repoData.RemovedStargazers[0].Login != "b" || repoData.RemovedStargazers[0].RemovedAt.IsZero() {
+		t.Errorf("Expected b to be recorded as removed, got %+v", repoData.RemovedStargazers)
+	}
+
+	// Every stargazer is logged, ordered by the star time GitHub reported
+	events, err := storage.StarEvents(ctx, StarEventQuery{Owner: "org", Repo: "repo", Kind: StarEventStarred})
+	if err != nil || len(events) != 4 || events[0].Stargazer.Login != "b" || !events[0].Stargazer.StarredAt.Equal(starredAt) {
+		t.Errorf("Expected 4 star events starting with b, got %+v (%v)", events, err)
+	}
+}
+
+// testSQLManyStargazers checks that saves larger than a batch keep the order
+// of stargazers and only touch the rows that changed
+func testSQLManyStargazers(t *testing.T, storage *sqlStorage) {
+	ctx := context.Background()
+
+	stargazers := make([]github.Stargazer, 2*storage.batchRows+10)
+	for i := range stargazers {
+		stargazers[i] = github.Stargazer{Login: fmt.Sprintf("user%d", i), ID: int64(i + 1)}
+	}
+	if err := storage.Save(ctx, "org", "repo", stargazers); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// An unstar near the start leaves the position of later stargazers alone
+	current := append(append([]github.Stargazer{}, stargazers[:5]...), stargazers[6:]...)
+	current = append(current, github.Stargazer{Login: "new", ID: 100000})
+	if err := storage.Save(ctx, "org", "repo", current); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	repoData, err := storage.Load(ctx, "org", "repo")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(repoData.Stargazers) != len(current) {
+		t.Fatalf("Expected %d stargazers, got %d", len(current), len(repoData.Stargazers))
+	}
+	for i, stargazer := range repoData.Stargazers {
+		if stargazer.ID != current[i].ID {
+			t.Fatalf("Expected stargazer %d to be %s, got %s", i, current[i].Login, stargazer.Login)
+		}
+	}
+
+	var position int64
+	if err := storage.db.QueryRowContext(ctx, `SELECT position FROM stargazers WHERE user_id = $1`, stargazers[6].ID).Scan(&position); err != nil || position != 6 {
+		t.Errorf("Expected the stargazer after the removed one to keep position 6, got %d (%v)", position, err)
+	}
+
+	events, err := storage.StarEvents(ctx, StarEventQuery{Owner: "org", Repo: "repo", Kind: StarEventStarred})
+	if err != nil || len(events) != len(stargazers)+1 {
+		t.Errorf("Expected %d star events, got %d (%v)", len(stargazers)+1, len(events), err)
+	}
+
+	newStargazers, err := storage.GetNewStargazers(ctx, "org", "repo", []github.Stargazer{stargazers[5], stargazers[7]})
+	if err != nil || len(newStargazers) != 1 || newStargazers[0].ID != stargazers[5].ID {
+		t.Errorf("Expected the removed stargazer to be new again, got %+v (%v)", newStargazers, err)
+	}
+
+	// Candidates and removals spanning several batches are looked up in chunks
+	newStargazers, err = storage.GetNewStargazers(ctx, "org", "repo", stargazers)
+	if err != nil || len(newStargazers) != 1 || newStargazers[0].ID != stargazers[5].ID {
+		t.Errorf("Expected only the removed stargazer to be new, got %+v (%v)", newStargazers, err)
+	}
+	diff, err := storage.DiffStargazers(ctx, "org", "repo", current[2*storage.batchRows:])
+	if err != nil {
+		t.Fatalf("DiffStargazers failed: %v", err)
+	}
+	if len(diff.Added) != 0 || len(diff.Removed) != 2*storage.batchRows {
+		t.Fatalf("Expected %d removed stargazers, got %d added and %d removed",
+			2*storage.batchRows, len(diff.Added), len(diff.Removed))
+	}
+	for i, stargazer := range diff.Removed {
+		if stargazer.ID != current[i].ID || stargazer.Login != current[i].Login {
+			t.Fatalf("Expected removed stargazer %d to be %s, got %+v", i, current[i].Login, stargazer)
+		}
+	}
+}
+
+// testSQLConcurrentWrites checks that concurrent writes of a repository record
+// each star event once and give added stargazers distinct positions
+func testSQLConcurrentWrites(t *testing.T, storage *sqlStorage) {
+	ctx := context.Background()
+
+	stargazers := []github.Stargazer{{Login: "a", ID: 1}, {Login: "b", ID: 2}, {Login: "c", ID: 3}}
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := storage.Save(ctx, "org", "repo", stargazers); err != nil {
+				t.Errorf("Save failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	events, err := storage.StarEvents(ctx, StarEventQuery{Owner: "org", Repo: "repo", Kind: StarEventStarred})
+	if err != nil || len(events) != len(stargazers) {
+		t.Errorf("Expected %d star events, got %d (%v)", len(stargazers), len(events), err)
+	}
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stargazer := github.Stargazer{Login: fmt.Sprintf("user%d", i), ID: int64(100 + i)}
+			if _, err := storage.AddStargazer(ctx, "org", "repo", stargazer); err != nil {
+				t.Errorf("AddStargazer failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	positions, err := storage.loadIDs(ctx, "test", `SELECT DISTINCT position FROM stargazers WHERE repository_id = $1`, mustFindRepository(t, storage, "org", "repo"))
+	if err != nil || len(positions) != len(stargazers)+8 {
+		t.Errorf("Expected %d distinct positions, got %v (%v)", len(stargazers)+8, positions, err)
+	}
+}
+
+// mustFindRepository returns the row ID of a stored repository
+func mustFindRepository(t *testing.T, storage *sqlStorage, owner, repo string) int64 {
+	t.Helper()
+
+	id, found, err := storage.findRepository(context.Background(), storage.db, owner, repo)
+	if err != nil || !found {
+		t.Fatalf("Expected repository %s/%s to be stored (%v)", owner, repo, err)
+	}
+	return id
+}
+
+// testSQLMilestonesForksReleasesFollowers checks that a SQL storage stores milestones, forks, releases and followers
+func testSQLMilestonesForksReleasesFollowers(t *testing.T, storage *sqlStorage) {
+	ctx := context.Background()
+
+	if err := storage.RecordMilestones(ctx, "org", "repo", []int{500, 100}); err != nil {
+		t.Fatalf("RecordMilestones failed: %v", err)
+	}
+	if err := storage.RecordMilestones(ctx, "org", "repo", []int{100}); err != nil {
+		t.Fatalf("RecordMilestones failed: %v", err)
+	}
+	repoData, _ := storage.Load(ctx, "org", "repo")
+	if len(repoData.Milestones) != 2 || repoData.Milestones[0] != 100 {
+		t.Errorf("Expected milestones [100 500], got %v", repoData.Milestones)
+	}
+
+	forks := []github.Repository{{ID: 1, FullName: "a/repo"}, {ID: 2, FullName: "b/repo", Topics: []string{"go"}}}
+	if err := storage.SaveForks(ctx, "org", "repo", forks[:1]); err != nil {
+		t.Fatalf("SaveForks failed: %v", err)
+	}
+	newForks, err := storage.GetNewForks(ctx, "org", "repo", forks)
+	if err != nil || len(newForks) != 1 || newForks[0].ID != 2 {
+		t.Errorf("Expected fork 2 to be new, got %v (%v)", newForks, err)
+	}
+	if err := storage.SaveForks(ctx, "org", "repo", forks); err != nil {
+		t.Fatalf("SaveForks failed: %v", err)
+	}
+	forkData, _ := storage.LoadForks(ctx, "org", "repo")
+	if len(forkData.Forks) != 2 || forkData.Forks[1].Topics[0] != "go" || forkData.LastCheck.IsZero() {
+		t.Errorf("Expected both forks stored, got %+v", forkData)
+	}
+
+	releases := []github.Release{{ID: 10}, {ID: 11}}
+	if err := storage.SaveReleases(ctx, "org", "repo", releases[:1], ReleaseFilter{}); err != nil {
+		t.Fatalf("SaveReleases failed: %v", err)
+	}
+	newReleases, err := storage.GetNewReleases(ctx, "org", "repo", releases)
+	if err != nil || len(newReleases) != 1 || newReleases[0].ID != 11 {
+		t.Errorf("Expected release 11 to be new, got %v (%v)", newReleases, err)
+	}
+	if releaseData, _ := storage.LoadReleases(ctx, "org", "repo"); releaseData.Filter == nil || *releaseData.Filter != (ReleaseFilter{}) {
+		t.Errorf("Expected the release filter to be recorded, got %+v", releaseData)
+	}
+	if err := storage.SaveReleases(ctx, "org", "repo", releases, ReleaseFilter{Drafts: true}); err != nil {
+		t.Fatalf("SaveReleases failed: %v", err)
+	}
+	if releaseData, _ := storage.LoadReleases(ctx, "org", "repo"); releaseData.Filter == nil || !releaseData.Filter.Drafts {
+		t.Errorf("Expected the release filter to be updated, got %+v", releaseData)
+	}
+
+	followers := []github.Stargazer{{Login: "x", ID: 1}, {Login: "y", ID: 2}}
+	if err := storage.SaveFollowers(ctx, "org", followers); err != nil {
+		t.Fatalf("SaveFollowers failed: %v", err)
+	}
+	diff, err := storage.DiffFollowers(ctx, "org", []github.Stargazer{followers[1], {Login: "z", ID: 3}})
+	if err != nil || len(diff.Added) != 1 || diff.Added[0].Login != "z" || len(diff.Removed) != 1 || diff.Removed[0].Login != "x" {
+		t.Errorf("Expected z added and x removed, got %+v (%v)", diff, err)
+	}
+}
+
+// testSQLRenameRepository checks that a SQL storage moves repository data to a new name
+func testSQLRenameRepository(t *testing.T, storage *sqlStorage) {
+	ctx := context.Background()
+
+	if err := storage.Save(ctx, "org", "old", []github.Stargazer{{Login: "a", ID: 1}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := storage.SetRepositoryState(ctx, "org", "old", &RepositoryState{ID: 42, NotFoundCount: 1}); err != nil {
+		t.Fatalf("SetRepositoryState failed: %v", err)
+	}
+
+	if err := storage.RenameRepository(ctx, "org", "old", "neworg", "new"); err != nil {
+		t.Fatalf("RenameRepository failed: %v", err)
+	}
+
+	moved, _ := storage.Load(ctx, "neworg", "new")
+	if len(moved.Stargazers) != 1 {
+		t.Errorf("Expected the stargazers to move to the new name, got %+v", moved)
+	}
+	if old, _ := storage.Load(ctx, "org", "old"); len(old.Stargazers) != 0 {
+		t.Errorf("Expected no stargazers under the old name, got %+v", old)
+	}
+
+	oldState, _ := storage.GetRepositoryState(ctx, "org", "old")
+	if oldState.RenamedTo != "neworg/new" || oldState.ID != 42 {
+		t.Errorf("Expected the old name to point at the new one, got %+v", oldState)
+	}
+	newState, _ := storage.GetRepositoryState(ctx, "neworg", "new")
+	if newState.ID != 42 || newState.NotFoundCount != 1 || newState.RenamedTo != "" {
+		t.Errorf("Expected the state to move to the new name, got %+v", newState)
+	}
+
+	// A name that already has data gets the rows of the old name merged in,
+	// keeping its own rows for the same keys
+	if err := storage.Save(ctx, "org", "other", []github.Stargazer{{Login: "a", ID: 1}, {Login: "b", ID: 2}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := storage.RecordMilestones(ctx, "org", "other", []int{1, 2}); err != nil {
+		t.Fatalf("RecordMilestones failed: %v", err)
+	}
+	if err := storage.SaveForks(ctx, "org", "other", []github.Repository{{ID: 10, FullName: "x/other"}}); err != nil {
+		t.Fatalf("SaveForks failed: %v", err)
+	}
+	if err := storage.SaveReleases(ctx, "org", "other", []github.Release{{ID: 20}}, ReleaseFilter{Prereleases: true}); err != nil {
+		t.Fatalf("SaveReleases failed: %v", err)
+	}
+	if err := storage.RecordMilestones(ctx, "neworg", "new", []int{1}); err != nil {
+		t.Fatalf("RecordMilestones failed: %v", err)
+	}
+	if err := storage.SaveReleases(ctx, "neworg", "new", []github.Release{{ID: 21}}, ReleaseFilter{}); err != nil {
+		t.Fatalf("SaveReleases failed: %v", err)
+	}
+
+	if err := storage.RenameRepository(ctx, "org", "other", "neworg", "new"); err != nil {
+		t.Fatalf("RenameRepository failed: %v", err)
+	}
+
+	merged, err := storage.Load(ctx, "neworg", "new")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(merged.Stargazers) != 2 || merged.Stargazers[0].ID != 1 || merged.Stargazers[1].ID != 2 {
+		t.Errorf("Expected the stargazers merged after the existing ones, got %+v", merged.Stargazers)
+	}
+	if len(merged.Milestones) != 2 {
+		t.Errorf("Expected the milestones merged, got %v", merged.Milestones)
+	}
+	if forkData, _ := storage.LoadForks(ctx, "neworg", "new"); len(forkData.Forks) != 1 {
+		t.Errorf("Expected the forks moved, got %+v", forkData)
+	}
+	releaseData, _ := storage.LoadReleases(ctx, "neworg", "new")
+	if len(releaseData.ReleaseIDs) != 2 || releaseData.Filter == nil || releaseData.Filter.Prereleases {
+		t.Errorf("Expected the releases merged under the filter of the new name, got %+v", releaseData)
+	}
+	events, err := storage.StarEvents(ctx, StarEventQuery{Owner: "neworg", Repo: "new"})
+	if err != nil || len(events) != 3 {
+		t.Errorf("Expected the star events of both names, got %d (%v)", len(events), err)
+	}
+
+	old, _ := storage.Load(ctx, "org", "other")
+	if len(old.Stargazers) != 0 || len(old.Milestones) != 0 || !old.LastCheck.IsZero() {
+		t.Errorf("Expected no data left under the old name, got %+v", old)
+	}
+	if events, _ := storage.StarEvents(ctx, StarEventQuery{Owner: "org", Repo: "other"}); len(events) != 0 {
+		t.Errorf("Expected no star events left under the old name, got %+v", events)
+	}
+	if oldForks, _ := storage.LoadForks(ctx, "org", "other"); len(oldForks.Forks) != 0 {
+		t.Errorf("Expected no forks left under the old name, got %+v", oldForks)
+	}
+	if oldReleases, _ := storage.LoadReleases(ctx, "org", "other"); len(oldReleases.ReleaseIDs) != 0 || oldReleases.Filter != nil {
+		t.Errorf("Expected no releases left under the old name, got %+v", oldReleases)
+	}
+}
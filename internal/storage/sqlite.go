@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+
+	"github-stars-notify/internal/errors"
+
+	_ "modernc.org/sqlite" // Pure Go driver, keeps builds CGO-free
+)
+
+// SQLiteFilename is the name of the database file in the storage directory
+const SQLiteFilename = "stars.db"
+
+// starEventsSeededKey marks in the metadata table that the stargazers stored
+// before the star event log existed were converted into it
+const starEventsSeededKey = "star_events_seeded_at"
+
+// sqliteSchema creates the tables of the SQLite storage. Times are stored as
+// Unix nanoseconds, NULL for the zero time.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS repositories (
+	id                  INTEGER PRIMARY KEY,
+	owner               TEXT NOT NULL,
+	repo                TEXT NOT NULL,
+	last_check          INTEGER,
+	forks_checked_at    INTEGER,
+	releases_checked_at INTEGER,
+	github_id           INTEGER NOT NULL DEFAULT 0,
+	renamed_to          TEXT NOT NULL DEFAULT '',
+	not_found_count     INTEGER NOT NULL DEFAULT 0,
+	suspended_at        INTEGER,
+	UNIQUE (owner, repo)
+);
+
+CREATE TABLE IF NOT EXISTS stargazers (
+	repository_id INTEGER NOT NULL REFERENCES repositories (id) ON DELETE CASCADE,
+	user_id       INTEGER NOT NULL,
+	position      INTEGER NOT NULL,
+	login         TEXT NOT NULL,
+	node_id       TEXT NOT NULL DEFAULT '',
+	avatar_url    TEXT NOT NULL DEFAULT '',
+	html_url      TEXT NOT NULL DEFAULT '',
+	starred_at    INTEGER,
+	PRIMARY KEY (repository_id, user_id)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS star_events (
+	id            INTEGER PRIMARY KEY,
+	repository_id INTEGER NOT NULL REFERENCES repositories (id) ON DELETE CASCADE,
+	kind          TEXT NOT NULL,
+	user_id       INTEGER NOT NULL,
+	login         TEXT NOT NULL,
+	node_id       TEXT NOT NULL DEFAULT '',
+	avatar_url    TEXT NOT NULL DEFAULT '',
+	html_url      TEXT NOT NULL DEFAULT '',
+	starred_at    INTEGER,
+	recorded_at   INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS star_events_repository ON star_events (repository_id, kind, recorded_at);
+CREATE INDEX IF NOT EXISTS star_events_login ON star_events (repository_id, lower(login));
+
+CREATE TABLE IF NOT EXISTS milestones (
+	repository_id INTEGER NOT NULL REFERENCES repositories (id) ON DELETE CASCADE,
+	stars         INTEGER NOT NULL,
+	PRIMARY KEY (repository_id, stars)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS forks (
+	repository_id INTEGER NOT NULL REFERENCES repositories (id) ON DELETE CASCADE,
+	fork_id       INTEGER NOT NULL,
+	data          TEXT NOT NULL,
+	PRIMARY KEY (repository_id, fork_id)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS releases (
+	repository_id INTEGER NOT NULL REFERENCES repositories (id) ON DELETE CASCADE,
+	release_id    INTEGER NOT NULL,
+	PRIMARY KEY (repository_id, release_id)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS release_filters (
+	repository_id INTEGER PRIMARY KEY REFERENCES repositories (id) ON DELETE CASCADE,
+	prereleases   INTEGER NOT NULL,
+	drafts        INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS accounts (
+	id         INTEGER PRIMARY KEY,
+	login      TEXT NOT NULL UNIQUE,
+	last_check INTEGER
+);
+
+CREATE TABLE IF NOT EXISTS followers (
+	account_id INTEGER NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+	user_id    INTEGER NOT NULL,
+	position   INTEGER NOT NULL,
+	login      TEXT NOT NULL,
+	node_id    TEXT NOT NULL DEFAULT '',
+	avatar_url TEXT NOT NULL DEFAULT '',
+	html_url   TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (account_id, user_id)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS metadata (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS user_profiles (
+	key        TEXT PRIMARY KEY,
+	profile    TEXT NOT NULL,
+	fetched_at INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS http_responses (
+	key           TEXT PRIMARY KEY,
+	etag          TEXT NOT NULL DEFAULT '',
+	last_modified TEXT NOT NULL DEFAULT '',
+	link          TEXT NOT NULL DEFAULT '',
+	body          BLOB NOT NULL,
+	used_at       INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS http_responses_used_at ON http_responses (used_at);
+`
+
+// SQLiteStorage implements Storage interface using a SQLite database in the
+// data directory
+type SQLiteStorage struct {
+	sqlStorage
+	dataDir string
+}
+
+// NewSQLiteStorage creates a new SQLite storage instance keeping its database
+// in dataDir. The database is opened by Initialize.
+func NewSQLiteStorage(dataDir string) *SQLiteStorage {
+	if dataDir == "" {
+		dataDir = "./data"
+	}
+
+	return &SQLiteStorage{
+		// The driver binds the parameters of a statement in quadratic
+		// time and writes need no round trip, small batches are faster.
+		// Rows need no lock, the single connection serializes transactions.
+		sqlStorage: sqlStorage{path: filepath.Join(dataDir, SQLiteFilename), batchRows: 10, cachedResponses: MaxCachedResponses},
+		dataDir:    dataDir,
+	}
+}
+
+// Initialize opens the database, creates its tables and imports the data of
+// the file storage found in the data directory the first time. Stargazers of
+// databases created before the star event log are converted into it once.
+func (s *SQLiteStorage) Initialize(ctx context.Context) error {
+	if err := os.MkdirAll(s.dataDir, 0755); err != nil {
+		return errors.NewStorageError("initialize", s.dataDir,
+			"failed to create data directory", err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+s.path+
+		"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return errors.NewStorageError("initialize", s.path,
+			"failed to open database", err)
+	}
+	// A single connection serializes writers instead of failing them with SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.ExecContext(ctx, sqliteSchema); err != nil {
+		db.Close()
+		return errors.NewStorageError("initialize", s.path,
+			"failed to create tables", err)
+	}
+	s.db = db
+
+	if err := s.importFilesOnce(ctx); err != nil {
+		return err
+	}
+	return s.runOnce(ctx, "seed_star_events", starEventsSeededKey, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, seedStarEventsSQL); err != nil {
+			return errors.NewStorageError("seed_star_events", s.path, "failed to seed star events", err)
+		}
+		return nil
+	})
+}
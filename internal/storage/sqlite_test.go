@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github-stars-notify/internal/github"
+)
+
+// newTestSQLiteStorage creates an initialized SQLite storage in a temporary directory
+func newTestSQLiteStorage(t *testing.T, dataDir string) *SQLiteStorage {
+	t.Helper()
+
+	storage := NewSQLiteStorage(dataDir)
+	if err := storage.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	t.Cleanup(func() { storage.Close() })
+	return storage
+}
+
+func TestSQLiteStargazers(t *testing.T) {
+	testSQLStargazers(t, &newTestSQLiteStorage(t, t.TempDir()).sqlStorage)
+}
+
+func TestSQLiteManyStargazers(t *testing.T) {
+	testSQLManyStargazers(t, &newTestSQLiteStorage(t, t.TempDir()).sqlStorage)
+}
+
+func TestSQLiteConcurrentWrites(t *testing.T) {
+	testSQLConcurrentWrites(t, &newTestSQLiteStorage(t, t.TempDir()).sqlStorage)
+}
+
+func TestSQLiteMilestonesForksReleasesFollowers(t *testing.T) {
+	testSQLMilestonesForksReleasesFollowers(t, &newTestSQLiteStorage(t, t.TempDir()).sqlStorage)
+}
+
+func TestSQLiteRenameRepository(t *testing.T) {
+	testSQLRenameRepository(t, &newTestSQLiteStorage(t, t.TempDir()).sqlStorage)
+}
+
+func TestSQLiteStargazerSummary(t *testing.T) {
+	testStargazerSummary(t, newTestSQLiteStorage(t, t.TempDir()))
+}
+
+func TestSQLiteRemovedStargazersCap(t *testing.T) {
+	testRemovedStargazersCap(t, newTestSQLiteStorage(t, t.TempDir()))
+}
+
+func TestSQLiteResponseCache(t *testing.T) {
+	storage := newTestSQLiteStorage(t, t.TempDir())
+	storage.cachedResponses = 3
+	testResponseCache(t, storage)
+}
+
+func TestSQLiteUserProfiles(t *testing.T) {
+	testUserProfiles(t, newTestSQLiteStorage(t, t.TempDir()))
+}
+
+func TestSQLiteStarEvents(t *testing.T) {
+	testStarEventLog(t, newTestSQLiteStorage(t, t.TempDir()))
+}
+
+func TestSQLiteSeedStarEvents(t *testing.T) {
+	dataDir := t.TempDir()
+	ctx := context.Background()
+
+	// A database written before every stargazer was logged
+	storage := newTestSQLiteStorage(t, dataDir)
+	if err := storage.Save(ctx, "org", "repo", []github.Stargazer{{Login: "a", ID: 1}, {Login: "b", ID: 2}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := storage.db.Exec(`DELETE FROM star_events`); err != nil {
+		t.Fatalf("Failed to delete star events: %v", err)
+	}
+	if _, err := storage.db.Exec(`DELETE FROM metadata WHERE key = $1`, starEventsSeededKey); err != nil {
+		t.Fatalf("Failed to delete metadata: %v", err)
+	}
+	storage.Close()
+
+	reopened := newTestSQLiteStorage(t, dataDir)
+	events, err := reopened.StarEvents(ctx, StarEventQuery{Owner: "org", Repo: "repo"})
+	if err != nil || eventLogins(events) != "a,b" || events[0].RecordedAt.IsZero() {
+		t.Errorf("Expected the stargazers converted into star events, got %+v (%v)", events, err)
+	}
+}
+
+func TestSQLiteImportFiles(t *testing.T) {
+	dataDir := t.TempDir()
+	ctx := context.Background()
+
+	files := NewFileStorage(dataDir)
+	if err := files.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if err := files.Save(ctx, "org", "my_repo", []github.Stargazer{{Login: "a", ID: 1}, {Login: "b", ID: 2}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := files.RecordRemovedStargazers(ctx, "org", "my_repo", []github.Stargazer{{Login: "c", ID: 3}}); err != nil {
+		t.Fatalf("RecordRemovedStargazers failed: %v", err)
+	}
+	if err := files.RecordMilestones(ctx, "org", "my_repo", []int{100}); err != nil {
+		t.Fatalf("RecordMilestones failed: %v", err)
+	}
+	if err := files.SaveForks(ctx, "org", "my_repo", []github.Repository{{ID: 5}}); err != nil {
+		t.Fatalf("SaveForks failed: %v", err)
+	}
+	if err := files.SaveReleases(ctx, "org", "my_repo", []github.Release{{ID: 7}}, ReleaseFilter{Prereleases: true}); err != nil {
+		t.Fatalf("SaveReleases failed: %v", err)
+	}
+	if err := files.SaveFollowers(ctx, "org", []github.Stargazer{{Login: "f", ID: 9}}); err != nil {
+		t.Fatalf("SaveFollowers failed: %v", err)
+	}
+	if err := files.SetRepositoryState(ctx, "org", "my_repo", &RepositoryState{ID: 42}); err != nil {
+		t.Fatalf("SetRepositoryState failed: %v", err)
+	}
+
+	// The files are imported on the first start
+	storage := newTestSQLiteStorage(t, dataDir)
+
+	repoData, err := storage.Load(ctx, "org", "my_repo")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(repoData.Stargazers) != 2 || repoData.LastCheck.IsZero() ||
+		len(repoData.RemovedStargazers) != 1 || len(repoData.Milestones) != 1 {
+		t.Errorf("Expected the repository data to be imported, got %+v", repoData)
+	}
+	if forkData, _ := storage.LoadForks(ctx, "org", "my_repo"); len(forkData.Forks) != 1 {
+		t.Errorf("Expected the forks to be imported, got %+v", forkData)
+	}
+	if releaseData, _ := storage.LoadReleases(ctx, "org", "my_repo"); len(releaseData.ReleaseIDs) != 1 || releaseData.Filter == nil || !releaseData.Filter.Prereleases {
+		t.Errorf("Expected the releases to be imported, got %+v", releaseData)
+	}
+	if followerData, _ := storage.LoadFollowers(ctx, "org"); len(followerData.Followers) != 1 {
+		t.Errorf("Expected the followers to be imported, got %+v", followerData)
+	}
+	if state, _ := storage.GetRepositoryState(ctx, "org", "my_repo"); state.ID != 42 {
+		t.Errorf("Expected the repository state to be imported, got %+v", state)
+	}
+
+	// Later starts keep the database as it is
+	if err := storage.Save(ctx, "org", "my_repo", []github.Stargazer{{Login: "a", ID: 1}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	storage.Close()
+
+	reopened := newTestSQLiteStorage(t, dataDir)
+	if repoData, _ := reopened.Load(ctx, "org", "my_repo"); len(repoData.Stargazers) != 1 {
+		t.Errorf("Expected the files not to be imported again, got %d stargazers", len(repoData.Stargazers))
+	}
+}
@@ -0,0 +1,507 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github-stars-notify/internal/errors"
+	"github-stars-notify/internal/github"
+)
+
+// Headers of the compact stargazer files, followed by fixed width fields: the
+// number of IDs of the ID set, the number and length in bytes of the records
+const (
+	stargazerIDsMagic     = "SGID\x01"
+	stargazerRecordsMagic = "SGRC\x01"
+)
+
+// Smallest encodings of an ID delta and of a record, which bound the number of
+// entries a file of a given size can hold
+const (
+	minStargazerIDSize     = 1
+	minStargazerRecordSize = 6
+)
+
+// stargazerRecordsHeaderSize is the size of the header of the stargazer records
+const stargazerRecordsHeaderSize = int64(len(stargazerRecordsMagic) + 16)
+
+// getStargazerIDsFilename generates the filename of a repository's stargazer
+// ID set: the IDs sorted ascending, each stored as the uvarint delta to the
+// previous one, which takes one to three bytes for most IDs.
+func (s *FileStorage) getStargazerIDsFilename(owner, repo string) string {
+	return filepath.Join(s.dataDir, "stargazers", fmt.Sprintf("%s_%s.ids", owner, repo))
+}
+
+// getStargazerRecordsFilename generates the filename of a repository's
+// stargazer records, one per stargazer in saved order: ID, star time and the
+// profile fields, without JSON field names or indentation.
+func (s *FileStorage) getStargazerRecordsFilename(owner, repo string) string {
+	return filepath.Join(s.dataDir, "stargazers", fmt.Sprintf("%s_%s.dat", owner, repo))
+}
+
+// hasStargazerFilesUnsafe reports whether the stargazers of a repository are
+// stored in the compact files rather than in its data file. The ID set is
+// always written before the records, so it is never behind them.
+func (s *FileStorage) hasStargazerFilesUnsafe(owner, repo string) bool {
+	_, err := os.Stat(s.getStargazerRecordsFilename(owner, repo))
+	return err == nil
+}
+
+// writeStargazersUnsafe streams the stargazers of a repository into its
+// compact files without acquiring a lock (for internal use). The ID set is
+// written first: a crash before the records are renamed leaves the ID set of
+// the new stargazers with the records of the previous ones, or no records at
+// all for data files holding them inline, which keep being used.
+func (s *FileStorage) writeStargazersUnsafe(owner, repo string, stargazers []github.Stargazer) error {
+	ids := make([]int64, len(stargazers))
+	for i, stargazer := range stargazers {
+		ids[i] = stargazer.ID
+	}
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	if err := s.writeStargazerIDsUnsafe("save", owner, repo, ids); err != nil {
+		return err
+	}
+
+	var length int
+	var record []byte
+	for _, stargazer := range stargazers {
+		record = appendStargazerRecord(record[:0], stargazer)
+		length += len(record)
+	}
+
+	return writeFileAtomic("save", s.getStargazerRecordsFilename(owner, repo), func(w *bufio.Writer) error {
+		writeHeader(w, stargazerRecordsMagic, uint64(len(stargazers)), uint64(length))
+		for _, stargazer := range stargazers {
+			w.Write(appendStargazerRecord(w.AvailableBuffer(), stargazer))
+		}
+		return nil
+	})
+}
+
+// writeStargazerIDsUnsafe writes the sorted stargazer IDs of a repository into
+// its ID set without acquiring a lock (for internal use)
+func (s *FileStorage) writeStargazerIDsUnsafe(op, owner, repo string, ids []int64) error {
+	return writeFileAtomic(op, s.getStargazerIDsFilename(owner, repo), func(w *bufio.Writer) error {
+		writeHeader(w, stargazerIDsMagic, uint64(len(ids)))
+		var previous int64
+		for _, id := range ids {
+			writeUvarint(w, uint64(id-previous))
+			previous = id
+		}
+		return nil
+	})
+}
+
+// appendStargazerRecordsUnsafe appends records to the compact stargazer
+// records of a repository without acquiring a lock (for internal use). The
+// records are written after the counted ones before the header counts them,
+// so records cut short by a crash are never read back. op names the storage
+// operation in errors.
+func (s *FileStorage) appendStargazerRecordsUnsafe(op, owner, repo string, stargazers []github.Stargazer) error {
+	if len(stargazers) == 0 {
+		return nil
+	}
+
+	filename := s.getStargazerRecordsFilename(owner, repo)
+
+	file, err := os.OpenFile(filename, os.O_RDWR, 0)
+	if err != nil {
+		return errors.NewStorageError(op, filename,
+			"failed to open stargazer records", err)
+	}
+	defer file.Close()
+
+	_, count, length, err := readRecordsHeader(file)
+	if err != nil {
+		return errors.NewStorageError(op, filename,
+			"failed to read stargazer records", err)
+	}
+
+	var records []byte
+	for _, stargazer := range stargazers {
+		records = appendStargazerRecord(records, stargazer)
+	}
+	if _, err := file.WriteAt(records, stargazerRecordsHeaderSize+length); err != nil {
+		return errors.NewStorageError(op, filename,
+			"failed to append stargazer records", err)
+	}
+
+	fields := binary.LittleEndian.AppendUint64(nil, uint64(count+len(stargazers)))
+	fields = binary.LittleEndian.AppendUint64(fields, uint64(length)+uint64(len(records)))
+	if _, err := file.WriteAt(fields, int64(len(stargazerRecordsMagic))); err != nil {
+		return errors.NewStorageError(op, filename,
+			"failed to update stargazer records", err)
+	}
+	return nil
+}
+
+// countStargazersUnsafe reads the number of stargazers of a repository from
+// the header of its ID set without acquiring a lock (for internal use)
+func (s *FileStorage) countStargazersUnsafe(owner, repo string) (int, error) {
+	filename := s.getStargazerIDsFilename(owner, repo)
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return 0, errors.NewStorageError("load", filename,
+			"failed to open stargazer IDs", err)
+	}
+	defer file.Close()
+
+	_, count, err := readIDsHeader(file)
+	if err != nil {
+		return 0, errors.NewStorageError("load", filename,
+			"failed to read stargazer IDs", err)
+	}
+	return count, nil
+}
+
+// readStargazerIDsUnsafe reads the ID set of a repository without acquiring a
+// lock (for internal use)
+func (s *FileStorage) readStargazerIDsUnsafe(op, owner, repo string) ([]int64, error) {
+	filename := s.getStargazerIDsFilename(owner, repo)
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.NewStorageError(op, filename,
+			"failed to open stargazer IDs", err)
+	}
+	defer file.Close()
+
+	r, count, err := readIDsHeader(file)
+	if err != nil {
+		return nil, errors.NewStorageError(op, filename,
+			"failed to read stargazer IDs", err)
+	}
+
+	ids := make([]int64, count)
+	var id int64
+	for i := range ids {
+		delta, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, errors.NewStorageError(op, filename,
+				"failed to read stargazer ID", err)
+		}
+		id += int64(delta)
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// readStargazersUnsafe streams the stargazer records of a repository without
+// acquiring a lock (for internal use). Only the stargazers for which keep
+// returns true are returned, all of them if keep is nil.
+func (s *FileStorage) readStargazersUnsafe(owner, repo string, keep func(id int64) bool) ([]github.Stargazer, error) {
+	filename := s.getStargazerRecordsFilename(owner, repo)
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.NewStorageError("load", filename,
+			"failed to open stargazer records", err)
+	}
+	defer file.Close()
+
+	r, count, _, err := readRecordsHeader(file)
+	if err != nil {
+		return nil, errors.NewStorageError("load", filename,
+			"failed to read stargazer records", err)
+	}
+
+	capacity := count
+	if keep != nil {
+		capacity = 0
+	}
+	stargazers := make([]github.Stargazer, 0, capacity)
+	var scratch []byte
+	for i := 0; i < count; i++ {
+		var stargazer github.Stargazer
+		kept, err := readStargazerRecord(r, &stargazer, &scratch, keep)
+		if err != nil {
+			return nil, errors.NewStorageError("load", filename,
+				"failed to read stargazer record", err)
+		}
+		if kept {
+			stargazers = append(stargazers, stargazer)
+		}
+	}
+	return stargazers, nil
+}
+
+// diffStoredStargazersUnsafe compares current stargazers with the stored ones
+// of a repository without acquiring a lock (for internal use). repoData is its
+// loaded metadata, which holds the stargazers of data files written before the
+// compact files. The profiles of removed stargazers are only read back from the
+// compact files if withRemoved is set.
+func (s *FileStorage) diffStoredStargazersUnsafe(owner, repo string, repoData *RepoData, current []github.Stargazer, withRemoved bool) (*StargazerDiff, error) {
+	if !s.hasStargazerFilesUnsafe(owner, repo) {
+		return diffStargazers(repoData.Stargazers, current), nil
+	}
+
+	added, removedIDs, err := s.diffStargazerIDsUnsafe(owner, repo, current)
+	if err != nil {
+		return nil, err
+	}
+	diff := &StargazerDiff{Added: added}
+	if !withRemoved || len(removedIDs) == 0 {
+		return diff, nil
+	}
+
+	removed := make(map[int64]bool, len(removedIDs))
+	for _, id := range removedIDs {
+		removed[id] = true
+	}
+	diff.Removed, err = s.readStargazersUnsafe(owner, repo, func(id int64) bool { return removed[id] })
+	if err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// diffStargazerIDsUnsafe compares current stargazers with the stored ID set of
+// a repository without acquiring a lock (for internal use). It walks the
+// sorted set alongside the sorted current IDs, so only the current IDs are
+// held in memory. It returns the current stargazers missing from the set and
+// the stored IDs missing from current.
+func (s *FileStorage) diffStargazerIDsUnsafe(owner, repo string, current []github.Stargazer) (added []github.Stargazer, removed []int64, err error) {
+	filename := s.getStargazerIDsFilename(owner, repo)
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, errors.NewStorageError("diff", filename,
+			"failed to open stargazer IDs", err)
+	}
+	defer file.Close()
+
+	r, count, err := readIDsHeader(file)
+	if err != nil {
+		return nil, nil, errors.NewStorageError("diff", filename,
+			"failed to read stargazer IDs", err)
+	}
+
+	currentIDs := make([]int64, len(current))
+	for i, stargazer := range current {
+		currentIDs[i] = stargazer.ID
+	}
+	slices.Sort(currentIDs)
+	currentIDs = slices.Compact(currentIDs)
+
+	// stored[i] reports whether currentIDs[i] is in the set
+	stored := make([]bool, len(currentIDs))
+	var id int64
+	next := 0
+	for i := 0; i < count; i++ {
+		delta, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, nil, errors.NewStorageError("diff", filename,
+				"failed to read stargazer ID", err)
+		}
+		id += int64(delta)
+
+		for next < len(currentIDs) && currentIDs[next] < id {
+			next++
+		}
+		if next < len(currentIDs) && currentIDs[next] == id {
+			stored[next] = true
+		} else {
+			removed = append(removed, id)
+		}
+	}
+
+	for _, stargazer := range current {
+		if i, _ := slices.BinarySearch(currentIDs, stargazer.ID); !stored[i] {
+			added = append(added, stargazer)
+		}
+	}
+	return added, removed, nil
+}
+
+// writeFileAtomic streams a file through write into a temporary file, renamed
+// over filename once complete. op names the storage operation in errors.
+func writeFileAtomic(op, filename string, write func(w *bufio.Writer) error) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return errors.NewStorageError(op, filepath.Dir(filename),
+			"failed to create data directory", err)
+	}
+
+	tempFile := filename + ".tmp"
+	file, err := os.Create(tempFile)
+	if err != nil {
+		return errors.NewStorageError(op, tempFile,
+			"failed to create temporary file", err)
+	}
+
+	w := bufio.NewWriterSize(file, 64*1024)
+	err = write(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tempFile)
+		return errors.NewStorageError(op, tempFile,
+			"failed to write temporary file", err)
+	}
+
+	if err := os.Rename(tempFile, filename); err != nil {
+		os.Remove(tempFile)
+		return errors.NewStorageError(op, filename,
+			"failed to rename temporary file", err)
+	}
+	return nil
+}
+
+// writeHeader writes the magic of a compact file and its fixed width fields,
+// which appends update in place. Write errors are reported by the final flush
+// of the buffered writer.
+func writeHeader(w *bufio.Writer, magic string, fields ...uint64) {
+	w.WriteString(magic)
+	for _, field := range fields {
+		w.Write(binary.LittleEndian.AppendUint64(w.AvailableBuffer(), field))
+	}
+}
+
+// readHeader checks the magic of an open compact file and reads its fixed
+// width fields. It returns a reader positioned after the header and the size
+// of the rest of the file.
+func readHeader(file *os.File, magic string, fields []uint64) (*bufio.Reader, int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r := bufio.NewReader(file)
+	header := make([]byte, len(magic)+8*len(fields))
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, 0, err
+	}
+	if string(header[:len(magic)]) != magic {
+		return nil, 0, fmt.Errorf("unknown file format %q", header[:len(magic)])
+	}
+	for i := range fields {
+		fields[i] = binary.LittleEndian.Uint64(header[len(magic)+8*i:])
+	}
+	return r, info.Size() - int64(len(header)), nil
+}
+
+// readIDsHeader reads the header of an open ID set and returns a reader
+// positioned after it and the number of IDs
+func readIDsHeader(file *os.File) (*bufio.Reader, int, error) {
+	var fields [1]uint64
+	r, size, err := readHeader(file, stargazerIDsMagic, fields[:])
+	if err != nil {
+		return nil, 0, err
+	}
+	count, err := checkCount(fields[0], size, minStargazerIDSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	return r, count, nil
+}
+
+// readRecordsHeader reads the header of open stargazer records and returns a
+// reader positioned after it, the number of records and their length in bytes
+func readRecordsHeader(file *os.File) (*bufio.Reader, int, int64, error) {
+	var fields [2]uint64
+	r, size, err := readHeader(file, stargazerRecordsMagic, fields[:])
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if fields[1] > uint64(size) {
+		return nil, 0, 0, fmt.Errorf("%d bytes of records in %d bytes", fields[1], size)
+	}
+	count, err := checkCount(fields[0], int64(fields[1]), minStargazerRecordSize)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return r, count, int64(fields[1]), nil
+}
+
+// checkCount returns the number of entries of a header if that many entries
+// of at least minSize bytes fit in size bytes, so a damaged header cannot
+// cause a huge allocation
+func checkCount(count uint64, size int64, minSize int) (int, error) {
+	if count > uint64(size)/uint64(minSize) {
+		return 0, fmt.Errorf("%d entries in %d bytes", count, size)
+	}
+	return int(count), nil
+}
+
+// appendStargazerRecord appends the ID, star time (Unix nanoseconds, 0 for the
+// zero time) and length-prefixed profile fields of a stargazer to buf
+func appendStargazerRecord(buf []byte, stargazer github.Stargazer) []byte {
+	buf = binary.AppendUvarint(buf, uint64(stargazer.ID))
+	var starredAt int64
+	if !stargazer.StarredAt.IsZero() {
+		starredAt = stargazer.StarredAt.UnixNano()
+	}
+	buf = binary.AppendVarint(buf, starredAt)
+	for _, field := range [...]string{stargazer.Login, stargazer.NodeID, stargazer.AvatarURL, stargazer.HTMLURL} {
+		buf = binary.AppendUvarint(buf, uint64(len(field)))
+		buf = append(buf, field...)
+	}
+	return buf
+}
+
+// readStargazerRecord reads a record written by appendStargazerRecord into
+// stargazer if keep is nil or returns true for its ID, and skips it otherwise.
+// The profile fields are read through scratch and share a single allocation.
+func readStargazerRecord(r *bufio.Reader, stargazer *github.Stargazer, scratch *[]byte, keep func(id int64) bool) (bool, error) {
+	id, err := binary.ReadUvarint(r)
+	if err != nil {
+		return false, err
+	}
+	starredAt, err := binary.ReadVarint(r)
+	if err != nil {
+		return false, err
+	}
+	kept := keep == nil || keep(int64(id))
+
+	var ends [4]int
+	*scratch = (*scratch)[:0]
+	for i := range ends {
+		length, err := binary.ReadUvarint(r)
+		if err != nil {
+			return false, err
+		}
+		if !kept {
+			if _, err := r.Discard(int(length)); err != nil {
+				return false, err
+			}
+			continue
+		}
+		start := len(*scratch)
+		*scratch = slices.Grow(*scratch, int(length))[:start+int(length)]
+		if _, err := io.ReadFull(r, (*scratch)[start:]); err != nil {
+			return false, err
+		}
+		ends[i] = len(*scratch)
+	}
+	if !kept {
+		return false, nil
+	}
+
+	fields := string(*scratch)
+	stargazer.ID = int64(id)
+	if starredAt != 0 {
+		stargazer.StarredAt = time.Unix(0, starredAt)
+	}
+	stargazer.Login = fields[:ends[0]]
+	stargazer.NodeID = fields[ends[0]:ends[1]]
+	stargazer.AvatarURL = fields[ends[1]:ends[2]]
+	stargazer.HTMLURL = fields[ends[2]:ends[3]]
+	return true, nil
+}
+
+// writeUvarint writes v in the variable length encoding of encoding/binary
+func writeUvarint(w *bufio.Writer, v uint64) {
+	w.Write(binary.AppendUvarint(w.AvailableBuffer(), v))
+}
@@ -0,0 +1,368 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github-stars-notify/internal/github"
+)
+
+func TestStargazerFiles(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	ctx := context.Background()
+	if err := storage.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	starredAt := time.Date(2024, time.March, 10, 12, 30, 0, 0, time.UTC)
+	stargazers := []github.Stargazer{
+		{Login: "zed", ID: 900, NodeID: "node900", AvatarURL: "https://avatars.example/900", HTMLURL: "https://github.com/zed", StarredAt: starredAt},
+		{Login: "amy", ID: 5},
+		{Login: "bob", ID: 70000},
+	}
+	if err := storage.Save(ctx, "org", "repo", stargazers); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// The data file no longer holds the stargazers
+	data, err := os.ReadFile(storage.getFilename("org", "repo"))
+	if err != nil {
+		t.Fatalf("Failed to read data file: %v", err)
+	}
+	if strings.Contains(string(data), `"stargazers"`) {
+		t.Errorf("Expected the stargazers outside of the data file, got %s", data)
+	}
+
+	// Stargazers are loaded back in saved order with all their fields
+	repoData, err := storage.Load(ctx, "org", "repo")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(repoData.Stargazers) != len(stargazers) {
+		t.Fatalf("Expected %d stargazers, got %+v", len(stargazers), repoData.Stargazers)
+	}
+	for i, want := range stargazers {
+		got := repoData.Stargazers[i]
+		if got.Login != want.Login || got.ID != want.ID || got.NodeID != want.NodeID || got.AvatarURL != want.AvatarURL ||
+			got.HTMLURL != want.HTMLURL || !got.StarredAt.Equal(want.StarredAt) || got.StarredAt.IsZero() != want.StarredAt.IsZero() {
+			t.Errorf("Stargazer %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+
+	// Removed stargazers are diffed against the ID set and keep their profile
+	diff, err := storage.DiffStargazers(ctx, "org", "repo", []github.Stargazer{{Login: "amy", ID: 5}, {Login: "new", ID: 42}})
+	if err != nil {
+		t.Fatalf("DiffStargazers failed: %v", err)
+	}
+	if len(diff.Added) != 1 || diff.Added[0].Login != "new" {
+		t.Errorf("Expected new to be added, got %+v", diff.Added)
+	}
+	if len(diff.Removed) != 2 || diff.Removed[0].Login != "zed" || diff.Removed[0].HTMLURL != "https://github.com/zed" || diff.Removed[1].Login != "bob" {
+		t.Errorf("Expected zed and bob to be removed, got %+v", diff.Removed)
+	}
+
+	// Saving no stargazers empties the stored ones
+	if err := storage.Save(ctx, "org", "repo", nil); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if repoData, err := storage.Load(ctx, "org", "repo"); err != nil || len(repoData.Stargazers) != 0 {
+		t.Errorf("Expected no stargazers, got %+v (%v)", repoData, err)
+	}
+
+	// Damaged files are reported rather than read as empty
+	if err := os.WriteFile(storage.getStargazerIDsFilename("org", "repo"), []byte("garbage"), 0644); err != nil {
+		t.Fatalf("Failed to damage ID set: %v", err)
+	}
+	if _, err := storage.GetNewStargazers(ctx, "org", "repo", stargazers); err == nil {
+		t.Error("Expected an error for a damaged ID set")
+	}
+}
+
+func TestStargazerFilesAppend(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	ctx := context.Background()
+	if err := storage.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	if err := storage.Save(ctx, "org", "repo", []github.Stargazer{{Login: "amy", ID: 5}, {Login: "zed", ID: 900}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	recordsFile := storage.getStargazerRecordsFilename("org", "repo")
+	before, err := os.Stat(recordsFile)
+	if err != nil {
+		t.Fatalf("Failed to stat records: %v", err)
+	}
+
+	// A new stargazer is appended rather than rewriting the records
+	bob := github.Stargazer{Login: "bob", ID: 70}
+	if added, err := storage.AddStargazer(ctx, "org", "repo", bob); !added || err != nil {
+		t.Fatalf("Expected bob to be added, got %v (%v)", added, err)
+	}
+	after, err := os.Stat(recordsFile)
+	if err != nil {
+		t.Fatalf("Failed to stat records: %v", err)
+	}
+	if grown := after.Size() - before.Size(); grown != int64(len(appendStargazerRecord(nil, bob))) {
+		t.Errorf("Expected the records to grow by one record, grew by %d bytes", grown)
+	}
+	if added, err := storage.AddStargazer(ctx, "org", "repo", bob); added || err != nil {
+		t.Errorf("Expected bob to be known, got %v (%v)", added, err)
+	}
+
+	// A record cut short by a crash is ignored and overwritten by the next one
+	file, err := os.OpenFile(recordsFile, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("Failed to open records: %v", err)
+	}
+	file.Write([]byte{0x80})
+	file.Close()
+	if added, err := storage.AddStargazer(ctx, "org", "repo", github.Stargazer{Login: "cat", ID: 8}); !added || err != nil {
+		t.Fatalf("Expected cat to be added, got %v (%v)", added, err)
+	}
+
+	repoData, err := storage.Load(ctx, "org", "repo")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	var logins []string
+	for _, stargazer := range repoData.Stargazers {
+		logins = append(logins, stargazer.Login)
+	}
+	if strings.Join(logins, ",") != "amy,zed,bob,cat" {
+		t.Errorf("Expected the appended stargazers in order, got %v", logins)
+	}
+	if newStargazers, err := storage.GetNewStargazers(ctx, "org", "repo", repoData.Stargazers); len(newStargazers) != 0 || err != nil {
+		t.Errorf("Expected the ID set to hold the appended stargazers, got %+v (%v)", newStargazers, err)
+	}
+}
+
+func TestStargazerFilesDamagedHeader(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	ctx := context.Background()
+	if err := storage.Save(ctx, "org", "repo", []github.Stargazer{{Login: "amy", ID: 5}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Counts that cannot fit in the file are rejected before allocating
+	for _, filename := range []string{storage.getStargazerIDsFilename("org", "repo"), storage.getStargazerRecordsFilename("org", "repo")} {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", filename, err)
+		}
+		binary.LittleEndian.PutUint64(data[5:], 1<<60)
+		if err := os.WriteFile(filename, data, 0644); err != nil {
+			t.Fatalf("Failed to damage %s: %v", filename, err)
+		}
+	}
+	if _, err := storage.Load(ctx, "org", "repo"); err == nil {
+		t.Error("Expected an error for a damaged record count")
+	}
+	if _, err := storage.GetNewStargazers(ctx, "org", "repo", nil); err == nil {
+		t.Error("Expected an error for a damaged ID count")
+	}
+}
+
+func TestStargazerFilesFromInlineData(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	ctx := context.Background()
+	if err := storage.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	// A data file written before the compact stargazer files
+	data, err := json.Marshal(&RepoData{
+		Owner:      "org",
+		Repo:       "repo",
+		LastCheck:  time.Now(),
+		Stargazers: []github.Stargazer{{Login: "a", ID: 1}, {Login: "b", ID: 2}},
+		PreviousData: &RepoData{
+			LastCheck:  time.Now().Add(-time.Hour),
+			Stargazers: []github.Stargazer{{Login: "a", ID: 1}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal data: %v", err)
+	}
+	if err := os.WriteFile(storage.getFilename("org", "repo"), data, 0644); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+
+	newStargazers, err := storage.GetNewStargazers(ctx, "org", "repo", []github.Stargazer{{Login: "a", ID: 1}, {Login: "c", ID: 3}})
+	if err != nil || len(newStargazers) != 1 || newStargazers[0].Login != "c" {
+		t.Errorf("Expected c to be new, got %+v (%v)", newStargazers, err)
+	}
+	if summary, err := storage.LoadSummary(ctx, "org", "repo"); err != nil || summary.StargazerCount != 2 {
+		t.Errorf("Expected the inline stargazers counted, got %+v (%v)", summary, err)
+	}
+	if ids, err := storage.StargazerIDs(ctx, "org", "repo"); err != nil || len(ids) != 2 || !ids[2] {
+		t.Errorf("Expected the inline stargazer IDs, got %v (%v)", ids, err)
+	}
+
+	// An ID set written without its records, as by a crash in between, leaves
+	// the inline stargazers in use
+	if err := storage.writeStargazerIDsUnsafe("save", "org", "repo", []int64{1, 2, 3}); err != nil {
+		t.Fatalf("Failed to write ID set: %v", err)
+	}
+	if repoData, err := storage.Load(ctx, "org", "repo"); err != nil || len(repoData.Stargazers) != 2 {
+		t.Errorf("Expected the inline stargazers, got %+v (%v)", repoData, err)
+	}
+
+	// The next write moves the stargazers into the compact files
+	if err := storage.RecordMilestones(ctx, "org", "repo", []int{1}); err != nil {
+		t.Fatalf("RecordMilestones failed: %v", err)
+	}
+	if !storage.hasStargazerFilesUnsafe("org", "repo") {
+		t.Error("Expected the stargazers to be moved to the compact files")
+	}
+	repoData, err := storage.Load(ctx, "org", "repo")
+	if err != nil || len(repoData.Stargazers) != 2 || repoData.Stargazers[1].Login != "b" || len(repoData.Milestones) != 1 {
+		t.Errorf("Expected the stargazers and milestone to be kept, got %+v (%v)", repoData, err)
+	}
+}
+
+// benchmarkSizes are the stargazer counts of the storage benchmarks
+var benchmarkSizes = []int{100_000, 1_000_000}
+
+// benchmarkStargazers generates n stargazers with realistic IDs and profiles
+func benchmarkStargazers(n int) []github.Stargazer {
+	stargazers := make([]github.Stargazer, n)
+	starredAt := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := range stargazers {
+		id := int64(1_000_000 + i*97)
+		login := fmt.Sprintf("user%d", id)
+		stargazers[i] = github.Stargazer{
+			Login:     login,
+			ID:        id,
+			NodeID:    fmt.Sprintf("MDQ6VXNlcj%d", id),
+			AvatarURL: fmt.Sprintf("https://avatars.githubusercontent.com/u/%d?v=4", id),
+			HTMLURL:   "https://github.com/" + login,
+			StarredAt: starredAt.Add(time.Duration(i) * time.Minute),
+		}
+	}
+	return stargazers
+}
+
+// newBenchmarkStorage creates a file storage holding n saved stargazers
+func newBenchmarkStorage(b *testing.B, n int) (*FileStorage, []github.Stargazer) {
+	b.Helper()
+
+	storage := NewFileStorage(b.TempDir())
+	stargazers := benchmarkStargazers(n)
+	if err := storage.Save(context.Background(), "org", "repo", stargazers); err != nil {
+		b.Fatalf("Save failed: %v", err)
+	}
+	return storage, stargazers
+}
+
+// reportStargazerFileSize reports the disk space of the stargazers of the benchmark repository
+func reportStargazerFileSize(b *testing.B, storage *FileStorage) {
+	var size int64
+	for _, filename := range []string{
+		storage.getFilename("org", "repo"),
+		storage.getStargazerIDsFilename("org", "repo"),
+		storage.getStargazerRecordsFilename("org", "repo"),
+	} {
+		if info, err := os.Stat(filename); err == nil {
+			size += info.Size()
+		}
+	}
+	b.ReportMetric(float64(size), "disk-bytes")
+}
+
+func BenchmarkFileStorageSave(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			storage, stargazers := newBenchmarkStorage(b, n)
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := storage.Save(ctx, "org", "repo", stargazers); err != nil {
+					b.Fatalf("Save failed: %v", err)
+				}
+			}
+			b.StopTimer()
+			reportStargazerFileSize(b, storage)
+		})
+	}
+}
+
+func BenchmarkFileStorageLoad(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			storage, _ := newBenchmarkStorage(b, n)
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := storage.Load(ctx, "org", "repo"); err != nil {
+					b.Fatalf("Load failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFileStorageDiff(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			storage, stargazers := newBenchmarkStorage(b, n)
+			ctx := context.Background()
+
+			// A few new stars and a few removals, as in a regular check
+			current := append(stargazers[100:], benchmarkStargazers(n + 100)[n:]...)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				diff, err := storage.DiffStargazers(ctx, "org", "repo", current)
+				if err != nil || len(diff.Added) != 100 || len(diff.Removed) != 100 {
+					b.Fatalf("Unexpected diff: %d added, %d removed (%v)", len(diff.Added), len(diff.Removed), err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkInlineJSONSave measures the data files written before the compact
+// stargazer files, holding the stargazers of the last two checks, for comparison
+func BenchmarkInlineJSONSave(b *testing.B) {
+	for _, n := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			stargazers := benchmarkStargazers(n)
+			repoData := &RepoData{
+				Owner:        "org",
+				Repo:         "repo",
+				LastCheck:    time.Now(),
+				Stargazers:   stargazers,
+				PreviousData: &RepoData{LastCheck: time.Now(), Stargazers: stargazers},
+			}
+			filename := b.TempDir() + "/org_repo.json"
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				data, err := json.MarshalIndent(repoData, "", "  ")
+				if err != nil {
+					b.Fatalf("Marshal failed: %v", err)
+				}
+				if err := os.WriteFile(filename, data, 0644); err != nil {
+					b.Fatalf("Write failed: %v", err)
+				}
+			}
+			b.StopTimer()
+			if info, err := os.Stat(filename); err == nil {
+				b.ReportMetric(float64(info.Size()), "disk-bytes")
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 
@@ -21,32 +23,148 @@ type Storage interface {
 	// Load loads the stored data for a repository
 	Load(ctx context.Context, owner, repo string) (*RepoData, error)
 
+	// LoadSummary loads the last check time, stargazer count and milestones of a repository without its stargazers
+	LoadSummary(ctx context.Context, owner, repo string) (*RepoSummary, error)
+
+	// StargazerIDs returns the set of stored stargazer IDs of a repository
+	StargazerIDs(ctx context.Context, owner, repo string) (map[int64]bool, error)
+
 	// Save saves the data for a repository
 	Save(ctx context.Context, owner, repo string, stargazers []github.Stargazer) error
 
 	// GetNewStargazers compares current stargazers with previous data and returns new ones
 	GetNewStargazers(ctx context.Context, owner, repo string, currentStargazers []github.Stargazer) ([]github.Stargazer, error)
 
+	// DiffStargazers compares current stargazers with previous data and returns added and removed ones
+	DiffStargazers(ctx context.Context, owner, repo string, currentStargazers []github.Stargazer) (*StargazerDiff, error)
+
+	// AddStargazer records a single new stargazer, reporting false if it was already known
+	AddStargazer(ctx context.Context, owner, repo string, stargazer github.Stargazer) (bool, error)
+
+	// AppendStargazers records the new stargazers found by an incremental check and updates the last check time
+	AppendStargazers(ctx context.Context, owner, repo string, stargazers []github.Stargazer) error
+
+	// RecordRemovedStargazers records stargazers that removed their star
+	RecordRemovedStargazers(ctx context.Context, owner, repo string, removed []github.Stargazer) error
+
+	// StarEvents lists the events of the star event log of a repository matching query, oldest first
+	StarEvents(ctx context.Context, query StarEventQuery) ([]StarEvent, error)
+
+	// RecordMilestones records star milestones a repository has reached
+	RecordMilestones(ctx context.Context, owner, repo string, milestones []int) error
+
 	// GetLastCheckTime returns the last check time for a repository
 	GetLastCheckTime(ctx context.Context, owner, repo string) (time.Time, error)
 
+	// LoadForks loads the stored forks of a repository
+	LoadForks(ctx context.Context, owner, repo string) (*ForkData, error)
+
+	// SaveForks saves the forks of a repository
+	SaveForks(ctx context.Context, owner, repo string, forks []github.Repository) error
+
+	// GetNewForks compares current forks with previous data and returns new ones
+	GetNewForks(ctx context.Context, owner, repo string, currentForks []github.Repository) ([]github.Repository, error)
+
+	// LoadReleases loads the release IDs already seen for a repository
+	LoadReleases(ctx context.Context, owner, repo string) (*ReleaseData, error)
+
+	// SaveReleases marks releases of a repository as seen under the given filter
+	SaveReleases(ctx context.Context, owner, repo string, releases []github.Release, filter ReleaseFilter) error
+
+	// GetNewReleases returns the releases that have not been seen before
+	GetNewReleases(ctx context.Context, owner, repo string, currentReleases []github.Release) ([]github.Release, error)
+
+	// LoadFollowers loads the stored followers of a user or organization
+	LoadFollowers(ctx context.Context, login string) (*FollowerData, error)
+
+	// SaveFollowers saves the followers of a user or organization
+	SaveFollowers(ctx context.Context, login string, followers []github.Stargazer) error
+
+	// DiffFollowers compares current followers with previous data and returns added and removed ones
+	DiffFollowers(ctx context.Context, login string, currentFollowers []github.Stargazer) (*StargazerDiff, error)
+
+	// GetRepositoryState returns the stored identity and availability of a repository
+	GetRepositoryState(ctx context.Context, owner, repo string) (*RepositoryState, error)
+
+	// SetRepositoryState stores the identity and availability of a repository
+	SetRepositoryState(ctx context.Context, owner, repo string, state *RepositoryState) error
+
+	// RenameRepository moves the stored data of a repository to its new name
+	RenameRepository(ctx context.Context, owner, repo, newOwner, newRepo string) error
+
+	// GetUserProfile returns the cached profile of a user if it was fetched within ttl
+	GetUserProfile(ctx context.Context, key string, ttl time.Duration) (*github.UserProfile, bool, error)
+
+	// SaveUserProfile caches the profile of a user, dropping profiles older than ttl
+	SaveUserProfile(ctx context.Context, key string, profile *github.UserProfile, ttl time.Duration) error
+
+	// GetCachedResponse returns the cached GitHub response for a URL and marks it as recently used
+	GetCachedResponse(ctx context.Context, key string) (*github.CacheEntry, bool, error)
+
+	// SaveCachedResponse caches the GitHub response for a URL, dropping the least
+	// recently used responses beyond MaxCachedResponses
+	SaveCachedResponse(ctx context.Context, key string, entry *github.CacheEntry) error
+
 	// Close closes the storage and cleans up resources
 	Close() error
 }
 
-// RepoData represents stored data for a repository
+// MaxRemovedStargazers is the number of most recent removals kept in
+// RepoData.RemovedStargazers. The star event log keeps the full history.
+const MaxRemovedStargazers = 1000
+
+// RepoData represents stored data for a repository. The file storage keeps
+// Stargazers in compact stargazer files next to the JSON data file; data files
+// written before hold them inline, along with the previous check in PreviousData.
+// RemovedStargazers holds at most MaxRemovedStargazers of the latest removals.
 type RepoData struct {
-	Owner        string             `json:"owner"`
-	Repo         string             `json:"repo"`
-	LastCheck    time.Time          `json:"last_check"`
-	Stargazers   []github.Stargazer `json:"stargazers"`
-	PreviousData *RepoData          `json:"previous_data,omitempty"`
+	Owner             string             `json:"owner"`
+	Repo              string             `json:"repo"`
+	LastCheck         time.Time          `json:"last_check"`
+	Stargazers        []github.Stargazer `json:"stargazers,omitempty"`
+	RemovedStargazers []RemovedStargazer `json:"removed_stargazers,omitempty"`
+	Milestones        []int              `json:"milestones,omitempty"` // Star milestones already reached
+	PreviousData      *RepoData          `json:"previous_data,omitempty"`
+}
+
+// RepoSummary holds the stored state of a repository a check starts from,
+// without the profiles of its stargazers
+type RepoSummary struct {
+	Owner          string
+	Repo           string
+	LastCheck      time.Time
+	StargazerCount int
+	Milestones     []int // Star milestones already reached
+}
+
+// RemovedStargazer represents a stargazer that removed their star
+type RemovedStargazer struct {
+	github.Stargazer
+	RemovedAt time.Time `json:"removed_at"` // When the removal was detected
+}
+
+// StargazerDiff holds the stargazers added and removed since the last save
+type StargazerDiff struct {
+	Added   []github.Stargazer
+	Removed []github.Stargazer
+}
+
+// StargazerIDs returns the set of stored stargazer IDs for fast lookup
+func (d *RepoData) StargazerIDs() map[int64]bool {
+	ids := make(map[int64]bool, len(d.Stargazers))
+	for _, sg := range d.Stargazers {
+		ids[sg.ID] = true
+	}
+	return ids
 }
 
 // FileStorage implements Storage interface using file system
 type FileStorage struct {
-	dataDir string
-	mutex   sync.RWMutex
+	dataDir    string
+	mutex      sync.RWMutex
+	cacheMutex sync.Mutex // Guards the response cache, kept apart from the stored data
+
+	cachedResponses int // Responses kept by the response cache, see MaxCachedResponses
 }
 
 // NewFileStorage creates a new file-based storage instance
@@ -56,7 +174,8 @@ func NewFileStorage(dataDir string) *FileStorage {
 	}
 
 	return &FileStorage{
-		dataDir: dataDir,
+		dataDir:         dataDir,
+		cachedResponses: MaxCachedResponses,
 	}
 }
 
@@ -82,40 +201,174 @@ func (s *FileStorage) Load(ctx context.Context, owner, repo string) (*RepoData,
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return s.loadUnsafe(owner, repo)
+}
+
+// Save saves the data for a repository
+func (s *FileStorage) Save(ctx context.Context, owner, repo string, stargazers []github.Stargazer) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	filename := s.getFilename(owner, repo)
 
 	// Check if context is cancelled
 	if ctx.Err() != nil {
-		return nil, ctx.Err()
+		return ctx.Err()
 	}
 
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		// Return empty data if file doesn't exist
-		return &RepoData{
-			Owner:      owner,
-			Repo:       repo,
-			LastCheck:  time.Time{},
-			Stargazers: []github.Stargazer{},
-		}, nil
+	// Only the metadata is loaded, stored stargazers are diffed as a stream
+	existingData, err := s.loadMetadataUnsafe(owner, repo)
+	if err != nil {
+		return errors.NewStorageError("save", filename,
+			"failed to load existing data", err)
+	}
+	diff, err := s.diffStoredStargazersUnsafe(owner, repo, existingData, stargazers, false)
+	if err != nil {
+		return err
 	}
 
-	data, err := os.ReadFile(filename)
+	// Events are appended before the snapshot is written, so a failure in
+	// between repeats them on the next save rather than losing them
+	now := time.Now()
+	if err := s.appendStarEventsUnsafe(owner, repo, StarEventStarred, diff.Added, now); err != nil {
+		return err
+	}
+
+	if err := s.writeStargazersUnsafe(owner, repo, stargazers); err != nil {
+		return err
+	}
+	return s.writeUnsafe(owner, repo, &RepoData{
+		Owner:             owner,
+		Repo:              repo,
+		LastCheck:         now,
+		RemovedStargazers: existingData.RemovedStargazers,
+		Milestones:        existingData.Milestones,
+	})
+}
+
+// AddStargazer appends a stargazer to the stored data of a repository, for
+// stars received outside of a full check. It reports false if the stargazer
+// was already known. The last check time is left untouched. Compact files are
+// updated in place, data files holding the stargazers inline are rewritten.
+func (s *FileStorage) AddStargazer(ctx context.Context, owner, repo string, stargazer github.Stargazer) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	filename := s.getFilename(owner, repo)
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	if s.hasStargazerFilesUnsafe(owner, repo) {
+		ids, err := s.readStargazerIDsUnsafe("add_stargazer", owner, repo)
+		if err != nil {
+			return false, err
+		}
+		i, found := slices.BinarySearch(ids, stargazer.ID)
+		if found {
+			return false, nil
+		}
+		if err := s.appendStarEventsUnsafe(owner, repo, StarEventStarred, []github.Stargazer{stargazer}, time.Now()); err != nil {
+			return false, err
+		}
+
+		// The ID set is written first, as for a full write
+		if err := s.writeStargazerIDsUnsafe("add_stargazer", owner, repo, slices.Insert(ids, i, stargazer.ID)); err != nil {
+			return false, err
+		}
+		return true, s.appendStargazerRecordsUnsafe("add_stargazer", owner, repo, []github.Stargazer{stargazer})
+	}
+
+	repoData, err := s.loadUnsafe(owner, repo)
 	if err != nil {
-		return nil, errors.NewStorageError("load", filename,
-			"failed to read data file", err)
+		return false, errors.NewStorageError("add_stargazer", filename,
+			"failed to load existing data", err)
 	}
 
-	var repoData RepoData
-	if err := json.Unmarshal(data, &repoData); err != nil {
-		return nil, errors.NewStorageError("load", filename,
-			"failed to unmarshal data", err)
+	if repoData.StargazerIDs()[stargazer.ID] {
+		return false, nil
 	}
+	if err := s.appendStarEventsUnsafe(owner, repo, StarEventStarred, []github.Stargazer{stargazer}, time.Now()); err != nil {
+		return false, err
+	}
+	repoData.Stargazers = append(repoData.Stargazers, stargazer)
 
-	return &repoData, nil
+	return true, s.writeUnsafe(owner, repo, repoData)
 }
 
-// Save saves the data for a repository
-func (s *FileStorage) Save(ctx context.Context, owner, repo string, stargazers []github.Stargazer) error {
+// LoadSummary loads the last check time, stargazer count and milestones of a
+// repository. Only the header of the compact ID set is read for the count.
+func (s *FileStorage) LoadSummary(ctx context.Context, owner, repo string) (*RepoSummary, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	repoData, err := s.loadMetadataUnsafe(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	count := len(repoData.Stargazers)
+	if s.hasStargazerFilesUnsafe(owner, repo) {
+		if count, err = s.countStargazersUnsafe(owner, repo); err != nil {
+			return nil, err
+		}
+	}
+
+	return &RepoSummary{
+		Owner:          owner,
+		Repo:           repo,
+		LastCheck:      repoData.LastCheck,
+		StargazerCount: count,
+		Milestones:     repoData.Milestones,
+	}, nil
+}
+
+// StargazerIDs returns the set of stored stargazer IDs of a repository, read
+// from its compact ID set without the stargazer records
+func (s *FileStorage) StargazerIDs(ctx context.Context, owner, repo string) (map[int64]bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if !s.hasStargazerFilesUnsafe(owner, repo) {
+		repoData, err := s.loadMetadataUnsafe(owner, repo)
+		if err != nil {
+			return nil, err
+		}
+		return repoData.StargazerIDs(), nil
+	}
+
+	ids, err := s.readStargazerIDsUnsafe("load", owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set, nil
+}
+
+// AppendStargazers records the new stargazers found by an incremental check,
+// skipping the known ones, and updates the last check time. Compact files are
+// updated in place, data files holding the stargazers inline are converted.
+func (s *FileStorage) AppendStargazers(ctx context.Context, owner, repo string, stargazers []github.Stargazer) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
@@ -126,92 +379,234 @@ func (s *FileStorage) Save(ctx context.Context, owner, repo string, stargazers [
 		return ctx.Err()
 	}
 
-	// Load existing data to preserve as previous
-	existingData, err := s.loadUnsafe(owner, repo)
+	existingData, err := s.loadMetadataUnsafe(owner, repo)
 	if err != nil {
-		return errors.NewStorageError("save", filename,
+		return errors.NewStorageError("append_stargazers", filename,
 			"failed to load existing data", err)
 	}
+	compact := s.hasStargazerFilesUnsafe(owner, repo)
+
+	var ids []int64
+	if compact {
+		if ids, err = s.readStargazerIDsUnsafe("append_stargazers", owner, repo); err != nil {
+			return err
+		}
+	} else {
+		for id := range existingData.StargazerIDs() {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+	}
+
+	var added []github.Stargazer
+	seen := make(map[int64]bool, len(stargazers))
+	for _, stargazer := range stargazers {
+		if _, found := slices.BinarySearch(ids, stargazer.ID); found || seen[stargazer.ID] {
+			continue
+		}
+		seen[stargazer.ID] = true
+		added = append(added, stargazer)
+	}
+	for _, stargazer := range added {
+		ids = append(ids, stargazer.ID)
+	}
+	slices.Sort(ids)
 
-	// Create new data with current stargazers
-	newData := &RepoData{
-		Owner:      owner,
-		Repo:       repo,
-		LastCheck:  time.Now(),
-		Stargazers: stargazers,
+	now := time.Now()
+	if err := s.appendStarEventsUnsafe(owner, repo, StarEventStarred, added, now); err != nil {
+		return err
 	}
 
-	// Preserve previous data if it exists and has stargazers
-	if len(existingData.Stargazers) > 0 {
-		newData.PreviousData = &RepoData{
-			Owner:      existingData.Owner,
-			Repo:       existingData.Repo,
-			LastCheck:  existingData.LastCheck,
-			Stargazers: existingData.Stargazers,
+	if compact {
+		// The ID set is written first, as for a full write
+		if err := s.writeStargazerIDsUnsafe("append_stargazers", owner, repo, ids); err != nil {
+			return err
 		}
+		if err := s.appendStargazerRecordsUnsafe("append_stargazers", owner, repo, added); err != nil {
+			return err
+		}
+	} else if err := s.writeStargazersUnsafe(owner, repo, append(existingData.Stargazers, added...)); err != nil {
+		return err
+	}
+
+	return s.writeUnsafe(owner, repo, &RepoData{
+		Owner:             owner,
+		Repo:              repo,
+		LastCheck:         now,
+		RemovedStargazers: existingData.RemovedStargazers,
+		Milestones:        existingData.Milestones,
+	})
+}
+
+// RecordRemovedStargazers records stargazers that removed their star, dropping
+// the oldest removals beyond MaxRemovedStargazers
+func (s *FileStorage) RecordRemovedStargazers(ctx context.Context, owner, repo string, removed []github.Stargazer) error {
+	if len(removed) == 0 {
+		return nil
 	}
 
-	// Marshal and save
-	data, err := json.MarshalIndent(newData, "", "  ")
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	filename := s.getFilename(owner, repo)
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	repoData, err := s.loadMetadataUnsafe(owner, repo)
 	if err != nil {
-		return errors.NewStorageError("save", filename,
-			"failed to marshal data", err)
+		return errors.NewStorageError("record_removed", filename,
+			"failed to load existing data", err)
 	}
 
-	// Write to temporary file first, then rename (atomic write)
-	tempFile := filename + ".tmp"
-	if err := os.WriteFile(tempFile, data, 0644); err != nil {
-		return errors.NewStorageError("save", tempFile,
-			"failed to write temporary file", err)
+	now := time.Now()
+	if err := s.appendStarEventsUnsafe(owner, repo, StarEventUnstarred, removed, now); err != nil {
+		return err
+	}
+	for _, sg := range removed {
+		repoData.RemovedStargazers = append(repoData.RemovedStargazers, RemovedStargazer{
+			Stargazer: sg,
+			RemovedAt: now,
+		})
+	}
+	if excess := len(repoData.RemovedStargazers) - MaxRemovedStargazers; excess > 0 {
+		repoData.RemovedStargazers = append([]RemovedStargazer(nil), repoData.RemovedStargazers[excess:]...)
 	}
 
-	if err := os.Rename(tempFile, filename); err != nil {
-		// Clean up temporary file on error
-		os.Remove(tempFile)
-		return errors.NewStorageError("save", filename,
-			"failed to rename temporary file", err)
+	return s.writeUnsafe(owner, repo, repoData)
+}
+
+// RecordMilestones records star milestones a repository has reached so they are
+// never announced twice
+func (s *FileStorage) RecordMilestones(ctx context.Context, owner, repo string, milestones []int) error {
+	if len(milestones) == 0 {
+		return nil
 	}
 
-	return nil
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	filename := s.getFilename(owner, repo)
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	repoData, err := s.loadMetadataUnsafe(owner, repo)
+	if err != nil {
+		return errors.NewStorageError("record_milestones", filename,
+			"failed to load existing data", err)
+	}
+
+	reached := make(map[int]bool, len(repoData.Milestones))
+	for _, milestone := range repoData.Milestones {
+		reached[milestone] = true
+	}
+	for _, milestone := range milestones {
+		if !reached[milestone] {
+			reached[milestone] = true
+			repoData.Milestones = append(repoData.Milestones, milestone)
+		}
+	}
+	sort.Ints(repoData.Milestones)
+
+	return s.writeUnsafe(owner, repo, repoData)
+}
+
+// writeUnsafe writes repository data without acquiring a lock (for internal
+// use). Stargazers, unless nil, are written to the compact stargazer files and
+// the data file keeps the rest, which moves stargazers of older data files there.
+func (s *FileStorage) writeUnsafe(owner, repo string, repoData *RepoData) error {
+	if repoData.Stargazers != nil {
+		if err := s.writeStargazersUnsafe(owner, repo, repoData.Stargazers); err != nil {
+			return err
+		}
+	}
+
+	metadata := *repoData
+	metadata.Owner, metadata.Repo = owner, repo
+	metadata.Stargazers = nil
+	metadata.PreviousData = nil
+	return writeJSONFile("save", s.getFilename(owner, repo), &metadata)
 }
 
 // GetNewStargazers compares current stargazers with previous data and returns new ones
 func (s *FileStorage) GetNewStargazers(ctx context.Context, owner, repo string, currentStargazers []github.Stargazer) ([]github.Stargazer, error) {
-	repoData, err := s.Load(ctx, owner, repo)
+	diff, err := s.DiffStargazers(ctx, owner, repo, currentStargazers)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load repo data: %w", err)
+		return nil, err
 	}
+	return diff.Added, nil
+}
 
-	// If no previous data, all stargazers are new
-	if len(repoData.Stargazers) == 0 {
-		return currentStargazers, nil
-	}
+// DiffStargazers compares current stargazers with previous data and returns added and removed ones.
+// currentStargazers must be the complete list for removals to be meaningful.
+func (s *FileStorage) DiffStargazers(ctx context.Context, owner, repo string, currentStargazers []github.Stargazer) (*StargazerDiff, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 
 	// Check if context is cancelled
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
 
-	// Create a map of existing stargazers for fast lookup
-	existingStargazers := make(map[int64]bool)
-	for _, sg := range repoData.Stargazers {
-		existingStargazers[sg.ID] = true
+	repoData, err := s.loadMetadataUnsafe(owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load repo data: %w", err)
+	}
+
+	return s.diffStoredStargazersUnsafe(owner, repo, repoData, currentStargazers, true)
+}
+
+// diffStargazers computes the symmetric difference between previous and current stargazers
+func diffStargazers(previous, current []github.Stargazer) *StargazerDiff {
+	added, removed := diffByID(previous, current, func(sg github.Stargazer) int64 { return sg.ID })
+	return &StargazerDiff{Added: added, Removed: removed}
+}
+
+// diffByID computes the items added to and removed from previous, identified by id
+func diffByID[T any](previous, current []T, id func(T) int64) (added, removed []T) {
+	// Create maps of IDs for fast lookup
+	previousIDs := make(map[int64]bool, len(previous))
+	for _, item := range previous {
+		previousIDs[id(item)] = true
+	}
+	currentIDs := make(map[int64]bool, len(current))
+	for _, item := range current {
+		currentIDs[id(item)] = true
+	}
+
+	// Find new items
+	for _, item := range current {
+		if !previousIDs[id(item)] {
+			added = append(added, item)
+		}
 	}
 
-	// Find new stargazers
-	var newStargazers []github.Stargazer
-	for _, sg := range currentStargazers {
-		if !existingStargazers[sg.ID] {
-			newStargazers = append(newStargazers, sg)
+	// Find removed items
+	for _, item := range previous {
+		if !currentIDs[id(item)] {
+			removed = append(removed, item)
 		}
 	}
 
-	return newStargazers, nil
+	return added, removed
 }
 
 // GetLastCheckTime returns the last check time for a repository
 func (s *FileStorage) GetLastCheckTime(ctx context.Context, owner, repo string) (time.Time, error) {
-	repoData, err := s.Load(ctx, owner, repo)
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Check if context is cancelled
+	if ctx.Err() != nil {
+		return time.Time{}, ctx.Err()
+	}
+
+	repoData, err := s.loadMetadataUnsafe(owner, repo)
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -231,15 +626,34 @@ func (s *FileStorage) getFilename(owner, repo string) string {
 
 // loadUnsafe loads data without acquiring a lock (for internal use)
 func (s *FileStorage) loadUnsafe(owner, repo string) (*RepoData, error) {
+	repoData, err := s.loadMetadataUnsafe(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.hasStargazerFilesUnsafe(owner, repo) {
+		repoData.Stargazers, err = s.readStargazersUnsafe(owner, repo, nil)
+		if err != nil {
+			return nil, err
+		}
+	} else if repoData.Stargazers == nil {
+		repoData.Stargazers = []github.Stargazer{}
+	}
+
+	return repoData, nil
+}
+
+// loadMetadataUnsafe loads the data file of a repository without its compact
+// stargazer files and without acquiring a lock (for internal use). Stargazers
+// is only set for data files holding them inline.
+func (s *FileStorage) loadMetadataUnsafe(owner, repo string) (*RepoData, error) {
 	filename := s.getFilename(owner, repo)
 
 	if _, err := os.Stat(filename); os.IsNotExist(err) {
 		// Return empty data if file doesn't exist
 		return &RepoData{
-			Owner:      owner,
-			Repo:       repo,
-			LastCheck:  time.Time{},
-			Stargazers: []github.Stargazer{},
+			Owner: owner,
+			Repo:  repo,
 		}, nil
 	}
 
@@ -262,6 +676,12 @@ func (s *FileStorage) loadUnsafe(owner, repo string) (*RepoData, error) {
 type StorageConfig struct {
 	Type string
 	Path string
+
+	// PostgreSQL connection and pool settings
+	DSN             string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
 }
 
 // NewStorageFromConfig creates a storage instance from configuration
@@ -269,8 +689,51 @@ func NewStorageFromConfig(cfg StorageConfig) (Storage, error) {
 	switch cfg.Type {
 	case "file", "":
 		return NewFileStorage(cfg.Path), nil
+	case "sqlite":
+		return NewSQLiteStorage(cfg.Path), nil
+	case "postgres":
+		if cfg.DSN == "" {
+			return nil, errors.NewStorageError("create", "", "postgres storage requires a DSN", nil)
+		}
+		return NewPostgresStorage(PostgresConfig{
+			DSN:             cfg.DSN,
+			MaxOpenConns:    cfg.MaxOpenConns,
+			MaxIdleConns:    cfg.MaxIdleConns,
+			ConnMaxLifetime: cfg.ConnMaxLifetime,
+		}), nil
 	default:
 		return nil, errors.NewStorageError("create", "",
 			fmt.Sprintf("unsupported storage type: %s", cfg.Type), nil)
 	}
 }
+
+// writeJSONFile atomically writes v as indented JSON to filename, creating its
+// directory if needed. op names the storage operation in errors.
+func writeJSONFile(op, filename string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return errors.NewStorageError(op, filepath.Dir(filename),
+			"failed to create data directory", err)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.NewStorageError(op, filename,
+			"failed to marshal data", err)
+	}
+
+	// Write to temporary file first, then rename (atomic write)
+	tempFile := filename + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return errors.NewStorageError(op, tempFile,
+			"failed to write temporary file", err)
+	}
+
+	if err := os.Rename(tempFile, filename); err != nil {
+		// Clean up temporary file on error
+		os.Remove(tempFile)
+		return errors.NewStorageError(op, filename,
+			"failed to rename temporary file", err)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
 	"time"
@@ -127,6 +128,25 @@ func TestStorageFromConfig(t *testing.T) {
 		t.Error("Expected storage to be created")
 	}
 
+	sqlite, err := NewStorageFromConfig(StorageConfig{Type: "sqlite", Path: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewStorageFromConfig failed for sqlite: %v", err)
+	}
+	if _, ok := sqlite.(*SQLiteStorage); !ok {
+		t.Errorf("Expected a SQLite storage, got %T", sqlite)
+	}
+
+	postgres, err := NewStorageFromConfig(StorageConfig{Type: "postgres", DSN: "postgres://localhost/stars"})
+	if err != nil {
+		t.Fatalf("NewStorageFromConfig failed for postgres: %v", err)
+	}
+	if _, ok := postgres.(*PostgresStorage); !ok {
+		t.Errorf("Expected a PostgreSQL storage, got %T", postgres)
+	}
+	if _, err := NewStorageFromConfig(StorageConfig{Type: "postgres"}); err == nil {
+		t.Error("Expected error for postgres storage without DSN")
+	}
+
 	// Test unsupported type
 	badCfg := StorageConfig{
 		Type: "unsupported",
@@ -136,3 +156,473 @@ func TestStorageFromConfig(t *testing.T) {
 		t.Error("Expected error for unsupported storage type")
 	}
 }
+
+// testResponseCache checks that a storage caches GitHub responses and drops
+// the least recently used ones beyond a size of 3
+func testResponseCache(t *testing.T, storage Storage) {
+	cache := NewResponseCache(storage)
+
+	if _, ok := cache.Get("https://api.github.com/repos/a/a"); ok {
+		t.Error("Expected empty cache")
+	}
+
+	entry := &github.CacheEntry{
+		ETag: `"abc"`,
+		Link: `<https://api.github.com/x?page=2>; rel="next"`,
+		Body: []byte(`[]`),
+	}
+	if err := cache.Set("https://api.github.com/repos/a/a", entry); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	cached, ok := cache.Get("https://api.github.com/repos/a/a")
+	if !ok {
+		t.Fatal("Expected cached entry")
+	}
+	if cached.ETag != entry.ETag || cached.Link != entry.Link || string(cached.Body) != "[]" {
+		t.Errorf("Cached entry mismatch: %+v", cached)
+	}
+
+	// Using a response keeps it over the ones stored after it
+	for _, repo := range []string{"b", "c"} {
+		if err := cache.Set("https://api.github.com/repos/a/"+repo, entry); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+	if _, ok := cache.Get("https://api.github.com/repos/a/a"); !ok {
+		t.Fatal("Expected cached entry")
+	}
+	if err := cache.Set("https://api.github.com/repos/a/d", entry); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	for repo, kept := range map[string]bool{"a": true, "b": false, "c": true, "d": true} {
+		if _, ok := cache.Get("https://api.github.com/repos/a/" + repo); ok != kept {
+			t.Errorf("Expected %s to be kept: %v, got %v", repo, kept, ok)
+		}
+	}
+}
+
+func TestFileResponseCache(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	storage.cachedResponses = 3
+	testResponseCache(t, storage)
+}
+
+// testUserProfiles checks that a storage caches user profiles for their TTL
+func testUserProfiles(t *testing.T, storage Storage) {
+	ctx := context.Background()
+
+	if _, ok, err := storage.GetUserProfile(ctx, "alice", time.Hour); ok || err != nil {
+		t.Errorf("Expected empty cache, got %v (%v)", ok, err)
+	}
+
+	profile := &github.UserProfile{Login: "alice", Company: "Acme Corp", Followers: 2300}
+	if err := storage.SaveUserProfile(ctx, "alice", profile, time.Hour); err != nil {
+		t.Fatalf("SaveUserProfile failed: %v", err)
+	}
+
+	// Lookups are case-insensitive
+	cached, ok, err := storage.GetUserProfile(ctx, "Alice", time.Hour)
+	if err != nil || !ok {
+		t.Fatalf("Expected cached profile (%v)", err)
+	}
+	if cached.Company != "Acme Corp" || cached.Followers != 2300 {
+		t.Errorf("Cached profile mismatch: %+v", cached)
+	}
+
+	// Expired profiles are not returned, and dropped by the next save
+	if _, ok, _ := storage.GetUserProfile(ctx, "alice", -time.Second); ok {
+		t.Error("Expected expired profile to be ignored")
+	}
+	if err := storage.SaveUserProfile(ctx, "bob", &github.UserProfile{Login: "bob"}, 0); err != nil {
+		t.Fatalf("SaveUserProfile failed: %v", err)
+	}
+	if _, ok, _ := storage.GetUserProfile(ctx, "alice", time.Hour); ok {
+		t.Error("Expected expired profile to be dropped")
+	}
+	if _, ok, _ := storage.GetUserProfile(ctx, "bob", time.Hour); !ok {
+		t.Error("Expected the saved profile to be kept")
+	}
+}
+
+// testRemovedStargazersCap checks that a storage keeps only the latest
+// MaxRemovedStargazers removals in the repository data
+func testRemovedStargazersCap(t *testing.T, storage Storage) {
+	ctx := context.Background()
+
+	var removed []github.Stargazer
+	for i := 1; i <= MaxRemovedStargazers+5; i++ {
+		removed = append(removed, github.Stargazer{Login: fmt.Sprintf("user%d", i), ID: int64(i)})
+	}
+	if err := storage.RecordRemovedStargazers(ctx, "org", "repo", removed[:10]); err != nil {
+		t.Fatalf("RecordRemovedStargazers failed: %v", err)
+	}
+	if err := storage.RecordRemovedStargazers(ctx, "org", "repo", removed[10:]); err != nil {
+		t.Fatalf("RecordRemovedStargazers failed: %v", err)
+	}
+
+	repoData, err := storage.Load(ctx, "org", "repo")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(repoData.RemovedStargazers) != MaxRemovedStargazers {
+		t.Fatalf("Expected %d removed stargazers, got %d", MaxRemovedStargazers, len(repoData.RemovedStargazers))
+	}
+	if first, last := repoData.RemovedStargazers[0], repoData.RemovedStargazers[MaxRemovedStargazers-1]; first.ID != 6 || last.ID != int64(MaxRemovedStargazers+5) {
+		t.Errorf("Expected the latest removals in order, got %d to %d", first.ID, last.ID)
+	}
+
+	// The event log keeps every removal
+	events, err := storage.StarEvents(ctx, StarEventQuery{Owner: "org", Repo: "repo"})
+	if err != nil {
+		t.Fatalf("StarEvents failed: %v", err)
+	}
+	if len(events) != MaxRemovedStargazers+5 {
+		t.Errorf("Expected every removal in the event log, got %d events", len(events))
+	}
+}
+
+// testStargazerSummary checks that a storage reports the stargazer count and
+// IDs of a repository and appends the stargazers of an incremental check
+func testStargazerSummary(t *testing.T, storage Storage) {
+	ctx := context.Background()
+
+	summary, err := storage.LoadSummary(ctx, "org", "repo")
+	if err != nil || !summary.LastCheck.IsZero() || summary.StargazerCount != 0 {
+		t.Fatalf("Expected an empty summary, got %+v (%v)", summary, err)
+	}
+
+	if err := storage.Save(ctx, "org", "repo", []github.Stargazer{{Login: "a", ID: 1}, {Login: "b", ID: 2}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := storage.RecordMilestones(ctx, "org", "repo", []int{1}); err != nil {
+		t.Fatalf("RecordMilestones failed: %v", err)
+	}
+	summary, err = storage.LoadSummary(ctx, "org", "repo")
+	if err != nil || summary.LastCheck.IsZero() || summary.StargazerCount != 2 || len(summary.Milestones) != 1 {
+		t.Errorf("Expected 2 stargazers and a milestone, got %+v (%v)", summary, err)
+	}
+
+	// Known and repeated stargazers are skipped
+	lastCheck := summary.LastCheck
+	err = storage.AppendStargazers(ctx, "org", "repo", []github.Stargazer{{Login: "b", ID: 2}, {Login: "c", ID: 3}, {Login: "c", ID: 3}})
+	if err != nil {
+		t.Fatalf("AppendStargazers failed: %v", err)
+	}
+	ids, err := storage.StargazerIDs(ctx, "org", "repo")
+	if err != nil || len(ids) != 3 || !ids[3] {
+		t.Errorf("Expected IDs 1 to 3, got %v (%v)", ids, err)
+	}
+	summary, err = storage.LoadSummary(ctx, "org", "repo")
+	if err != nil || summary.StargazerCount != 3 || !summary.LastCheck.After(lastCheck) {
+		t.Errorf("Expected 3 stargazers checked again, got %+v (%v)", summary, err)
+	}
+
+	repoData, err := storage.Load(ctx, "org", "repo")
+	if err != nil || len(repoData.Stargazers) != 3 || repoData.Stargazers[2].Login != "c" {
+		t.Errorf("Expected c appended, got %+v (%v)", repoData, err)
+	}
+	events, err := storage.StarEvents(ctx, StarEventQuery{Owner: "org", Repo: "repo"})
+	if err != nil || len(events) != 3 || events[2].Stargazer.Login != "c" {
+		t.Errorf("Expected a star event for c, got %+v (%v)", events, err)
+	}
+}
+
+func TestFileStargazerSummary(t *testing.T) {
+	testStargazerSummary(t, NewFileStorage(t.TempDir()))
+}
+
+func TestFileRemovedStargazersCap(t *testing.T) {
+	testRemovedStargazersCap(t, NewFileStorage(t.TempDir()))
+}
+
+func TestFileUserProfiles(t *testing.T) {
+	dataDir := t.TempDir()
+	testUserProfiles(t, NewFileStorage(dataDir))
+
+	// Profiles survive restarts
+	if _, ok, err := NewFileStorage(dataDir).GetUserProfile(context.Background(), "bob", time.Hour); !ok || err != nil {
+		t.Errorf("Expected cached profile after a restart (%v)", err)
+	}
+}
+
+func TestDiffStargazers(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	ctx := context.Background()
+
+	if err := storage.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	alice := github.Stargazer{Login: "alice", ID: 1}
+	bob := github.Stargazer{Login: "bob", ID: 2}
+	carol := github.Stargazer{Login: "carol", ID: 3}
+
+	if err := storage.Save(ctx, "org", "repo", []github.Stargazer{alice, bob}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	diff, err := storage.DiffStargazers(ctx, "org", "repo", []github.Stargazer{alice, carol})
+	if err != nil {
+		t.Fatalf("DiffStargazers failed: %v", err)
+	}
+	if len(diff.Added) != 1 || diff.Added[0].Login != "carol" {
+		t.Errorf("Expected carol to be added, got %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Login != "bob" {
+		t.Errorf("Expected bob to be removed, got %+v", diff.Removed)
+	}
+
+	// Removed stargazers are kept across saves
+	if err := storage.RecordRemovedStargazers(ctx, "org", "repo", diff.Removed); err != nil {
+		t.Fatalf("RecordRemovedStargazers failed: %v", err)
+	}
+	if err := storage.Save(ctx, "org", "repo", []github.Stargazer{alice, carol}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	repoData, err := storage.Load(ctx, "org", "repo")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(repoData.RemovedStargazers) != 1 || repoData.RemovedStargazers[0].Login != "bob" {
+		t.Fatalf("Expected bob to be recorded as removed, got %+v", repoData.RemovedStargazers)
+	}
+	if repoData.RemovedStargazers[0].RemovedAt.IsZero() {
+		t.Error("Expected removal time to be recorded")
+	}
+}
+
+func TestRecordMilestones(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	ctx := context.Background()
+
+	if err := storage.Save(ctx, "org", "repo", []github.Stargazer{{Login: "alice", ID: 1}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := storage.RecordMilestones(ctx, "org", "repo", []int{500, 100}); err != nil {
+		t.Fatalf("RecordMilestones failed: %v", err)
+	}
+	if err := storage.RecordMilestones(ctx, "org", "repo", []int{100}); err != nil {
+		t.Fatalf("RecordMilestones failed: %v", err)
+	}
+
+	// Reached milestones are kept across saves
+	if err := storage.Save(ctx, "org", "repo", []github.Stargazer{{Login: "alice", ID: 1}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	repoData, err := storage.Load(ctx, "org", "repo")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(repoData.Milestones) != 2 || repoData.Milestones[0] != 100 || repoData.Milestones[1] != 500 {
+		t.Errorf("Expected milestones [100 500], got %v", repoData.Milestones)
+	}
+}
+
+func TestGetNewReleases(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	ctx := context.Background()
+
+	v1 := github.Release{ID: 1, TagName: "v1.0.0"}
+	v2 := github.Release{ID: 2, TagName: "v2.0.0"}
+
+	if err := storage.SaveReleases(ctx, "org", "repo", []github.Release{v1}, ReleaseFilter{}); err != nil {
+		t.Fatalf("SaveReleases failed: %v", err)
+	}
+
+	newReleases, err := storage.GetNewReleases(ctx, "org", "repo", []github.Release{v2, v1})
+	if err != nil {
+		t.Fatalf("GetNewReleases failed: %v", err)
+	}
+	if len(newReleases) != 1 || newReleases[0].TagName != "v2.0.0" {
+		t.Errorf("Expected v2.0.0 to be new, got %+v", newReleases)
+	}
+
+	// Seen releases are kept when they drop out of the fetched page
+	if err := storage.SaveReleases(ctx, "org", "repo", []github.Release{v2}, ReleaseFilter{Prereleases: true}); err != nil {
+		t.Fatalf("SaveReleases failed: %v", err)
+	}
+	newReleases, err = storage.GetNewReleases(ctx, "org", "repo", []github.Release{v1})
+	if err != nil {
+		t.Fatalf("GetNewReleases failed: %v", err)
+	}
+	if len(newReleases) != 0 {
+		t.Errorf("Expected no new releases, got %+v", newReleases)
+	}
+
+	// The filter of the last save is recorded
+	releaseData, err := storage.LoadReleases(ctx, "org", "repo")
+	if err != nil || releaseData.Filter == nil || *releaseData.Filter != (ReleaseFilter{Prereleases: true}) {
+		t.Errorf("Expected the prerelease filter to be recorded, got %+v (%v)", releaseData, err)
+	}
+}
+
+func TestDiffFollowers(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	ctx := context.Background()
+
+	alice := github.Stargazer{Login: "alice", ID: 1}
+	bob := github.Stargazer{Login: "bob", ID: 2}
+
+	if err := storage.SaveFollowers(ctx, "my-org", []github.Stargazer{alice}); err != nil {
+		t.Fatalf("SaveFollowers failed: %v", err)
+	}
+
+	diff, err := storage.DiffFollowers(ctx, "my-org", []github.Stargazer{alice, bob})
+	if err != nil {
+		t.Fatalf("DiffFollowers failed: %v", err)
+	}
+	if len(diff.Added) != 1 || diff.Added[0].Login != "bob" || len(diff.Removed) != 0 {
+		t.Errorf("Expected bob to be a new follower, got %+v", diff)
+	}
+
+	followerData, err := storage.LoadFollowers(ctx, "my-org")
+	if err != nil {
+		t.Fatalf("LoadFollowers failed: %v", err)
+	}
+	if len(followerData.Followers) != 1 || followerData.LastCheck.IsZero() {
+		t.Errorf("Unexpected follower data: %+v", followerData)
+	}
+}
+
+func TestGetNewForks(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	ctx := context.Background()
+
+	first := github.Repository{ID: 1, FullName: "alice/repo"}
+	second := github.Repository{ID: 2, FullName: "bob/repo"}
+
+	// Forks are stored apart from stargazers of the same repository
+	if err := storage.Save(ctx, "org", "repo", []github.Stargazer{{Login: "carol", ID: 1}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := storage.SaveForks(ctx, "org", "repo", []github.Repository{first}); err != nil {
+		t.Fatalf("SaveForks failed: %v", err)
+	}
+
+	newForks, err := storage.GetNewForks(ctx, "org", "repo", []github.Repository{first, second})
+	if err != nil {
+		t.Fatalf("GetNewForks failed: %v", err)
+	}
+	if len(newForks) != 1 || newForks[0].FullName != "bob/repo" {
+		t.Errorf("Expected bob/repo to be new, got %+v", newForks)
+	}
+
+	forkData, err := storage.LoadForks(ctx, "org", "repo")
+	if err != nil {
+		t.Fatalf("LoadForks failed: %v", err)
+	}
+	if len(forkData.Forks) != 1 || forkData.LastCheck.IsZero() {
+		t.Errorf("Unexpected fork data: %+v", forkData)
+	}
+
+	repoData, err := storage.Load(ctx, "org", "repo")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(repoData.Stargazers) != 1 {
+		t.Errorf("Expected stargazers to be untouched, got %+v", repoData.Stargazers)
+	}
+}
+
+func TestRenameRepository(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	ctx := context.Background()
+
+	if err := storage.Save(ctx, "org", "old", []github.Stargazer{{Login: "alice", ID: 1}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := storage.SaveForks(ctx, "org", "old", []github.Repository{{ID: 2, FullName: "bob/old"}}); err != nil {
+		t.Fatalf("SaveForks failed: %v", err)
+	}
+	if err := storage.SetRepositoryState(ctx, "org", "old", &RepositoryState{ID: 42, NotFoundCount: 1}); err != nil {
+		t.Fatalf("SetRepositoryState failed: %v", err)
+	}
+
+	if err := storage.RenameRepository(ctx, "org", "old", "new-org", "new"); err != nil {
+		t.Fatalf("RenameRepository failed: %v", err)
+	}
+
+	repoData, err := storage.Load(ctx, "new-org", "new")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(repoData.Stargazers) != 1 {
+		t.Errorf("Expected stargazers to move to the new name, got %+v", repoData.Stargazers)
+	}
+	forkData, err := storage.LoadForks(ctx, "new-org", "new")
+	if err != nil {
+		t.Fatalf("LoadForks failed: %v", err)
+	}
+	if len(forkData.Forks) != 1 {
+		t.Errorf("Expected forks to move to the new name, got %+v", forkData.Forks)
+	}
+	if oldData, _ := storage.Load(ctx, "org", "old"); len(oldData.Stargazers) != 0 {
+		t.Errorf("Expected no data left under the old name, got %+v", oldData.Stargazers)
+	}
+
+	oldState, err := storage.GetRepositoryState(ctx, "org", "old")
+	if err != nil {
+		t.Fatalf("GetRepositoryState failed: %v", err)
+	}
+	if oldState.RenamedTo != "new-org/new" {
+		t.Errorf("Expected the rename to be recorded, got %+v", oldState)
+	}
+	newState, err := storage.GetRepositoryState(ctx, "new-org", "new")
+	if err != nil {
+		t.Fatalf("GetRepositoryState failed: %v", err)
+	}
+	if newState.ID != 42 || newState.RenamedTo != "" {
+		t.Errorf("Expected the state to move to the new name, got %+v", newState)
+	}
+
+	// A name with data files of its own keeps them, and none of the files of
+	// the old name are moved next to them
+	if err := storage.Save(ctx, "org", "other", []github.Stargazer{{Login: "carol", ID: 3}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := storage.SaveReleases(ctx, "org", "taken", []github.Release{{ID: 4}}, ReleaseFilter{}); err != nil {
+		t.Fatalf("SaveReleases failed: %v", err)
+	}
+	if err := storage.RenameRepository(ctx, "org", "other", "org", "taken"); err != nil {
+		t.Fatalf("RenameRepository failed: %v", err)
+	}
+	if takenData, _ := storage.Load(ctx, "org", "taken"); len(takenData.Stargazers) != 0 {
+		t.Errorf("Expected no stargazers moved next to the existing files, got %+v", takenData.Stargazers)
+	}
+	if otherData, _ := storage.Load(ctx, "org", "other"); len(otherData.Stargazers) != 1 {
+		t.Errorf("Expected the files of the old name left in place, got %+v", otherData.Stargazers)
+	}
+	if releaseData, _ := storage.LoadReleases(ctx, "org", "taken"); len(releaseData.ReleaseIDs) != 1 {
+		t.Errorf("Expected the releases of the new name kept, got %+v", releaseData)
+	}
+}
+
+func TestAddStargazer(t *testing.T) {
+	storage := NewFileStorage(t.TempDir())
+	ctx := context.Background()
+
+	if err := storage.Save(ctx, "org", "repo", []github.Stargazer{{Login: "alice", ID: 1}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	before, _ := storage.GetLastCheckTime(ctx, "org", "repo")
+
+	added, err := storage.AddStargazer(ctx, "org", "repo", github.Stargazer{Login: "bob", ID: 2})
+	if err != nil || !added {
+		t.Fatalf("Expected bob to be added, got %v, %v", added, err)
+	}
+	if added, _ := storage.AddStargazer(ctx, "org", "repo", github.Stargazer{Login: "bob", ID: 2}); added {
+		t.Error("Expected a known stargazer not to be added twice")
+	}
+
+	repoData, err := storage.Load(ctx, "org", "repo")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(repoData.Stargazers) != 2 || !repoData.LastCheck.Equal(before) {
+		t.Errorf("Unexpected repository data: %+v", repoData)
+	}
+}